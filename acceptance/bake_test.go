@@ -157,7 +157,7 @@ var _ = Describe("bake command", func() {
 				Expect(err).NotTo(HaveOccurred())
 			}
 
-			if f.Name == "migrations/v1/some_migration.js" {
+			if f.Name == "migrations/v1/201603011200_some_migration.js" {
 				archivedMigration3, err = f.Open()
 				Expect(err).NotTo(HaveOccurred())
 			}
@@ -399,7 +399,7 @@ var _ = Describe("bake command", func() {
 					Expect(err).NotTo(HaveOccurred())
 				}
 
-				if f.Name == "migrations/v1/some_migration.js" {
+				if f.Name == "migrations/v1/201603011200_some_migration.js" {
 					archivedMigration3, err = f.Open()
 					Expect(err).NotTo(HaveOccurred())
 				}
@@ -55,7 +55,7 @@ provides_product_versions:
 - name: cf
   version: 1.7.0.0
 rank: 90
-some_releases:
+releases:
 - file: diego-release-0.1467.1-3215.4.0.tgz
   name: diego
   version: 0.1467.1
@@ -83,7 +83,7 @@ label: Pivotal Elastic Runtime
 metadata_version: "1.7"
 name: cool-product-name
 product_version: 1.2.3
-some_releases:
+releases:
 - file: diego-release-0.1467.1-3215.4.0.tgz
   name: diego
   version: 0.1467.1
@@ -105,7 +105,7 @@ label: Pivotal Elastic Runtime
 metadata_version: "1.7"
 name: cool-product-name
 product_version: 1.2.3
-some_releases:
+releases:
 - file: cf-release-235.0.0-3215.4.0.tgz
   name: cf
   version: "235"
@@ -124,7 +124,7 @@ label: Pivotal Elastic Runtime
 metadata_version: "1.7"
 name: cool-product-name
 product_version: 1.2.3
-some_releases:
+releases:
 - file: cf-release-235.0.0-3215.4.0.tgz
   name: cf
   version: "235"
@@ -18,14 +18,24 @@ Usage: kiln [options] <command> [<args>]
 
 Commands:
   bake                    bakes a tile
+  check-upgrade           reports upgrade hazards between two tiles
   compile-built-releases  compiles built releases and uploads them
+  diff                    prints the metadata changes between two tiles
   fetch                   fetches releases
+  generate-migration      generates a migration for renamed property blueprints
+  generate-osl            generates an open source license document from release tarballs
   help                    prints this usage information
+  inspect                 prints a summary of a tile
+  lint                    checks property references in tile metadata
+  manifest                renders a BOSH deployment manifest from tile metadata
   publish                 publish tile on Pivnet
+  sbom                    generates a software bill of materials
   sync-with-local         update the Kilnfile.lock based on local releases
+  test-migrations         runs migrations against fixture installations
   update-release          bumps a release to a new version
   update-stemcell         updates Kilnfile.lock with stemcell info
   upload-release          uploads a BOSH release to an s3 release_source
+  verify-signature        verifies the signature of a tile
   version                 prints the kiln release version
 `
 
@@ -37,7 +47,12 @@ Usage: kiln [options] bake [<args>]
   --version, -v  bool  prints the kiln release version (default: false)
 
 Command Arguments:
+  --allow-env                        bool               lets the env template helper read environment variables
+  --allow-part-overrides             bool               lets parts and releases in later directories replace ones with the same name in earlier directories
   --bosh-variables-directory, -vd    string (variadic)  path to a directory containing BOSH variables
+  --checksum                         string (variadic)  writes a checksum of the output file computed while it is written: sha1, sha256 or sha512
+  --checksum-format                  string             format of the checksum files: hex or shasum (default: hex)
+  --config, -c                       string             path to a bake config file
   --embed, -e                        string (variadic)  path to files to include in the tile /embed directory
   --forms-directory, -f              string (variadic)  path to a directory containing forms
   --icon, -i                         string             path to icon file
@@ -47,15 +62,24 @@ Command Arguments:
   --metadata, -m                     string (required)  path to the metadata file
   --metadata-only, -mo               bool               don't build a tile, output the metadata to stdout
   --migrations-directory, -md        string (variadic)  path to a directory containing migrations
+  --ops-file, -op                    string (variadic)  path to an ops file to apply to the interpolated metadata
   --output-file, -o                  string             path to where the tile will be output
+  --partials-directory, -pa          string (variadic)  path to a directory containing partials
+  --previous-tile                    string             path to the previously shipped tile, migrations it does not contain must sort after the ones it does
+  --print-variables                  bool               prints each template variable and where its value came from, then exits
   --properties-directory, -pd        string (variadic)  path to a directory containing property blueprints
+  --provenance                       bool               embeds a document in the tile recording the kiln version, git commit, arguments, variables and input file hashes of the bake
   --releases-directory, -rd          string (variadic)  path to a directory containing release tarballs
   --runtime-configs-directory, -rcd  string (variadic)  path to a directory containing runtime configs
-  --sha256                           bool               calculates a SHA256 checksum of the output file
+  --sbom                             string             embeds a software bill of materials in the tile: cyclonedx or spdx
+  --sha256                           bool               calculates a SHA256 checksum of the output file, the same as --checksum sha256
+  --signing-key                      string             path to an ed25519 or PGP private key used to write a detached signature of the output file
   --stemcell-tarball, -st            string             deprecated -- path to a stemcell tarball  (NOTE: mutually exclusive with --kilnfile)
   --stemcells-directory, -sd         string (variadic)  path to a directory containing stemcells  (NOTE: mutually exclusive with --kilnfile or --stemcell-tarball)
+  --strict                           bool               fails on undefined variables and on variables or parts that are never used
   --stub-releases, -sr               bool               skips importing release tarballs into the tile
   --variable, -vr                    string (variadic)  key value pairs of variables to interpolate
+  --variable-yaml, -vy               string (variadic)  key value pairs of variables to interpolate, with the value parsed as YAML
   --variables-file, -vf              string (variadic)  path to a file containing variables to interpolate
   --version, -v                      string             version of the tile
 `
@@ -0,0 +1,62 @@
+package builder
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// Digests maps each checksum algorithm requested in WriteInput.Checksums, such
+// as "sha256", to the hex encoded digest of the written tile.
+type Digests map[string]string
+
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// hashingWriter hashes everything written through it, so the digests of the
+// tile are known as soon as the zip is closed without reading it back.
+type hashingWriter struct {
+	io.Writer
+	hashes map[string]hash.Hash
+}
+
+func validateChecksumAlgorithms(algorithms []string) error {
+	for _, algorithm := range algorithms {
+		if _, ok := checksumAlgorithms[algorithm]; !ok {
+			return fmt.Errorf("unsupported checksum algorithm %q, expected one of sha1, sha256 or sha512", algorithm)
+		}
+	}
+
+	return nil
+}
+
+func newHashingWriter(writer io.Writer, algorithms []string) *hashingWriter {
+	hashes := map[string]hash.Hash{}
+	writers := []io.Writer{writer}
+
+	for _, algorithm := range algorithms {
+		if _, ok := hashes[algorithm]; ok {
+			continue
+		}
+
+		hashes[algorithm] = checksumAlgorithms[algorithm]()
+		writers = append(writers, hashes[algorithm])
+	}
+
+	return &hashingWriter{Writer: io.MultiWriter(writers...), hashes: hashes}
+}
+
+func (w *hashingWriter) digests() Digests {
+	digests := Digests{}
+	for algorithm, h := range w.hashes {
+		digests[algorithm] = fmt.Sprintf("%x", h.Sum(nil))
+	}
+
+	return digests
+}
@@ -0,0 +1,156 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var (
+	executionErrorPattern = regexp.MustCompile(`^template: [^:]*:(\d+):\d+: executing "[^"]*" at <(.*?)>: `)
+	actionPattern         = regexp.MustCompile(`\$\(((?:"(?:[^"\\]|\\.)*"|[^)"])*)\)`)
+)
+
+// InterpolationError reports the file and line of a template helper call that
+// failed during interpolation. Chain lists the metadata file and the part
+// lookups that led to that file, outermost first.
+type InterpolationError struct {
+	Chain []string
+	File  string
+	Line  int
+	Call  string
+	Err   error
+}
+
+func (e *InterpolationError) Error() string {
+	location := e.File
+	if location == "" && len(e.Chain) > 0 {
+		location = e.Chain[len(e.Chain)-1]
+	}
+
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, e.Line)
+	}
+
+	message := fmt.Sprintf("%s: %s", location, e.Err)
+	if e.Call != "" {
+		message = fmt.Sprintf("%s: %s: %s", location, e.Call, e.Err)
+	}
+
+	if len(e.Chain) > 1 {
+		message = fmt.Sprintf("%s (in %s)", message, strings.Join(e.Chain, " → "))
+	}
+
+	return message
+}
+
+func (e *InterpolationError) Unwrap() error {
+	return e.Err
+}
+
+// interpolationFrame is one level of nested interpolation: the metadata file
+// itself, or a part pulled in by a helper such as $( job "web" ). All frames
+// of an interpolation share used, which records the helper calls made. The
+// frame of a partial also carries the parameters it was called with.
+type interpolationFrame struct {
+	parent      *interpolationFrame
+	description string
+	path        string
+	params      map[string]interface{}
+	used        map[string]bool
+}
+
+func (f *interpolationFrame) child(description string) *interpolationFrame {
+	f.used[description] = true
+
+	return &interpolationFrame{parent: f, description: description, used: f.used}
+}
+
+func (f *interpolationFrame) chain() []string {
+	if f.parent == nil {
+		return []string{f.description}
+	}
+
+	return append(f.parent.chain(), f.description)
+}
+
+// locateExecutionError turns a template execution error into an
+// InterpolationError pointing at the failing helper call. Errors that already
+// carry a location from a nested part are returned unchanged.
+func locateExecutionError(frame *interpolationFrame, templateYAML []byte, err error) *InterpolationError {
+	var nested *InterpolationError
+	if errors.As(err, &nested) {
+		return nested
+	}
+
+	interpolationErr := &InterpolationError{
+		Chain: frame.chain(),
+		File:  frame.path,
+		Err:   err,
+	}
+
+	match := executionErrorPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return interpolationErr
+	}
+
+	interpolationErr.Err = errors.New(strings.TrimPrefix(err.Error(), match[0]))
+
+	var execErr template.ExecError
+	if errors.As(err, &execErr) {
+		if cause := errors.Unwrap(execErr.Err); cause != nil {
+			interpolationErr.Err = cause
+		}
+	}
+
+	context := strings.TrimSuffix(match[2], "...")
+	interpolationErr.Call = fmt.Sprintf("$( %s )", match[2])
+
+	// Only the outermost template is parsed from the original file, so its line
+	// numbers can be trusted. Parts are re-marshalled before they are parsed,
+	// so their calls are found by searching the part file instead. Only YAML
+	// files are searched; other parts, like releases, point at tarballs.
+	source, searchFrom := templateYAML, 0
+	if frame.parent == nil {
+		searchFrom, _ = strconv.Atoi(match[1])
+	} else {
+		if ext := filepath.Ext(frame.path); ext != ".yml" && ext != ".yaml" {
+			return interpolationErr
+		}
+
+		source, err = ioutil.ReadFile(frame.path)
+		if err != nil {
+			return interpolationErr
+		}
+	}
+
+	if line, call := findCall(source, context, searchFrom); line > 0 {
+		interpolationErr.Line = line
+		interpolationErr.Call = call
+	}
+
+	return interpolationErr
+}
+
+// findCall returns the line and text of the first $( ) action in source that
+// contains context. When line is positive only that line is searched.
+func findCall(source []byte, context string, line int) (int, string) {
+	for i, text := range strings.Split(string(source), "\n") {
+		if line > 0 && i+1 != line {
+			continue
+		}
+
+		for _, action := range actionPattern.FindAllStringSubmatch(text, -1) {
+			if strings.Contains(strings.Join(strings.Fields(action[1]), " "), context) {
+				return i + 1, action[0]
+			}
+		}
+	}
+
+	return 0, ""
+}
@@ -0,0 +1,136 @@
+package builder_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/pivotal-cf/kiln/builder"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("interpolation errors", func() {
+	var (
+		tempDir      string
+		metadataPath string
+		jobPath      string
+		input        InterpolateInput
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		metadataPath = filepath.Join(tempDir, "base.yml")
+		jobPath = filepath.Join(tempDir, "jobs", "some-job.yml")
+
+		Expect(os.Mkdir(filepath.Join(tempDir, "jobs"), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(jobPath, []byte(`---
+name: some-job
+templates:
+- name: some-template
+  manifest: |
+    port: $( property "missing-property" )
+`), 0644)).To(Succeed())
+
+		input = InterpolateInput{
+			MetadataPath:       metadataPath,
+			PropertyBlueprints: map[string]interface{}{},
+			Jobs: map[string]interface{}{
+				"some-job": Part{
+					File: "some-job.yml",
+					Path: jobPath,
+					Name: "some-job",
+					Metadata: Metadata{
+						"name": "some-job",
+						"templates": []interface{}{
+							Metadata{
+								"name":     "some-template",
+								"manifest": "port: $( property \"missing-property\" )\n",
+							},
+						},
+					},
+				},
+			},
+			InstanceGroups: map[string]interface{}{
+				"some-instance-group": Part{
+					File: "some-instance-group.yml",
+					Path: filepath.Join(tempDir, "some-instance-group.yml"),
+					Name: "some-instance-group",
+					Metadata: Metadata{
+						"name":      "some-instance-group",
+						"templates": []interface{}{`$( job "some-job" )`},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("points at the failing call in the part file and lists the nesting chain", func() {
+		_, err := NewInterpolator().Interpolate(input, []byte(`---
+name: some-product
+job_types:
+- $( instance_group "some-instance-group" )
+`))
+		Expect(err).To(MatchError(`template execution failed: ` + jobPath + `:6: $( property "missing-property" ): ` +
+			`could not find property blueprint with name 'missing-property' ` +
+			`(in base.yml → instance_group "some-instance-group" → job "some-job")`))
+
+		var interpolationErr *InterpolationError
+		Expect(errors.As(err, &interpolationErr)).To(BeTrue())
+		Expect(interpolationErr.File).To(Equal(jobPath))
+		Expect(interpolationErr.Line).To(Equal(6))
+		Expect(interpolationErr.Chain).To(Equal([]string{"base.yml", `instance_group "some-instance-group"`, `job "some-job"`}))
+	})
+
+	It("uses the template line for calls in the metadata file", func() {
+		_, err := NewInterpolator().Interpolate(input, []byte(`---
+name: some-product
+property_blueprints:
+- $(   property   "missing-property" )
+`))
+		Expect(err).To(MatchError(`template execution failed: ` + metadataPath + `:4: $(   property   "missing-property" ): ` +
+			`could not find property blueprint with name 'missing-property'`))
+	})
+
+	Context("when the part file cannot be read", func() {
+		It("reports the call without a line", func() {
+			Expect(os.Remove(jobPath)).To(Succeed())
+
+			_, err := NewInterpolator().Interpolate(input, []byte(`job_types: [$( job "some-job" )]`))
+			Expect(err).To(MatchError(`template execution failed: ` + jobPath + `: $( property "missing-property" ): ` +
+				`could not find property blueprint with name 'missing-property' (in base.yml → job "some-job")`))
+		})
+	})
+
+	Context("when the part is not a YAML file", func() {
+		It("does not read the file", func() {
+			releasePath := filepath.Join(tempDir, "some-release-1.2.3.tgz")
+			Expect(ioutil.WriteFile(releasePath, []byte(`version: $( property "missing-property" )`), 0644)).To(Succeed())
+
+			input.ReleaseManifests = map[string]interface{}{
+				"some-release": Part{
+					File:     "some-release-1.2.3.tgz",
+					Path:     releasePath,
+					Name:     "some-release",
+					Metadata: Metadata{"version": `$( property "missing-property" )`},
+				},
+			}
+
+			_, err := NewInterpolator().Interpolate(input, []byte(`releases: [$( release "some-release" )]`))
+
+			var interpolationErr *InterpolationError
+			Expect(errors.As(err, &interpolationErr)).To(BeTrue())
+			Expect(interpolationErr.File).To(Equal(releasePath))
+			Expect(interpolationErr.Line).To(Equal(0))
+		})
+	})
+})
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"text/template"
 
 	yamlConverter "github.com/ghodss/yaml"
@@ -27,7 +29,11 @@ type InterpolateInput struct {
 	Jobs               map[string]interface{}
 	PropertyBlueprints map[string]interface{}
 	RuntimeConfigs     map[string]interface{}
+	Partials           map[string]interface{}
 	StubReleases       bool
+	MetadataPath       string
+	AllowEnv           bool
+	Strict             bool
 }
 
 func NewInterpolator() Interpolator {
@@ -35,11 +41,29 @@ func NewInterpolator() Interpolator {
 }
 
 func (i Interpolator) Interpolate(input InterpolateInput, templateYAML []byte) ([]byte, error) {
-	interpolatedYAML, err := i.interpolate(input, templateYAML)
+	description := "metadata"
+	if input.MetadataPath != "" {
+		description = filepath.Base(input.MetadataPath)
+	}
+
+	frame := &interpolationFrame{
+		description: description,
+		path:        input.MetadataPath,
+		used:        map[string]bool{},
+	}
+
+	interpolatedYAML, err := i.interpolate(input, frame, templateYAML)
 	if err != nil {
 		return nil, err
 	}
 
+	if input.Strict {
+		err = checkUnusedInputs(input, frame.used)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	prettyMetadata, err := i.prettyPrint(interpolatedYAML)
 	if err != nil {
 		return nil, err // un-tested
@@ -48,7 +72,7 @@ func (i Interpolator) Interpolate(input InterpolateInput, templateYAML []byte) (
 	return prettyMetadata, nil
 }
 
-func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) ([]byte, error) {
+func (i Interpolator) interpolate(input InterpolateInput, frame *interpolationFrame, templateYAML []byte) ([]byte, error) {
 	templateHelpers := template.FuncMap{
 		"bosh_variable": func(key string) (string, error) {
 			if input.BOSHVariables == nil {
@@ -58,7 +82,7 @@ func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) (
 			if !ok {
 				return "", fmt.Errorf("could not find bosh variable with key '%s'", key)
 			}
-			return i.interpolateValueIntoYAML(input, val)
+			return i.interpolateValueIntoYAML(input, frame, fmt.Sprintf("bosh_variable %q", key), val)
 		},
 		"form": func(key string) (string, error) {
 			if input.FormTypes == nil {
@@ -69,7 +93,13 @@ func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) (
 				return "", fmt.Errorf("could not find form with key '%s'", key)
 			}
 
-			return i.interpolateValueIntoYAML(input, val)
+			return i.interpolateValueIntoYAML(input, frame, fmt.Sprintf("form %q", key), val)
+		},
+		"param": func(name string) (string, error) {
+			return i.partialParameter(input, frame, name)
+		},
+		"partial": func(name string, params ...interface{}) (string, error) {
+			return i.interpolatePartial(input, frame, name, params)
 		},
 		"property": func(name string) (string, error) {
 			if input.PropertyBlueprints == nil {
@@ -79,7 +109,7 @@ func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) (
 			if !ok {
 				return "", fmt.Errorf("could not find property blueprint with name '%s'", name)
 			}
-			return i.interpolateValueIntoYAML(input, val)
+			return i.interpolateValueIntoYAML(input, frame, fmt.Sprintf("property %q", name), val)
 		},
 		"regexReplaceAll": func(regex, inputString, replaceString string) (string, error) {
 			re, err := regexp.Compile(regex)
@@ -106,7 +136,7 @@ func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) (
 				}
 			}
 
-			return i.interpolateValueIntoYAML(input, val)
+			return i.interpolateValueIntoYAML(input, frame, fmt.Sprintf("release %q", name), val)
 		},
 		"stemcell": func(osname ...string) (string, error) {
 			if input.StemcellManifest == nil && len(input.StemcellManifests) == 0 {
@@ -122,32 +152,33 @@ func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) (
 			}
 
 			if len(osname) > 0 {
-				return i.interpolateValueIntoYAML(input, input.StemcellManifests[osname[0]])
+				return i.interpolateValueIntoYAML(input, frame, fmt.Sprintf("stemcell %q", osname[0]), input.StemcellManifests[osname[0]])
 			}
 
 			if len(input.StemcellManifests) == 1 {
 				for _, stemcell := range input.StemcellManifests {
-					return i.interpolateValueIntoYAML(input, stemcell)
+					return i.interpolateValueIntoYAML(input, frame, "stemcell", stemcell)
 				}
 			}
 
-			return i.interpolateValueIntoYAML(input, input.StemcellManifest)
+			return i.interpolateValueIntoYAML(input, frame, "stemcell", input.StemcellManifest)
 		},
 		"version": func() (string, error) {
 			if input.Version == "" {
 				return "", errors.New("--version must be specified")
 			}
-			return i.interpolateValueIntoYAML(input, input.Version)
+			return i.interpolateValueIntoYAML(input, frame, "version", input.Version)
 		},
 		"variable": func(key string) (string, error) {
 			if input.Variables == nil {
 				return "", errors.New("--variable or --variables-file must be specified")
 			}
-			val, ok := input.Variables[key]
+			val, ok := lookupVariable(input.Variables, key)
 			if !ok {
 				return "", fmt.Errorf("could not find variable with key '%s'", key)
 			}
-			return i.interpolateValueIntoYAML(input, val)
+			frame.used[fmt.Sprintf("variable %q", strings.SplitN(key, ".", 2)[0])] = true
+			return i.interpolateValueIntoYAML(input, frame, fmt.Sprintf("variable %q", key), val)
 		},
 		"icon": func() (string, error) {
 			if input.IconImage == "" {
@@ -164,7 +195,7 @@ func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) (
 				return "", fmt.Errorf("could not find instance_group with name '%s'", name)
 			}
 
-			return i.interpolateValueIntoYAML(input, val)
+			return i.interpolateValueIntoYAML(input, frame, fmt.Sprintf("instance_group %q", name), val)
 		},
 		"job": func(name string) (string, error) {
 			if input.Jobs == nil {
@@ -175,7 +206,7 @@ func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) (
 				return "", fmt.Errorf("could not find job with name '%s'", name)
 			}
 
-			return i.interpolateValueIntoYAML(input, val)
+			return i.interpolateValueIntoYAML(input, frame, fmt.Sprintf("job %q", name), val)
 		},
 		"runtime_config": func(name string) (string, error) {
 			if input.RuntimeConfigs == nil {
@@ -186,7 +217,7 @@ func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) (
 				return "", fmt.Errorf("could not find runtime_config with name '%s'", name)
 			}
 
-			return i.interpolateValueIntoYAML(input, val)
+			return i.interpolateValueIntoYAML(input, frame, fmt.Sprintf("runtime_config %q", name), val)
 		},
 		"select": func(field, input string) (string, error) {
 			object := map[string]interface{}{}
@@ -210,33 +241,98 @@ func (i Interpolator) interpolate(input InterpolateInput, templateYAML []byte) (
 		},
 	}
 
-	t, err := template.New("metadata").
+	for name, helper := range templateFunctions(input) {
+		templateHelpers[name] = helper
+	}
+
+	t := template.New("metadata").
 		Delims("$(", ")").
-		Funcs(templateHelpers).
-		Parse(string(templateYAML))
+		Funcs(templateHelpers)
+
+	if input.Strict {
+		t = t.Option("missingkey=error")
+	}
+
+	t, err := t.Parse(string(templateYAML))
 
 	if err != nil {
-		return nil, fmt.Errorf("template parsing failed: %s", err)
+		if frame.parent == nil {
+			return nil, fmt.Errorf("template parsing failed: %s", err)
+		}
+
+		return nil, &InterpolationError{
+			Chain: frame.chain(),
+			File:  frame.path,
+			Err:   fmt.Errorf("template parsing failed: %s", err),
+		}
 	}
 
+	recordVariableReferences(t.Tree.Root, frame.used)
+
 	var buffer bytes.Buffer
 	err = t.Execute(&buffer, input.Variables)
 	if err != nil {
-		return nil, fmt.Errorf("template execution failed: %s", err)
+		interpolationErr := locateExecutionError(frame, templateYAML, err)
+		if frame.parent == nil {
+			return nil, fmt.Errorf("template execution failed: %w", interpolationErr)
+		}
+
+		return nil, interpolationErr
 	}
 
 	return buffer.Bytes(), nil
 }
 
-func (i Interpolator) interpolateValueIntoYAML(input InterpolateInput, val interface{}) (string, error) {
+// lookupVariable returns the variable named key. A key that is not a top-level
+// variable is treated as a dotted path into nested maps, so "a.b" finds the
+// value set by --variable a.b=c.
+func lookupVariable(variables map[string]interface{}, key string) (interface{}, bool) {
+	if val, ok := variables[key]; ok {
+		return val, true
+	}
+
+	path := strings.Split(key, ".")
+	val, ok := variables[path[0]]
+	for _, segment := range path[1:] {
+		if !ok {
+			break
+		}
+
+		switch m := val.(type) {
+		case map[interface{}]interface{}:
+			val, ok = m[segment]
+		case map[string]interface{}:
+			val, ok = m[segment]
+		default:
+			ok = false
+		}
+	}
+
+	return val, ok
+}
+
+// interpolateValueIntoYAML renders val as an inline YAML value. Errors from
+// templates inside val are nested under the helper call described by
+// description and, when val is a Part, point at the part's file.
+func (i Interpolator) interpolateValueIntoYAML(input InterpolateInput, parent *interpolationFrame, description string, val interface{}) (string, error) {
+	frame := parent.child(description)
+	if part, ok := val.(Part); ok {
+		val = part.Metadata
+		frame.path = part.Path
+	}
+
+	return i.interpolateFrameIntoYAML(input, frame, val)
+}
+
+func (i Interpolator) interpolateFrameIntoYAML(input InterpolateInput, frame *interpolationFrame, val interface{}) (string, error) {
 	initialYAML, err := yaml.Marshal(val)
 	if err != nil {
 		return "", err // should never happen
 	}
 
-	interpolatedYAML, err := i.interpolate(input, initialYAML)
+	interpolatedYAML, err := i.interpolate(input, frame, initialYAML)
 	if err != nil {
-		return "", fmt.Errorf("unable to interpolate value: %s", err)
+		return "", fmt.Errorf("unable to interpolate value: %w", err)
 	}
 
 	inlinedYAML, err := i.yamlMarshalOneLine(interpolatedYAML)
@@ -389,6 +389,29 @@ some_runtime_configs:
 		})
 	})
 
+	Context("when a variable key is dotted", func() {
+		It("finds the variable nested under the dotted path", func() {
+			interpolator := NewInterpolator()
+			input.Variables = map[string]interface{}{
+				"network": map[interface{}]interface{}{"name": "some-network"},
+			}
+			interpolatedYAML, err := interpolator.Interpolate(input, []byte(`network: $( variable "network.name" )`))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(interpolatedYAML).To(HelpfullyMatchYAML(`network: some-network`))
+		})
+
+		It("returns an error when the path does not exist", func() {
+			interpolator := NewInterpolator()
+			input.Variables = map[string]interface{}{
+				"network": map[interface{}]interface{}{"name": "some-network"},
+			}
+			_, err := interpolator.Interpolate(input, []byte(`network: $( variable "network.missing" )`))
+
+			Expect(err).To(MatchError(ContainSubstring("could not find variable with key 'network.missing'")))
+		})
+	})
+
 	Context("failure cases", func() {
 		Context("when the requested form name is not found", func() {
 			It("returns an error", func() {
@@ -434,7 +457,8 @@ some_runtime_configs:
 				interpolator := NewInterpolator()
 				_, err := interpolator.Interpolate(input, []byte(templateYAML))
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("unable to interpolate value"))
+				Expect(err.Error()).To(ContainSubstring(`form "some-form": template parsing failed`))
+				Expect(err.Error()).To(ContainSubstring(`(in metadata → form "some-form")`))
 			})
 		})
 
@@ -19,6 +19,7 @@ type MetadataPartsDirectoryReader struct {
 
 type Part struct {
 	File     string
+	Path     string
 	Name     string
 	Metadata interface{}
 }
@@ -85,7 +86,7 @@ func (r MetadataPartsDirectoryReader) readMetadataRecursivelyFromDir(path string
 			}
 		}
 
-		parts, err = r.readMetadataIntoParts(pathpkg.Base(filePath), vars, parts)
+		parts, err = r.readMetadataIntoParts(filePath, vars, parts)
 		if err != nil {
 			return fmt.Errorf("file '%s' with top-level key '%s' has an invalid format: %s", filePath, r.topLevelKey, err)
 		}
@@ -96,7 +97,7 @@ func (r MetadataPartsDirectoryReader) readMetadataRecursivelyFromDir(path string
 	return parts, err
 }
 
-func (r MetadataPartsDirectoryReader) readMetadataIntoParts(fileName string, vars interface{}, parts []Part) ([]Part, error) {
+func (r MetadataPartsDirectoryReader) readMetadataIntoParts(filePath string, vars interface{}, parts []Part) ([]Part, error) {
 	switch v := vars.(type) {
 	case []interface{}:
 		for _, item := range v {
@@ -105,7 +106,7 @@ func (r MetadataPartsDirectoryReader) readMetadataIntoParts(fileName string, var
 				return []Part{}, fmt.Errorf("metadata item '%v' must be a map", item)
 			}
 
-			part, err := r.buildPartFromMetadata(i, fileName)
+			part, err := r.buildPartFromMetadata(i, filePath)
 			if err != nil {
 				return []Part{}, err
 			}
@@ -113,7 +114,7 @@ func (r MetadataPartsDirectoryReader) readMetadataIntoParts(fileName string, var
 			parts = append(parts, part)
 		}
 	case map[interface{}]interface{}:
-		part, err := r.buildPartFromMetadata(v, fileName)
+		part, err := r.buildPartFromMetadata(v, filePath)
 		if err != nil {
 			return []Part{}, err
 		}
@@ -125,7 +126,7 @@ func (r MetadataPartsDirectoryReader) readMetadataIntoParts(fileName string, var
 	return parts, nil
 }
 
-func (r MetadataPartsDirectoryReader) buildPartFromMetadata(metadata map[interface{}]interface{}, legacyFilename string) (Part, error) {
+func (r MetadataPartsDirectoryReader) buildPartFromMetadata(metadata map[interface{}]interface{}, filePath string) (Part, error) {
 	name, ok := metadata["alias"].(string)
 	if !ok {
 		name, ok = metadata["name"].(string)
@@ -135,7 +136,7 @@ func (r MetadataPartsDirectoryReader) buildPartFromMetadata(metadata map[interfa
 	}
 	delete(metadata, "alias")
 
-	return Part{File: legacyFilename, Name: name, Metadata: metadata}, nil
+	return Part{File: pathpkg.Base(filePath), Path: filePath, Name: name, Metadata: metadata}, nil
 }
 
 func (r MetadataPartsDirectoryReader) orderWithOrderFromFile(path string, parts []Part) ([]Part, error) {
@@ -55,6 +55,7 @@ type: password
 			Expect(vars).To(Equal([]Part{
 				{
 					File: "vars-file-1.yml",
+					Path: filepath.Join(tempDir, "vars-file-1.yml"),
 					Name: "variable-1",
 					Metadata: map[interface{}]interface{}{
 						"name": "variable-1",
@@ -63,6 +64,7 @@ type: password
 				},
 				{
 					File: "vars-file-1.yml",
+					Path: filepath.Join(tempDir, "vars-file-1.yml"),
 					Name: "variable-2-alias",
 					Metadata: map[interface{}]interface{}{
 						"name": "variable-2",
@@ -71,6 +73,7 @@ type: password
 				},
 				{
 					File: "vars-file-2.yml",
+					Path: filepath.Join(tempDir, "vars-file-2.yml"),
 					Name: "variable-3",
 					Metadata: map[interface{}]interface{}{
 						"name": "variable-3",
@@ -171,6 +174,7 @@ variables:
 				Expect(vars).To(Equal([]Part{
 					{
 						File: "vars-file-1.yml",
+						Path: filepath.Join(tempDir, "vars-file-1.yml"),
 						Name: "variable-1",
 						Metadata: map[interface{}]interface{}{
 							"name": "variable-1",
@@ -179,6 +183,7 @@ variables:
 					},
 					{
 						File: "vars-file-1.yml",
+						Path: filepath.Join(tempDir, "vars-file-1.yml"),
 						Name: "variable-2",
 						Metadata: map[interface{}]interface{}{
 							"name": "variable-2",
@@ -187,6 +192,7 @@ variables:
 					},
 					{
 						File: "vars-file-2.yml",
+						Path: filepath.Join(tempDir, "vars-file-2.yml"),
 						Name: "variable-3",
 						Metadata: map[interface{}]interface{}{
 							"name": "variable-3",
@@ -273,6 +279,7 @@ variables:
 				Expect(vars).To(Equal([]Part{
 					{
 						File: "vars-file-2.yml",
+						Path: filepath.Join(tempDir, "vars-file-2.yml"),
 						Name: "variable-3",
 						Metadata: map[interface{}]interface{}{
 							"name": "variable-3",
@@ -281,6 +288,7 @@ variables:
 					},
 					{
 						File: "vars-file-1.yml",
+						Path: filepath.Join(tempDir, "vars-file-1.yml"),
 						Name: "variable-2",
 						Metadata: map[interface{}]interface{}{
 							"name": "variable-2",
@@ -289,6 +297,7 @@ variables:
 					},
 					{
 						File: "vars-file-1.yml",
+						Path: filepath.Join(tempDir, "vars-file-1.yml"),
 						Name: "variable-1",
 						Metadata: map[interface{}]interface{}{
 							"name": "variable-1",
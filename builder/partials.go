@@ -0,0 +1,120 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type partialDefinition struct {
+	Parameters []string    `yaml:"parameters"`
+	Template   interface{} `yaml:"template"`
+}
+
+// interpolatePartial renders the template of the named partial with the given
+// parameters. The parameters are either a single map, as piped from dict, or
+// key value pairs: $( partial "plan" "name" "small" "instances" 2 ).
+func (i Interpolator) interpolatePartial(input InterpolateInput, parent *interpolationFrame, name string, args []interface{}) (string, error) {
+	if input.Partials == nil {
+		return "", errors.New("--partials-directory must be specified")
+	}
+
+	val, ok := input.Partials[name]
+	if !ok {
+		return "", fmt.Errorf("could not find partial with name '%s'", name)
+	}
+
+	description := fmt.Sprintf("partial %q", name)
+	for frame := parent; frame != nil; frame = frame.parent {
+		if frame.description == description {
+			return "", fmt.Errorf("partial %q includes itself: %s", name, strings.Join(append(parent.chain(), description), " → "))
+		}
+	}
+
+	params, err := partialParameters(args)
+	if err != nil {
+		return "", fmt.Errorf("partial %q: %w", name, err)
+	}
+
+	frame := parent.child(description)
+	frame.params = params
+
+	if part, ok := val.(Part); ok {
+		val = part.Metadata
+		frame.path = part.Path
+	}
+
+	definitionYAML, err := yaml.Marshal(val)
+	if err != nil {
+		return "", err // should never happen
+	}
+
+	var definition partialDefinition
+	err = yaml.Unmarshal(definitionYAML, &definition)
+	if err != nil {
+		return "", fmt.Errorf("partial %q has an invalid format: %s", name, err)
+	}
+
+	if definition.Template == nil {
+		return "", fmt.Errorf("partial %q does not have a `template` field", name)
+	}
+
+	declared := map[string]bool{}
+	var missing []string
+	for _, parameter := range definition.Parameters {
+		declared[parameter] = true
+		if _, ok := params[parameter]; !ok {
+			missing = append(missing, parameter)
+		}
+	}
+
+	var undeclared []string
+	for parameter := range params {
+		if !declared[parameter] {
+			undeclared = append(undeclared, parameter)
+		}
+	}
+	sort.Strings(undeclared)
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("partial %q is missing parameters: %s", name, strings.Join(missing, ", "))
+	}
+
+	if len(undeclared) > 0 {
+		return "", fmt.Errorf("partial %q does not declare parameters: %s", name, strings.Join(undeclared, ", "))
+	}
+
+	return i.interpolateFrameIntoYAML(input, frame, definition.Template)
+}
+
+// partialParameter returns the value of a parameter of the innermost partial
+// being interpolated.
+func (i Interpolator) partialParameter(input InterpolateInput, current *interpolationFrame, name string) (string, error) {
+	for frame := current; frame != nil; frame = frame.parent {
+		if frame.params == nil {
+			continue
+		}
+
+		val, ok := frame.params[name]
+		if !ok {
+			return "", fmt.Errorf("could not find parameter with name '%s'", name)
+		}
+
+		return i.interpolateValueIntoYAML(input, current, fmt.Sprintf("param %q", name), val)
+	}
+
+	return "", errors.New("param can only be used inside a partial")
+}
+
+func partialParameters(args []interface{}) (map[string]interface{}, error) {
+	if len(args) == 1 {
+		if params, ok := args[0].(map[string]interface{}); ok {
+			return params, nil
+		}
+	}
+
+	return dict(args...)
+}
@@ -0,0 +1,103 @@
+package builder_test
+
+import (
+	. "github.com/pivotal-cf/kiln/builder"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf-experimental/gomegamatchers"
+)
+
+var _ = Describe("partials", func() {
+	var input InterpolateInput
+
+	BeforeEach(func() {
+		input = InterpolateInput{
+			Partials: map[string]interface{}{
+				"plan": Part{
+					File: "plan.yml",
+					Path: "partials/plan.yml",
+					Name: "plan",
+					Metadata: Metadata{
+						"name":       "plan",
+						"parameters": []interface{}{"plan_name", "instances"},
+						"template": Metadata{
+							"name":        `$( param "plan_name" )`,
+							"label":       `Plan $( param "plan_name" )`,
+							"instances":   `$( param "instances" )`,
+							"constraints": `$( partial "constraints" )`,
+						},
+					},
+				},
+				"constraints": Part{
+					File: "constraints.yml",
+					Path: "partials/constraints.yml",
+					Name: "constraints",
+					Metadata: Metadata{
+						"name":     "constraints",
+						"template": Metadata{"min": 1},
+					},
+				},
+			},
+		}
+	})
+
+	It("stamps out the partial template with each set of parameters", func() {
+		output, err := NewInterpolator().Interpolate(input, []byte(`
+plans:
+- $( partial "plan" "plan_name" "small" "instances" 1 )
+- $( dict "plan_name" "large" "instances" 5 | partial "plan" )
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HelpfullyMatchYAML(`
+plans:
+- name: small
+  label: Plan "small"
+  instances: 1
+  constraints:
+    min: 1
+- name: large
+  label: Plan "large"
+  instances: 5
+  constraints:
+    min: 1
+`))
+	})
+
+	It("fails when a declared parameter is missing", func() {
+		_, err := NewInterpolator().Interpolate(input, []byte(`plan: $( partial "plan" "plan_name" "small" )`))
+		Expect(err).To(MatchError(ContainSubstring(`partial "plan" is missing parameters: instances`)))
+	})
+
+	It("fails when a parameter is not declared", func() {
+		_, err := NewInterpolator().Interpolate(input, []byte(`plan: $( partial "constraints" "max" 3 )`))
+		Expect(err).To(MatchError(ContainSubstring(`partial "constraints" does not declare parameters: max`)))
+	})
+
+	It("fails when a partial includes itself", func() {
+		input.Partials["constraints"] = Part{
+			Name: "constraints",
+			Metadata: Metadata{
+				"name":     "constraints",
+				"template": Metadata{"plan": `$( partial "plan" "plan_name" "nested" "instances" 1 )`},
+			},
+		}
+
+		_, err := NewInterpolator().Interpolate(input, []byte(`plan: $( partial "plan" "plan_name" "small" "instances" 1 )`))
+		Expect(err).To(MatchError(ContainSubstring(`partial "plan" includes itself: metadata → partial "plan" → partial "constraints" → partial "plan"`)))
+	})
+
+	It("fails when param is used outside a partial", func() {
+		_, err := NewInterpolator().Interpolate(input, []byte(`name: $( param "plan_name" )`))
+		Expect(err).To(MatchError(ContainSubstring("param can only be used inside a partial")))
+	})
+
+	Context("when the partials directory is not specified", func() {
+		It("returns an error", func() {
+			input.Partials = nil
+
+			_, err := NewInterpolator().Interpolate(input, []byte(`plan: $( partial "plan" )`))
+			Expect(err).To(MatchError(ContainSubstring("--partials-directory must be specified")))
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"compress/gzip"
 	"crypto/sha1"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -21,6 +22,7 @@ type ReleaseManifest struct {
 	Version         string
 	File            string
 	SHA1            string
+	SHA256          string `yaml:"-"`
 	StemcellOS      string `yaml:"-"`
 	StemcellVersion string `yaml:"-"`
 }
@@ -114,16 +116,18 @@ func (r ReleaseManifestReader) Read(releaseTarball string) (Part, error) {
 		return Part{}, err // NOTE: cannot replicate this error scenario in a test
 	}
 
-	hash := sha1.New()
-	_, err = io.Copy(hash, file)
+	sha1Hash, sha256Hash := sha1.New(), sha256.New()
+	_, err = io.Copy(io.MultiWriter(sha1Hash, sha256Hash), file)
 	if err != nil {
 		return Part{}, err // NOTE: cannot replicate this error scenario in a test
 	}
 
-	outputReleaseManifest.SHA1 = fmt.Sprintf("%x", hash.Sum(nil))
+	outputReleaseManifest.SHA1 = fmt.Sprintf("%x", sha1Hash.Sum(nil))
+	outputReleaseManifest.SHA256 = fmt.Sprintf("%x", sha256Hash.Sum(nil))
 
 	return Part{
 		File:     releaseTarball,
+		Path:     releaseTarball,
 		Name:     inputReleaseManifest.Name,
 		Metadata: outputReleaseManifest,
 	}, nil
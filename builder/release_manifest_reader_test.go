@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"crypto/sha1"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -68,6 +69,13 @@ func createReleaseTarball(releaseMetadata string) (*os.File, string) {
 	return tarball, releaseSHA1
 }
 
+func fileSHA256(path string) string {
+	contents, err := ioutil.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred())
+
+	return fmt.Sprintf("%x", sha256.Sum256(contents))
+}
+
 var _ = Describe("ReleaseManifestReader", func() {
 	var (
 		reader      ReleaseManifestReader
@@ -99,12 +107,14 @@ compiled_packages:
 			Expect(err).NotTo(HaveOccurred())
 			Expect(releaseManifest).To(Equal(Part{
 				File: tarball.Name(),
+				Path: tarball.Name(),
 				Name: "release",
 				Metadata: ReleaseManifest{
 					Name:            "release",
 					Version:         "1.2.3",
 					File:            filepath.Base(tarball.Name()),
 					SHA1:            releaseSHA1,
+					SHA256:          fileSHA256(tarball.Name()),
 					StemcellOS:      "ubuntu-xenial",
 					StemcellVersion: "170.25",
 				},
@@ -125,12 +135,14 @@ version: 1.2.3
 				Expect(err).NotTo(HaveOccurred())
 				Expect(releaseManifest).To(Equal(Part{
 					File: tarball.Name(),
+					Path: tarball.Name(),
 					Name: "release",
 					Metadata: ReleaseManifest{
 						Name:            "release",
 						Version:         "1.2.3",
 						File:            filepath.Base(tarball.Name()),
 						SHA1:            releaseSHA1,
+						SHA256:          fileSHA256(tarball.Name()),
 						StemcellOS:      "",
 						StemcellVersion: "",
 					},
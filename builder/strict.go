@@ -0,0 +1,107 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+// recordVariableReferences marks the variables a template reads through the
+// template data, as in $( .some_variable ) or $( index . "some-variable" ),
+// as used.
+func recordVariableReferences(node parse.Node, used map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			recordVariableReferences(child, used)
+		}
+	case *parse.ActionNode:
+		recordVariableReferences(n.Pipe, used)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, command := range n.Cmds {
+			recordVariableReferences(command, used)
+		}
+	case *parse.CommandNode:
+		if len(n.Args) >= 3 {
+			identifier, isIdentifier := n.Args[0].(*parse.IdentifierNode)
+			_, isDot := n.Args[1].(*parse.DotNode)
+			key, isString := n.Args[2].(*parse.StringNode)
+			if isIdentifier && identifier.Ident == "index" && isDot && isString {
+				used[fmt.Sprintf("variable %q", key.Text)] = true
+			}
+		}
+		for _, arg := range n.Args {
+			recordVariableReferences(arg, used)
+		}
+	case *parse.FieldNode:
+		used[fmt.Sprintf("variable %q", n.Ident[0])] = true
+	case *parse.IfNode:
+		recordBranchVariableReferences(n.BranchNode, used)
+	case *parse.RangeNode:
+		recordBranchVariableReferences(n.BranchNode, used)
+	case *parse.WithNode:
+		recordBranchVariableReferences(n.BranchNode, used)
+	case *parse.TemplateNode:
+		recordVariableReferences(n.Pipe, used)
+	}
+}
+
+func recordBranchVariableReferences(branch parse.BranchNode, used map[string]bool) {
+	recordVariableReferences(branch.Pipe, used)
+	recordVariableReferences(branch.List, used)
+	recordVariableReferences(branch.ElseList, used)
+}
+
+// checkUnusedInputs returns an error listing the variables and parts that were
+// supplied to the interpolation but never referenced.
+func checkUnusedInputs(input InterpolateInput, used map[string]bool) error {
+	var unused []string
+
+	inputs := []struct {
+		helper string
+		values map[string]interface{}
+	}{
+		{"variable", input.Variables},
+		{"bosh_variable", input.BOSHVariables},
+		{"form", input.FormTypes},
+		{"instance_group", input.InstanceGroups},
+		{"job", input.Jobs},
+		{"property", input.PropertyBlueprints},
+		{"runtime_config", input.RuntimeConfigs},
+		{"partial", input.Partials},
+	}
+
+	for _, in := range inputs {
+		var names []string
+		for name := range in.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			description := fmt.Sprintf("%s %q", in.helper, name)
+			if used[description] {
+				continue
+			}
+
+			if part, ok := in.values[name].(Part); ok && part.Path != "" {
+				description = fmt.Sprintf("%s (%s)", description, part.Path)
+			}
+
+			unused = append(unused, description)
+		}
+	}
+
+	if len(unused) > 0 {
+		return fmt.Errorf("strict mode found inputs that are never used:\n  %s", strings.Join(unused, "\n  "))
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package builder_test
+
+import (
+	. "github.com/pivotal-cf/kiln/builder"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf-experimental/gomegamatchers"
+)
+
+var _ = Describe("strict interpolation", func() {
+	var input InterpolateInput
+
+	BeforeEach(func() {
+		input = InterpolateInput{
+			Strict: true,
+			Variables: map[string]interface{}{
+				"some_variable":  "some-value",
+				"other-variable": "other-value",
+			},
+			FormTypes: map[string]interface{}{
+				"some-form": Part{
+					File:     "some-form.yml",
+					Path:     "forms/some-form.yml",
+					Name:     "some-form",
+					Metadata: Metadata{"name": "some-form", "label": `$( variable "other-variable" )`},
+				},
+			},
+			PropertyBlueprints: map[string]interface{}{
+				"some-property": Part{
+					File:     "properties.yml",
+					Path:     "properties/properties.yml",
+					Name:     "some-property",
+					Metadata: Metadata{"name": "some-property", "type": "string"},
+				},
+			},
+		}
+	})
+
+	It("succeeds when every variable and part is used", func() {
+		output, err := NewInterpolator().Interpolate(input, []byte(`
+name: $( .some_variable )
+form_types: [$( form "some-form" )]
+property_blueprints: [$( property "some-property" )]
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HelpfullyMatchYAML(`
+name: some-value
+form_types:
+- name: some-form
+  label: other-value
+property_blueprints:
+- name: some-property
+  type: string
+`))
+	})
+
+	It("counts variables read with index as used", func() {
+		_, err := NewInterpolator().Interpolate(input, []byte(`
+name: $( index . "some_variable" )
+form_types: [$( form "some-form" )]
+property_blueprints: [$( property "some-property" )]
+`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("counts nested variables read with a dotted key as used", func() {
+		input.Variables["some_variable"] = map[interface{}]interface{}{"name": "some-value"}
+
+		_, err := NewInterpolator().Interpolate(input, []byte(`
+name: $( variable "some_variable.name" )
+form_types: [$( form "some-form" )]
+property_blueprints: [$( property "some-property" )]
+`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("fails when the template data path is not defined", func() {
+		_, err := NewInterpolator().Interpolate(input, []byte(`name: $( .missing_variable )`))
+		Expect(err).To(MatchError(ContainSubstring(`$( .missing_variable ): map has no entry for key "missing_variable"`)))
+	})
+
+	It("lists the variables and parts that are never used", func() {
+		_, err := NewInterpolator().Interpolate(input, []byte(`name: $( variable "some_variable" )`))
+		Expect(err).To(MatchError(`strict mode found inputs that are never used:
+  variable "other-variable"
+  form "some-form" (forms/some-form.yml)
+  property "some-property" (properties/properties.yml)`))
+	})
+
+	Context("when strict mode is off", func() {
+		It("renders undefined template data as before", func() {
+			input.Strict = false
+
+			output, err := NewInterpolator().Interpolate(input, []byte(`name: $( .missing_variable )`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`name: <no value>`))
+		})
+	})
+})
@@ -0,0 +1,165 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/semver"
+	yamlConverter "github.com/ghodss/yaml"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// templateFunctions returns the general purpose helpers available in metadata
+// templates alongside the part lookups.
+func templateFunctions(input InterpolateInput) template.FuncMap {
+	return template.FuncMap{
+		"default": func(defaultValue interface{}, given ...interface{}) interface{} {
+			if len(given) == 0 || isEmpty(given[0]) {
+				return defaultValue
+			}
+			return given[0]
+		},
+		"toJson": func(value interface{}) (string, error) {
+			// Marshalling through YAML first handles the map[interface{}]interface{}
+			// values that come out of the part files.
+			contents, err := yaml.Marshal(value)
+			if err != nil {
+				return "", fmt.Errorf("could not marshal value: %w", err)
+			}
+
+			output, err := yamlConverter.YAMLToJSON(contents)
+			if err != nil {
+				return "", fmt.Errorf("could not convert value to JSON: %w", err)
+			}
+
+			return string(output), nil
+		},
+		"toYaml": func(value interface{}) (string, error) {
+			output, err := yaml.Marshal(value)
+			if err != nil {
+				return "", fmt.Errorf("could not marshal value: %w", err)
+			}
+
+			return strings.TrimSuffix(string(output), "\n"), nil
+		},
+		"indent": func(spaces int, value string) string {
+			padding := strings.Repeat(" ", spaces)
+			return padding + strings.Replace(value, "\n", "\n"+padding, -1)
+		},
+		"join": func(separator string, values interface{}) (string, error) {
+			list, err := toList(values)
+			if err != nil {
+				return "", fmt.Errorf("join: %w", err)
+			}
+
+			var items []string
+			for _, item := range list {
+				items = append(items, fmt.Sprint(item))
+			}
+
+			return strings.Join(items, separator), nil
+		},
+		"split": func(separator, value string) []string {
+			return strings.Split(value, separator)
+		},
+		"semverCompare": func(constraint, version string) (bool, error) {
+			c, err := semver.NewConstraint(constraint)
+			if err != nil {
+				return false, fmt.Errorf("could not parse semver constraint %q: %w", constraint, err)
+			}
+
+			v, err := semver.NewVersion(version)
+			if err != nil {
+				return false, fmt.Errorf("could not parse semver version %q: %w", version, err)
+			}
+
+			return c.Check(v), nil
+		},
+		"b64enc": func(value string) string {
+			return base64.StdEncoding.EncodeToString([]byte(value))
+		},
+		"sha256sum": func(value string) string {
+			sum := sha256.Sum256([]byte(value))
+			return hex.EncodeToString(sum[:])
+		},
+		"file": func(path string) (string, error) {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(filepath.Dir(input.MetadataPath), path)
+			}
+
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				return "", fmt.Errorf("could not read file %q: %w", path, err)
+			}
+
+			return string(contents), nil
+		},
+		"env": func(name string) (string, error) {
+			if !input.AllowEnv {
+				return "", errors.New("--allow-env must be specified to use the env helper")
+			}
+
+			return os.Getenv(name), nil
+		},
+		"dict": dict,
+		"list": func(items ...interface{}) []interface{} {
+			return items
+		},
+	}
+}
+
+func toList(values interface{}) ([]interface{}, error) {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a list, got %v", values)
+	}
+
+	var list []interface{}
+	for i := 0; i < v.Len(); i++ {
+		list = append(list, v.Index(i).Interface())
+	}
+
+	return list, nil
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict requires an even number of arguments, got %d", len(pairs))
+	}
+
+	dict := map[string]interface{}{}
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict keys must be strings, got %v", pairs[i])
+		}
+		dict[key] = pairs[i+1]
+	}
+
+	return dict, nil
+}
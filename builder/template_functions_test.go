@@ -0,0 +1,196 @@
+package builder_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/pivotal-cf/kiln/builder"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf-experimental/gomegamatchers"
+)
+
+var _ = Describe("template functions", func() {
+	var (
+		interpolator Interpolator
+		input        InterpolateInput
+	)
+
+	BeforeEach(func() {
+		interpolator = NewInterpolator()
+		input = InterpolateInput{
+			Variables: map[string]interface{}{
+				"some-variable": "some-value",
+				"empty":         "",
+				"some-list":     []interface{}{"a", "b", "c"},
+				"some-map": map[interface{}]interface{}{
+					"key": "value",
+				},
+			},
+		}
+	})
+
+	interpolate := func(templateYAML string) (string, error) {
+		output, err := interpolator.Interpolate(input, []byte(templateYAML))
+		return string(output), err
+	}
+
+	Describe("default", func() {
+		It("returns the given value when it is not empty", func() {
+			output, err := interpolate(`value: $( variable "some-variable" | default "fallback" )`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`value: some-value`))
+		})
+
+		It("returns the default when the given value is empty", func() {
+			output, err := interpolate(`value: $( index . "empty" | default "fallback" )`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`value: fallback`))
+		})
+
+		It("returns the default when the variable is not set", func() {
+			output, err := interpolate(`value: $( index . "missing-variable" | default "fallback" )`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`value: fallback`))
+		})
+	})
+
+	Describe("toJson", func() {
+		It("renders values as inline JSON", func() {
+			output, err := interpolate(`value: $( index . "some-map" | toJson )`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`value: {key: value}`))
+		})
+	})
+
+	Describe("toYaml and indent", func() {
+		It("renders values as indented YAML", func() {
+			output, err := interpolate("value:\n$( index . \"some-list\" | toYaml | indent 2 )")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML("value: [a, b, c]"))
+		})
+	})
+
+	Describe("join and split", func() {
+		It("joins lists and splits strings", func() {
+			output, err := interpolate(`
+joined: $( index . "some-list" | join "," )
+split: $( split "," "x,z" | toJson )
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`
+joined: a,b,c
+split: [x, z]
+`))
+		})
+
+		It("returns an error when join is not given a list", func() {
+			_, err := interpolate(`value: $( join "," "not-a-list" )`)
+			Expect(err).To(MatchError(ContainSubstring("join: expected a list, got not-a-list")))
+		})
+	})
+
+	Describe("semverCompare", func() {
+		It("checks a version against a constraint", func() {
+			output, err := interpolate(`
+newer: $( semverCompare ">= 2.7" "2.8.1" )
+older: $( semverCompare ">= 2.7" "2.6.0" )
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`
+newer: true
+older: false
+`))
+		})
+
+		It("returns an error when the version is invalid", func() {
+			_, err := interpolate(`value: $( semverCompare ">= 2.7" "banana" )`)
+			Expect(err).To(MatchError(ContainSubstring(`could not parse semver version "banana"`)))
+		})
+	})
+
+	Describe("b64enc and sha256sum", func() {
+		It("encodes and hashes strings", func() {
+			output, err := interpolate(`
+encoded: $( b64enc "some-value" )
+sum: $( sha256sum "some-value" )
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`
+encoded: c29tZS12YWx1ZQ==
+sum: 700f3c597d9a0db5fc2dcc41c8d9b650d64ba0ed979dc00f1e3dea17fca07a1f
+`))
+		})
+	})
+
+	Describe("file", func() {
+		var tempDir string
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(tempDir, "description.txt"), []byte("some description"), 0644)).To(Succeed())
+			input.MetadataPath = filepath.Join(tempDir, "metadata.yml")
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		It("reads files relative to the metadata", func() {
+			output, err := interpolate(`description: $( file "description.txt" )`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`description: some description`))
+		})
+
+		It("returns an error when the file does not exist", func() {
+			_, err := interpolate(`description: $( file "missing.txt" )`)
+			Expect(err).To(MatchError(ContainSubstring("could not read file")))
+		})
+	})
+
+	Describe("env", func() {
+		BeforeEach(func() {
+			Expect(os.Setenv("KILN_TEMPLATE_TEST", "from-env")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.Unsetenv("KILN_TEMPLATE_TEST")).To(Succeed())
+		})
+
+		It("reads environment variables when allowed", func() {
+			input.AllowEnv = true
+			output, err := interpolate(`value: $( env "KILN_TEMPLATE_TEST" )`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`value: from-env`))
+		})
+
+		It("returns an error when not allowed", func() {
+			_, err := interpolate(`value: $( env "KILN_TEMPLATE_TEST" )`)
+			Expect(err).To(MatchError(ContainSubstring("--allow-env must be specified to use the env helper")))
+		})
+	})
+
+	Describe("dict and list", func() {
+		It("builds maps and lists", func() {
+			output, err := interpolate(`
+dict: $( dict "name" "some-name" "count" 2 | toJson )
+list: $( list "a" 1 | toJson )
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HelpfullyMatchYAML(`
+dict: {name: some-name, count: 2}
+list: [a, 1]
+`))
+		})
+
+		It("returns an error when dict is given an odd number of arguments", func() {
+			_, err := interpolate(`value: $( dict "name" )`)
+			Expect(err).To(MatchError(ContainSubstring("dict requires an even number of arguments, got 1")))
+		})
+	})
+})
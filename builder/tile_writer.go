@@ -2,12 +2,15 @@ package builder
 
 import (
 	"bytes"
+	"crypto/sha1"
+	"fmt"
 	"gopkg.in/yaml.v2"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -51,6 +54,16 @@ type WriteInput struct {
 	MigrationDirectories []string
 	ReleaseDirectories   []string
 	EmbedPaths           []string
+
+	// AllowReleaseOverrides lets a release tarball in a later releases
+	// directory replace one with the same file name in an earlier directory,
+	// like --allow-part-overrides does for parts.
+	AllowReleaseOverrides bool
+	Checksums             []string
+
+	// GeneratedFiles are added to the tile as is, keyed by their path in the
+	// tile. Bake uses them for documents about the tile such as its SBOM.
+	GeneratedFiles map[string][]byte
 }
 
 type tileMetadata struct {
@@ -58,63 +71,143 @@ type tileMetadata struct {
 }
 
 type release struct {
+	Name string `yaml:"name"`
 	File string `yaml:"file"`
+	SHA1 string `yaml:"sha1"`
 }
 
-func (w TileWriter) Write(generatedMetadataContents []byte, input WriteInput) error {
+// Write builds the tile and returns the digests of the tile for each
+// algorithm in input.Checksums, computed while the tile is written.
+func (w TileWriter) Write(generatedMetadataContents []byte, input WriteInput) (Digests, error) {
+	err := validateChecksumAlgorithms(input.Checksums)
+	if err != nil {
+		return nil, err
+	}
+
 	w.logger.Printf("Building %s...", input.OutputFile)
 
 	f, err := w.filesystem.Create(input.OutputFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f.Close()
 
-	w.zipper.SetWriter(f)
+	var hasher *hashingWriter
+	if len(input.Checksums) > 0 {
+		hasher = newHashingWriter(f, input.Checksums)
+		for _, algorithm := range input.Checksums {
+			w.logger.Printf("Calculating %s checksum of %s...", strings.ToUpper(algorithm), input.OutputFile)
+		}
+		w.zipper.SetWriter(hasher)
+	} else {
+		w.zipper.SetWriter(f)
+	}
 
 	err = w.addToZipper(filepath.Join("metadata", "metadata.yml"), bytes.NewBuffer(generatedMetadataContents), input.OutputFile)
 	if err != nil {
 		w.removeOutputFile(input.OutputFile)
-		return err
+		return nil, err
 	}
 
 	err = w.addMigrations(input.MigrationDirectories, input.OutputFile)
 	if err != nil {
 		w.removeOutputFile(input.OutputFile)
-		return err
+		return nil, err
 	}
 
 	if input.StubReleases {
 		err = w.addStubReleases(generatedMetadataContents, input.OutputFile)
 	} else {
-		err = w.addReleases(input.ReleaseDirectories, input.OutputFile)
+		err = w.addReleases(generatedMetadataContents, input.ReleaseDirectories, input.AllowReleaseOverrides, input.OutputFile)
 	}
 	if err != nil {
 		w.removeOutputFile(input.OutputFile)
-		return err
+		return nil, err
 	}
 
 	err = w.addEmbeddedPaths(input.EmbedPaths, input.OutputFile)
 	if err != nil {
 		w.removeOutputFile(input.OutputFile)
-		return err
+		return nil, err
+	}
+
+	err = w.addGeneratedFiles(input.GeneratedFiles, input.OutputFile)
+	if err != nil {
+		w.removeOutputFile(input.OutputFile)
+		return nil, err
 	}
 
 	err = w.zipper.Close()
 	if err != nil {
 		w.removeOutputFile(input.OutputFile)
-		return err
+		return nil, err
 	}
 
-	return nil
+	if hasher == nil {
+		return nil, nil
+	}
+
+	return hasher.digests(), nil
 }
 
-func (w TileWriter) addReleases(releasesDirs []string, outputFile string) error {
-	for _, releasesDirectory := range releasesDirs {
-		err := w.addReleaseTarballs(releasesDirectory, outputFile)
+// addReleases adds exactly the releases listed in the generated metadata,
+// failing when a listed tarball cannot be found, the metadata does not list
+// its SHA1 or the SHA1 does not match.
+func (w TileWriter) addReleases(generatedMetadataContents []byte, releasesDirs []string, allowOverrides bool, outputFile string) error {
+	var metadata tileMetadata
+	err := yaml.Unmarshal(generatedMetadataContents, &metadata)
+	if err != nil {
+		return err
+	}
+
+	// Tarballs are found by file name, so two tarballs with the same name
+	// follow the rule bake applies to parts with the same name: an error that
+	// names both files, unless overrides are allowed and the later tarball
+	// comes from a later directory.
+	var tarballPaths []string
+	tarballs := map[string]string{}
+	directories := map[string]int{}
+	for i, releasesDirectory := range releasesDirs {
+		paths, err := w.findReleaseTarballs(releasesDirectory)
 		if err != nil {
 			return err
 		}
+
+		for _, tarballPath := range paths {
+			name := filepath.Base(tarballPath)
+			if previous, ok := tarballs[name]; ok {
+				if !allowOverrides || directories[name] == i {
+					return fmt.Errorf("release tarball %q is defined in both %q and %q", name, previous, tarballPath)
+				}
+
+				w.logger.Printf("Overriding release tarball %q from %q with %q", name, previous, tarballPath)
+			}
+
+			tarballs[name] = tarballPath
+			directories[name] = i
+		}
+
+		tarballPaths = append(tarballPaths, paths...)
+	}
+
+	listed := map[string]bool{}
+	for _, release := range metadata.Releases {
+		tarballPath, ok := tarballs[release.File]
+		if !ok {
+			return fmt.Errorf("could not find release tarball %q for release %q in the releases directories", release.File, release.Name)
+		}
+		listed[tarballPath] = true
+
+		err = w.addReleaseTarball(tarballPath, release, outputFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, tarballPath := range tarballPaths {
+		if !listed[tarballPath] {
+			w.logger.Printf("Skipping %s, it is not listed in the metadata releases...", tarballPath)
+		}
 	}
 
 	return nil
@@ -137,8 +230,10 @@ func (w TileWriter) addStubReleases(generatedMetadataContents []byte, outputFile
 	return nil
 }
 
-func (w TileWriter) addReleaseTarballs(releasesDir string, outputFile string) error {
-	return w.filesystem.Walk(releasesDir, func(filePath string, info os.FileInfo, err error) error {
+func (w TileWriter) findReleaseTarballs(releasesDir string) ([]string, error) {
+	var tarballPaths []string
+
+	err := w.filesystem.Walk(releasesDir, func(filePath string, info os.FileInfo, err error) error {
 		isTarball, _ := regexp.MatchString("tgz$|tar.gz$", filePath)
 		if !isTarball {
 			return nil
@@ -152,15 +247,37 @@ func (w TileWriter) addReleaseTarballs(releasesDir string, outputFile string) er
 			return nil
 		}
 
-		file := ioutil.NopCloser(strings.NewReader(""))
-		file, err = w.filesystem.Open(filePath)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
+		tarballPaths = append(tarballPaths, filePath)
 
-		return w.addToZipper(filepath.Join("releases", filepath.Base(filePath)), file, outputFile)
+		return nil
 	})
+
+	return tarballPaths, err
+}
+
+func (w TileWriter) addReleaseTarball(tarballPath string, release release, outputFile string) error {
+	if release.SHA1 == "" {
+		return fmt.Errorf("the metadata for release %q does not list a SHA1 to verify %q against", release.Name, tarballPath)
+	}
+
+	file, err := w.filesystem.Open(tarballPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha1.New()
+	err = w.addToZipper(filepath.Join("releases", release.File), io.TeeReader(file, hash), outputFile)
+	if err != nil {
+		return err
+	}
+
+	sum := fmt.Sprintf("%x", hash.Sum(nil))
+	if release.SHA1 != sum {
+		return fmt.Errorf("release tarball %q has SHA1 %q but the metadata for release %q lists %q", tarballPath, sum, release.Name, release.SHA1)
+	}
+
+	return nil
 }
 
 func (w TileWriter) addEmbeddedPaths(embedPaths []string, outputFile string) error {
@@ -200,6 +317,23 @@ func (w TileWriter) addEmbeddedPath(pathToEmbed, outputFile string) error {
 	})
 }
 
+func (w TileWriter) addGeneratedFiles(files map[string][]byte, outputFile string) error {
+	var paths []string
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		err := w.addToZipper(path, bytes.NewReader(files[path]), outputFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (w TileWriter) addMigrations(migrationsDir []string, outputFile string) error {
 	var found bool
 
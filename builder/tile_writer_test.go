@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	. "github.com/pivotal-cf/kiln/builder"
 	"github.com/pivotal-cf/kiln/builder/fakes"
@@ -18,6 +20,16 @@ import (
 )
 
 var _ = Describe("TileWriter", func() {
+	const releasesMetadata = `---
+releases:
+- name: release-1
+  file: release-1.tgz
+  sha1: 08dab5929a7c613a839b7707afe7f3fdc1a248cd
+- name: release-2
+  file: release-2.tgz
+  sha1: 03cf77e56ba1618ff34bb2274019c8d0054aada8
+`
+
 	var (
 		filesystem *fakes.Filesystem
 		zipper     *fakes.Zipper
@@ -26,6 +38,7 @@ var _ = Describe("TileWriter", func() {
 		outputFile string
 
 		expectedFile *os.File
+		added        map[string]string
 	)
 
 	BeforeEach(func() {
@@ -40,6 +53,13 @@ var _ = Describe("TileWriter", func() {
 		BeforeEach(func() {
 			expectedFile = &os.File{}
 			filesystem.CreateReturns(expectedFile, nil)
+
+			added = map[string]string{}
+			zipper.AddStub = func(path string, contents io.Reader) error {
+				b, err := ioutil.ReadAll(contents)
+				added[path] = string(b)
+				return err
+			}
 		})
 
 		DescribeTable("writes tile to disk", func(stubbed bool, errorWhenAttemptingToOpenRelease error) {
@@ -121,12 +141,16 @@ var _ = Describe("TileWriter", func() {
 			var metadata = `---
 releases:
 - file: release-1.tgz
+  sha1: 08dab5929a7c613a839b7707afe7f3fdc1a248cd
 - file: release-2.tgz
+  sha1: 03cf77e56ba1618ff34bb2274019c8d0054aada8
 - file: release-3.tgz
+  sha1: 536273c98b9eb417959cbebfa468849f5c64aea9
 - file: release-4.tgz
+  sha1: b1b5a930c03dec921bfc7214c6b8b0334b459eac
 `
 
-			err := tileWriter.Write([]byte(metadata), input)
+			_, err := tileWriter.Write([]byte(metadata), input)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(zipper.SetWriterCallCount()).To(Equal(1))
@@ -135,51 +159,52 @@ releases:
 
 			Expect(zipper.AddCallCount()).To(Equal(8))
 
-			path, file := zipper.AddArgsForCall(0)
+			path, _ := zipper.AddArgsForCall(0)
 			Expect(path).To(Equal(filepath.Join("metadata", "metadata.yml")))
-			Eventually(gbytes.BufferReader(file)).Should(gbytes.Say(metadata))
+			Expect(added[path]).To(Equal(metadata))
 
-			path, file = zipper.AddArgsForCall(1)
+			path, _ = zipper.AddArgsForCall(1)
 			Expect(path).To(Equal(filepath.Join("migrations", "v1", "migration-1.js")))
-			Eventually(gbytes.BufferReader(file)).Should(gbytes.Say("migration-1"))
+			Expect(added[path]).To(Equal("migration-1"))
 
-			path, file = zipper.AddArgsForCall(2)
+			path, _ = zipper.AddArgsForCall(2)
 			Expect(path).To(Equal(filepath.Join("migrations", "v1", "migration-2.js")))
-			Eventually(gbytes.BufferReader(file)).Should(gbytes.Say("migration-2"))
+			Expect(added[path]).To(Equal("migration-2"))
 
-			path, file = zipper.AddArgsForCall(3)
+			path, _ = zipper.AddArgsForCall(3)
 			Expect(path).To(Equal(filepath.Join("migrations", "v1", "other-migration.js")))
-			Eventually(gbytes.BufferReader(file)).Should(gbytes.Say("other-migration"))
+			Expect(added[path]).To(Equal("other-migration"))
 
-			path, file = zipper.AddArgsForCall(4)
+			path, _ = zipper.AddArgsForCall(4)
 			Expect(path).To(Equal(filepath.Join("releases", "release-1.tgz")))
-			checkReleaseFileContent("release-1", stubbed, file)
+			checkReleaseFileContent("release-1", stubbed, added[path])
 
-			path, file = zipper.AddArgsForCall(5)
+			path, _ = zipper.AddArgsForCall(5)
 			Expect(path).To(Equal(filepath.Join("releases", "release-2.tgz")))
-			checkReleaseFileContent("release-2", stubbed, file)
+			checkReleaseFileContent("release-2", stubbed, added[path])
 
-			path, file = zipper.AddArgsForCall(6)
+			path, _ = zipper.AddArgsForCall(6)
 			Expect(path).To(Equal(filepath.Join("releases", "release-3.tgz")))
-			checkReleaseFileContent("release-3", stubbed, file)
+			checkReleaseFileContent("release-3", stubbed, added[path])
 
-			path, file = zipper.AddArgsForCall(7)
+			path, _ = zipper.AddArgsForCall(7)
 			Expect(path).To(Equal(filepath.Join("releases", "release-4.tgz")))
-			checkReleaseFileContent("release-4", stubbed, file)
+			checkReleaseFileContent("release-4", stubbed, added[path])
 
 			Expect(zipper.CloseCallCount()).To(Equal(1))
 
-			Expect(logger.PrintfCall.Receives.LogLines).To(Equal([]string{
+			expectedLogLines := []string{
 				fmt.Sprintf("Building %s...", outputFile),
 				fmt.Sprintf("Adding metadata/metadata.yml to %s...", outputFile),
 				fmt.Sprintf("Adding migrations/v1/migration-1.js to %s...", outputFile),
 				fmt.Sprintf("Adding migrations/v1/migration-2.js to %s...", outputFile),
 				fmt.Sprintf("Adding migrations/v1/other-migration.js to %s...", outputFile),
-				fmt.Sprintf("Adding releases/release-1.tgz to %s...", outputFile),
-				fmt.Sprintf("Adding releases/release-2.tgz to %s...", outputFile),
-				fmt.Sprintf("Adding releases/release-3.tgz to %s...", outputFile),
-				fmt.Sprintf("Adding releases/release-4.tgz to %s...", outputFile),
-			}))
+			}
+			for _, release := range []string{"release-1.tgz", "release-2.tgz", "release-3.tgz", "release-4.tgz"} {
+				expectedLogLines = append(expectedLogLines, fmt.Sprintf("Adding releases/%s to %s...", release, outputFile))
+			}
+
+			Expect(logger.PrintfCall.Receives.LogLines).To(Equal(expectedLogLines))
 
 		},
 			Entry("without stubbing releases", false, nil),
@@ -220,7 +245,7 @@ releases:
 					StubReleases:       true,
 				}
 
-				err := tileWriter.Write([]byte("releases:\n- file: release-1.tgz"), input)
+				_, err := tileWriter.Write([]byte("releases:\n- file: release-1.tgz"), input)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(zipper.AddCallCount()).To(Equal(2))
 				path, _ := zipper.AddArgsForCall(1)
@@ -259,7 +284,7 @@ releases:
 					}
 
 					if path == "/some/path/releases/release-2.tgz" {
-						return NewBuffer(bytes.NewBufferString("release-1")), nil
+						return NewBuffer(bytes.NewBufferString("release-2")), nil
 					}
 
 					return nil, nil
@@ -275,7 +300,7 @@ releases:
 						StubReleases:         false,
 					}
 
-					err := tileWriter.Write([]byte("generated-metadata-contents"), input)
+					_, err := tileWriter.Write([]byte(releasesMetadata), input)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(logger.PrintfCall.Receives.LogLines).To(Equal([]string{
@@ -300,7 +325,7 @@ releases:
 						StubReleases:         false,
 					}
 
-					err := tileWriter.Write([]byte("generated-metadata-contents"), input)
+					_, err := tileWriter.Write([]byte(releasesMetadata), input)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(logger.PrintfCall.Receives.LogLines).To(Equal([]string{
@@ -317,6 +342,201 @@ releases:
 			})
 		})
 
+		Context("when the metadata lists the releases to include", func() {
+			var input WriteInput
+
+			BeforeEach(func() {
+				dirInfo := &fakes.FileInfo{}
+				dirInfo.IsDirReturns(true)
+
+				releaseInfo := &fakes.FileInfo{}
+				releaseInfo.IsDirReturns(false)
+
+				filesystem.WalkStub = func(root string, walkFn filepath.WalkFunc) error {
+					walkFn("/some/path/releases", dirInfo, nil)
+					walkFn("/some/path/releases/release-1.tgz", releaseInfo, nil)
+					walkFn("/some/path/releases/stale-release.tgz", releaseInfo, nil)
+					return nil
+				}
+
+				filesystem.OpenStub = func(path string) (io.ReadCloser, error) {
+					return NewBuffer(bytes.NewBufferString("release-1")), nil
+				}
+
+				zipper.AddStub = func(path string, contents io.Reader) error {
+					_, err := io.Copy(ioutil.Discard, contents)
+					return err
+				}
+
+				input = WriteInput{
+					ReleaseDirectories: []string{"/some/path/releases"},
+					OutputFile:         outputFile,
+				}
+			})
+
+			It("only adds the listed releases", func() {
+				_, err := tileWriter.Write([]byte("releases:\n- name: release-1\n  file: release-1.tgz\n  sha1: 08dab5929a7c613a839b7707afe7f3fdc1a248cd"), input)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(zipper.AddCallCount()).To(Equal(2))
+				path, _ := zipper.AddArgsForCall(1)
+				Expect(path).To(Equal(filepath.Join("releases", "release-1.tgz")))
+
+				Expect(logger.PrintfCall.Receives.LogLines).To(ContainElement(
+					"Skipping /some/path/releases/stale-release.tgz, it is not listed in the metadata releases...",
+				))
+			})
+
+			Context("when a listed release tarball is missing", func() {
+				It("returns an error", func() {
+					_, err := tileWriter.Write([]byte("releases:\n- name: release-3\n  file: release-3.tgz"), input)
+					Expect(err).To(MatchError(`could not find release tarball "release-3.tgz" for release "release-3" in the releases directories`))
+
+					Expect(filesystem.RemoveCallCount()).To(Equal(1))
+					Expect(filesystem.RemoveArgsForCall(0)).To(Equal(outputFile))
+				})
+			})
+
+			Context("when the metadata does not list the SHA1 of a release", func() {
+				It("returns an error without adding the release tarball", func() {
+					_, err := tileWriter.Write([]byte("releases:\n- name: release-1\n  file: release-1.tgz"), input)
+					Expect(err).To(MatchError(`the metadata for release "release-1" does not list a SHA1 to verify "/some/path/releases/release-1.tgz" against`))
+
+					Expect(zipper.AddCallCount()).To(Equal(1))
+					Expect(filesystem.RemoveCallCount()).To(Equal(1))
+					Expect(filesystem.RemoveArgsForCall(0)).To(Equal(outputFile))
+				})
+			})
+
+			Context("when the SHA1 of a release tarball does not match the metadata", func() {
+				It("returns an error", func() {
+					_, err := tileWriter.Write([]byte("releases:\n- name: release-1\n  file: release-1.tgz\n  sha1: some-other-sha1"), input)
+					Expect(err).To(MatchError(`release tarball "/some/path/releases/release-1.tgz" has SHA1 "08dab5929a7c613a839b7707afe7f3fdc1a248cd" but the metadata for release "release-1" lists "some-other-sha1"`))
+
+					Expect(filesystem.RemoveCallCount()).To(Equal(1))
+					Expect(filesystem.RemoveArgsForCall(0)).To(Equal(outputFile))
+				})
+			})
+
+			Context("when two releases directories contain a tarball with the same name", func() {
+				BeforeEach(func() {
+					releaseInfo := &fakes.FileInfo{}
+					releaseInfo.IsDirReturns(false)
+
+					filesystem.WalkStub = func(root string, walkFn filepath.WalkFunc) error {
+						walkFn(filepath.Join(root, "release-1.tgz"), releaseInfo, nil)
+						return nil
+					}
+
+					input.ReleaseDirectories = []string{"/some/path/releases", "/some/variant/releases"}
+				})
+
+				It("returns an error that names both files", func() {
+					_, err := tileWriter.Write([]byte("releases:\n- name: release-1\n  file: release-1.tgz\n  sha1: 08dab5929a7c613a839b7707afe7f3fdc1a248cd"), input)
+					Expect(err).To(MatchError(`release tarball "release-1.tgz" is defined in both "/some/path/releases/release-1.tgz" and "/some/variant/releases/release-1.tgz"`))
+				})
+
+				Context("when release overrides are allowed", func() {
+					It("adds the tarball from the later directory", func() {
+						input.AllowReleaseOverrides = true
+
+						_, err := tileWriter.Write([]byte("releases:\n- name: release-1\n  file: release-1.tgz\n  sha1: 08dab5929a7c613a839b7707afe7f3fdc1a248cd"), input)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(filesystem.OpenArgsForCall(0)).To(Equal("/some/variant/releases/release-1.tgz"))
+						Expect(logger.PrintfCall.Receives.LogLines).To(ContainElement(
+							`Overriding release tarball "release-1.tgz" from "/some/path/releases/release-1.tgz" with "/some/variant/releases/release-1.tgz"`,
+						))
+					})
+				})
+			})
+
+			Context("when the generated metadata is invalid", func() {
+				It("returns an error", func() {
+					_, err := tileWriter.Write([]byte("releases: {"), input)
+					Expect(err).To(MatchError(ContainSubstring("yaml")))
+				})
+			})
+		})
+
+		Context("when checksums are requested", func() {
+			var output *bytes.Buffer
+
+			BeforeEach(func() {
+				output = bytes.NewBuffer(nil)
+				filesystem.CreateReturns(NewBuffer(output), nil)
+
+				var zipWriter io.Writer
+				zipper.SetWriterStub = func(writer io.Writer) {
+					zipWriter = writer
+				}
+				zipper.AddStub = func(path string, contents io.Reader) error {
+					_, err := io.Copy(zipWriter, contents)
+					return err
+				}
+			})
+
+			It("returns the digests of everything written to the tile", func() {
+				digests, err := tileWriter.Write([]byte("releases: []\n"), WriteInput{
+					OutputFile:   outputFile,
+					StubReleases: true,
+					Checksums:    []string{"sha1", "sha256", "sha512", "sha256"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(output.String()).To(Equal("releases: []\n"))
+				Expect(digests).To(Equal(Digests{
+					"sha1":   "5e915be899f7106ac5fd74acff816a51e85d4f86",
+					"sha256": "a0c0e771414ce43cc3b05715ec0ebee45c8781123701ecf028cd0590f3ad415d",
+					"sha512": "8835c2e299e898d3f26a06d1454fb145520a8b50e103ba40c63b2c0d131868157ac67e50d0bee94d89a58798097a9ed292b2c16ab4482425614fa044624dcd7e",
+				}))
+			})
+
+			It("returns no digests when none are requested", func() {
+				digests, err := tileWriter.Write([]byte("releases: []\n"), WriteInput{
+					OutputFile:   outputFile,
+					StubReleases: true,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(digests).To(BeNil())
+			})
+
+			Context("when the algorithm is not supported", func() {
+				It("returns an error without creating the tile", func() {
+					_, err := tileWriter.Write([]byte("releases: []\n"), WriteInput{
+						OutputFile: outputFile,
+						Checksums:  []string{"md5"},
+					})
+					Expect(err).To(MatchError(`unsupported checksum algorithm "md5", expected one of sha1, sha256 or sha512`))
+					Expect(filesystem.CreateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when generated files are provided", func() {
+			It("adds each file at its path in the tile", func() {
+				_, err := tileWriter.Write([]byte("releases: []\n"), WriteInput{
+					OutputFile:   outputFile,
+					StubReleases: true,
+					GeneratedFiles: map[string][]byte{
+						"sbom/sbom.spdx.json": []byte("some-sbom"),
+						"provenance.json":     []byte("some-provenance"),
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(zipper.AddCallCount()).To(Equal(3))
+
+				path, _ := zipper.AddArgsForCall(1)
+				Expect(path).To(Equal("provenance.json"))
+				Expect(added[path]).To(Equal("some-provenance"))
+
+				path, _ = zipper.AddArgsForCall(2)
+				Expect(path).To(Equal("sbom/sbom.spdx.json"))
+				Expect(added[path]).To(Equal("some-sbom"))
+			})
+		})
+
 		Context("when a file to embed is provided", func() {
 			BeforeEach(func() {
 				dirInfo := &fakes.FileInfo{}
@@ -345,7 +565,7 @@ releases:
 						return NewBuffer(bytes.NewBufferString("contents-of-embedded-file")), nil
 					}
 
-					return NewBuffer(bytes.NewBufferString("contents-of-non-embedded-file")), nil
+					return NewBuffer(bytes.NewBufferString(strings.TrimSuffix(filepath.Base(path), ".tgz"))), nil
 				}
 			})
 
@@ -358,7 +578,7 @@ releases:
 					StubReleases:         false,
 				}
 
-				err := tileWriter.Write([]byte("generated-metadata-contents"), input)
+				_, err := tileWriter.Write([]byte(releasesMetadata), input)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(logger.PrintfCall.Receives.LogLines).To(Equal([]string{
@@ -410,7 +630,7 @@ releases:
 						return NewBuffer(bytes.NewBufferString("contents-of-embedded-file-2")), nil
 					}
 
-					return NewBuffer(bytes.NewBufferString("contents-of-non-embedded-file")), nil
+					return NewBuffer(bytes.NewBufferString(strings.TrimSuffix(filepath.Base(path), ".tgz"))), nil
 				}
 			})
 
@@ -423,7 +643,7 @@ releases:
 					StubReleases:         false,
 				}
 
-				err := tileWriter.Write([]byte("generated-metadata-contents"), input)
+				_, err := tileWriter.Write([]byte(releasesMetadata), input)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(logger.PrintfCall.Receives.LogLines).To(Equal([]string{
@@ -459,7 +679,7 @@ releases:
 						OutputFile: outputFile,
 					}
 
-					err := tileWriter.Write([]byte{}, input)
+					_, err := tileWriter.Write([]byte{}, input)
 					Expect(err).To(HaveOccurred())
 					Expect(err).To(MatchError("boom!"))
 				})
@@ -474,7 +694,7 @@ releases:
 				})
 				Context("when the generated metadata is invalid", func() {
 					It("returns the error", func() {
-						err := tileWriter.Write([]byte("generated-metadata"), input)
+						_, err := tileWriter.Write([]byte("generated-metadata"), input)
 						Expect(err).To(HaveOccurred())
 						Expect(err).To(MatchError(ContainSubstring("cannot unmarshal")))
 					})
@@ -485,7 +705,7 @@ releases:
 						zipper.AddReturnsOnCall(1, errors.New("failed to add file to zip"))
 					})
 					It("returns the error", func() {
-						err := tileWriter.Write([]byte("---\nreleases:\n- file: release-1.tgz"), input)
+						_, err := tileWriter.Write([]byte("---\nreleases:\n- file: release-1.tgz"), input)
 						Expect(err).To(HaveOccurred())
 						Expect(err).To(MatchError("failed to add file to zip"))
 
@@ -506,7 +726,7 @@ releases:
 						OutputFile:   outputFile,
 					}
 
-					err := tileWriter.Write([]byte{}, input)
+					_, err := tileWriter.Write([]byte{}, input)
 					Expect(err).To(HaveOccurred())
 					Expect(err).To(MatchError("failed to create folder"))
 
@@ -525,7 +745,7 @@ releases:
 							OutputFile:   outputFile,
 						}
 
-						err := tileWriter.Write([]byte{}, input)
+						_, err := tileWriter.Write([]byte{}, input)
 						Expect(err).To(HaveOccurred())
 						Expect(err).To(MatchError("failed to create folder"))
 
@@ -569,7 +789,7 @@ releases:
 				})
 
 				It("returns an error", func() {
-					err := tileWriter.Write([]byte("generated-metadata-contents"), input)
+					_, err := tileWriter.Write([]byte(releasesMetadata), input)
 					Expect(err).To(MatchError("failed to open release"))
 
 					Expect(filesystem.RemoveCallCount()).To(Equal(1))
@@ -582,7 +802,7 @@ releases:
 					})
 
 					It("returns an error", func() {
-						err := tileWriter.Write([]byte{}, input)
+						_, err := tileWriter.Write([]byte(releasesMetadata), input)
 						Expect(err).To(HaveOccurred())
 						Expect(err).To(MatchError("failed to open release"))
 
@@ -641,7 +861,7 @@ releases:
 				})
 
 				It("returns an error", func() {
-					err := tileWriter.Write([]byte{}, input)
+					_, err := tileWriter.Write([]byte{}, input)
 					Expect(err).To(MatchError("failed to open migration"))
 
 					Expect(filesystem.RemoveCallCount()).To(Equal(1))
@@ -654,7 +874,7 @@ releases:
 					})
 
 					It("returns an error", func() {
-						err := tileWriter.Write([]byte{}, input)
+						_, err := tileWriter.Write([]byte{}, input)
 						Expect(err).To(HaveOccurred())
 						Expect(err).To(MatchError("failed to open migration"))
 
@@ -698,7 +918,7 @@ releases:
 				})
 
 				It("returns an error", func() {
-					err := tileWriter.Write([]byte("generated-metadata-contents"), input)
+					_, err := tileWriter.Write([]byte("name: some-product"), input)
 					Expect(err).To(MatchError("failed to open embed"))
 
 					Expect(filesystem.RemoveCallCount()).To(Equal(1))
@@ -711,7 +931,7 @@ releases:
 					})
 
 					It("returns an error", func() {
-						err := tileWriter.Write([]byte{}, input)
+						_, err := tileWriter.Write([]byte{}, input)
 						Expect(err).To(HaveOccurred())
 						Expect(err).To(MatchError("failed to open embed"))
 
@@ -735,7 +955,7 @@ releases:
 						OutputFile:   outputFile,
 					}
 
-					err := tileWriter.Write([]byte{}, input)
+					_, err := tileWriter.Write([]byte{}, input)
 					Expect(err).To(MatchError("failed to add file to zip"))
 
 					Expect(filesystem.RemoveCallCount()).To(Equal(1))
@@ -753,7 +973,7 @@ releases:
 							OutputFile:   outputFile,
 						}
 
-						err := tileWriter.Write([]byte{}, input)
+						_, err := tileWriter.Write([]byte{}, input)
 						Expect(err).To(HaveOccurred())
 						Expect(err).To(MatchError("failed to add file to zip"))
 
@@ -778,7 +998,7 @@ releases:
 				})
 
 				It("returns an error", func() {
-					err := tileWriter.Write([]byte{}, input)
+					_, err := tileWriter.Write([]byte{}, input)
 					Expect(err).To(MatchError("failed to close the zip"))
 
 					Expect(filesystem.RemoveCallCount()).To(Equal(1))
@@ -791,7 +1011,7 @@ releases:
 					})
 
 					It("returns an error", func() {
-						err := tileWriter.Write([]byte{}, input)
+						_, err := tileWriter.Write([]byte{}, input)
 						Expect(err).To(HaveOccurred())
 						Expect(err).To(MatchError("failed to close the zip"))
 
@@ -806,10 +1026,10 @@ releases:
 	})
 })
 
-func checkReleaseFileContent(releaseContent string, stubbed bool, file io.Reader) {
+func checkReleaseFileContent(releaseContent string, stubbed bool, contents string) {
 	if stubbed == false {
-		Eventually(gbytes.BufferReader(file)).Should(gbytes.Say(releaseContent))
+		Expect(contents).To(Equal(releaseContent))
 	} else {
-		Eventually(gbytes.BufferReader(file)).Should(gbytes.Say(""))
+		Expect(contents).To(BeEmpty())
 	}
 }
@@ -4,9 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"path"
+	"path/filepath"
 
+	yamlConverter "github.com/ghodss/yaml"
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/kiln/builder"
+	"github.com/pivotal-cf/kiln/internal/baking"
+	"github.com/pivotal-cf/kiln/internal/sbom"
+	yaml "gopkg.in/yaml.v2"
 )
 
 //go:generate counterfeiter -o ./fakes/interpolator.go --fake-name Interpolator . interpolator
@@ -16,17 +22,17 @@ type interpolator interface {
 
 //go:generate counterfeiter -o ./fakes/tile_writer.go --fake-name TileWriter . tileWriter
 type tileWriter interface {
-	Write(generatedMetadataContents []byte, input builder.WriteInput) error
+	Write(generatedMetadataContents []byte, input builder.WriteInput) (digests builder.Digests, err error)
 }
 
 //go:generate counterfeiter -o ./fakes/bosh_variables_service.go --fake-name BOSHVariablesService . boshVariablesService
 type boshVariablesService interface {
-	FromDirectories(directories []string) (boshVariables map[string]interface{}, err error)
+	FromDirectories(directories []string, allowOverrides bool) (boshVariables map[string]interface{}, err error)
 }
 
 //go:generate counterfeiter -o ./fakes/releases_service.go --fake-name ReleasesService . releasesService
 type releasesService interface {
-	FromDirectories(directories []string) (releases map[string]interface{}, err error)
+	FromDirectories(directories []string, allowOverrides bool) (releases map[string]interface{}, err error)
 }
 
 //go:generate counterfeiter -o ./fakes/stemcell_service.go --fake-name StemcellService . stemcellService
@@ -38,32 +44,37 @@ type stemcellService interface {
 
 //go:generate counterfeiter -o ./fakes/template_variables_service.go --fake-name TemplateVariablesService . templateVariablesService
 type templateVariablesService interface {
-	FromPathsAndPairs(paths []string, pairs []string) (templateVariables map[string]interface{}, err error)
+	FromPathsAndPairs(paths []string, pairs []string, yamlPairs []string) (templateVariables map[string]interface{}, sources []baking.TemplateVariableSource, err error)
 }
 
 //go:generate counterfeiter -o ./fakes/forms_service.go --fake-name FormsService . formsService
 type formsService interface {
-	FromDirectories(directories []string) (forms map[string]interface{}, err error)
+	FromDirectories(directories []string, allowOverrides bool) (forms map[string]interface{}, err error)
 }
 
 //go:generate counterfeiter -o ./fakes/instance_groups_service.go --fake-name InstanceGroupsService . instanceGroupsService
 type instanceGroupsService interface {
-	FromDirectories(directories []string) (instanceGroups map[string]interface{}, err error)
+	FromDirectories(directories []string, allowOverrides bool) (instanceGroups map[string]interface{}, err error)
 }
 
 //go:generate counterfeiter -o ./fakes/jobs_service.go --fake-name JobsService . jobsService
 type jobsService interface {
-	FromDirectories(directories []string) (jobs map[string]interface{}, err error)
+	FromDirectories(directories []string, allowOverrides bool) (jobs map[string]interface{}, err error)
 }
 
 //go:generate counterfeiter -o ./fakes/properties_service.go --fake-name PropertiesService . propertiesService
 type propertiesService interface {
-	FromDirectories(directories []string) (properties map[string]interface{}, err error)
+	FromDirectories(directories []string, allowOverrides bool) (properties map[string]interface{}, err error)
 }
 
 //go:generate counterfeiter -o ./fakes/runtime_configs_service.go --fake-name RuntimeConfigsService . runtimeConfigsService
 type runtimeConfigsService interface {
-	FromDirectories(directories []string) (runtimeConfigs map[string]interface{}, err error)
+	FromDirectories(directories []string, allowOverrides bool) (runtimeConfigs map[string]interface{}, err error)
+}
+
+//go:generate counterfeiter -o ./fakes/partials_service.go --fake-name PartialsService . partialsService
+type partialsService interface {
+	FromDirectories(directories []string, allowOverrides bool) (partials map[string]interface{}, err error)
 }
 
 //go:generate counterfeiter -o ./fakes/icon_service.go --fake-name IconService . iconService
@@ -76,14 +87,48 @@ type metadataService interface {
 	Read(path string) (metadata []byte, err error)
 }
 
+//go:generate counterfeiter -o ./fakes/bake_config_service.go --fake-name BakeConfigService . bakeConfigService
+type bakeConfigService interface {
+	Read(path string) (config baking.BakeConfig, err error)
+}
+
+//go:generate counterfeiter -o ./fakes/ops_files_service.go --fake-name OpsFilesService . opsFilesService
+type opsFilesService interface {
+	Apply(paths []string, metadata []byte) (patchedMetadata []byte, err error)
+}
+
 //go:generate counterfeiter -o ./fakes/checksummer.go --fake-name Checksummer . checksummer
 type checksummer interface {
-	Sum(path string) error
+	Write(path string, digests builder.Digests, format string) error
+}
+
+//go:generate counterfeiter -o ./fakes/signer.go --fake-name Signer . signer
+type signer interface {
+	Sign(path, keyPath, sha256 string) error
+}
+
+//go:generate counterfeiter -o ./fakes/provenance_service.go --fake-name ProvenanceService . provenanceService
+type provenanceService interface {
+	Generate(input baking.ProvenanceInput) (document []byte, err error)
+}
+
+//go:generate counterfeiter -o ./fakes/migrations_service.go --fake-name MigrationsService . migrationsService
+type migrationsService interface {
+	Validate(directories []string, previousTile string) error
+}
+
+//go:generate counterfeiter -o ./fakes/sbom_service.go --fake-name SBOMService . sbomService
+type sbomService interface {
+	FromMetadata(format string, metadata []byte, kilnfile string, releases []builder.Part) (document []byte, err error)
 }
 
 type Bake struct {
 	interpolator      interpolator
 	checksummer       checksummer
+	signer            signer
+	sbom              sbomService
+	provenance        provenanceService
+	migrations        migrationsService
 	tileWriter        tileWriter
 	output            *log.Logger
 	templateVariables templateVariablesService
@@ -95,8 +140,11 @@ type Bake struct {
 	jobs              jobsService
 	properties        propertiesService
 	runtimeConfigs    runtimeConfigsService
+	partials          partialsService
 	icon              iconService
 	metadata          metadataService
+	bakeConfig        bakeConfigService
+	opsFiles          opsFilesService
 
 	Options struct {
 		Kilnfile           string   `short:"kf"  long:"kilnfile"                        description:"path to Kilnfile  (NOTE: mutually exclusive with --stemcell-directory)"`
@@ -104,7 +152,12 @@ type Bake struct {
 		OutputFile         string   `short:"o"  long:"output-file"                        description:"path to where the tile will be output"`
 		ReleaseDirectories []string `short:"rd" long:"releases-directory"               description:"path to a directory containing release tarballs"`
 
+		AllowEnv                 bool     `            long:"allow-env"                 description:"lets the env template helper read environment variables"`
+		AllowPartOverrides       bool     `            long:"allow-part-overrides"      description:"lets parts and releases in later directories replace ones with the same name in earlier directories"`
 		BOSHVariableDirectories  []string `short:"vd"  long:"bosh-variables-directory"  description:"path to a directory containing BOSH variables"`
+		ChecksumFormat           string   `            long:"checksum-format"           description:"format of the checksum files: hex or shasum" default:"hex"`
+		Checksums                []string `            long:"checksum"                  description:"writes a checksum of the output file computed while it is written: sha1, sha256 or sha512"`
+		Config                   string   `short:"c"   long:"config"                    description:"path to a bake config file"`
 		EmbedPaths               []string `short:"e"   long:"embed"                     description:"path to files to include in the tile /embed directory"`
 		FormDirectories          []string `short:"f"   long:"forms-directory"           description:"path to a directory containing forms"`
 		IconPath                 string   `short:"i"   long:"icon"                      description:"path to icon file"`
@@ -112,14 +165,23 @@ type Bake struct {
 		JobDirectories           []string `short:"j"   long:"jobs-directory"            description:"path to a directory containing jobs"`
 		MetadataOnly             bool     `short:"mo"  long:"metadata-only"             description:"don't build a tile, output the metadata to stdout"`
 		MigrationDirectories     []string `short:"md"  long:"migrations-directory"      description:"path to a directory containing migrations"`
+		OpsFiles                 []string `short:"op"  long:"ops-file"                  description:"path to an ops file to apply to the interpolated metadata"`
+		PartialDirectories       []string `short:"pa"  long:"partials-directory"        description:"path to a directory containing partials"`
+		PreviousTile             string   `            long:"previous-tile"             description:"path to the previously shipped tile, migrations it does not contain must sort after the ones it does"`
+		PrintVariables           bool     `            long:"print-variables"           description:"prints each template variable and where its value came from, then exits"`
 		PropertyDirectories      []string `short:"pd"  long:"properties-directory"      description:"path to a directory containing property blueprints"`
+		Provenance               bool     `            long:"provenance"                description:"embeds a document in the tile recording the kiln version, git commit, arguments, variables and input file hashes of the bake"`
 		RuntimeConfigDirectories []string `short:"rcd" long:"runtime-configs-directory" description:"path to a directory containing runtime configs"`
-		Sha256                   bool     `            long:"sha256"                    description:"calculates a SHA256 checksum of the output file"`
+		SBOM                     string   `            long:"sbom"                      description:"embeds a software bill of materials in the tile: cyclonedx or spdx"`
+		Sha256                   bool     `            long:"sha256"                    description:"calculates a SHA256 checksum of the output file, the same as --checksum sha256"`
+		SigningKey               string   `            long:"signing-key"               description:"path to an ed25519 or PGP private key used to write a detached signature of the output file"`
 		StemcellTarball          string   `short:"st"  long:"stemcell-tarball"          description:"deprecated -- path to a stemcell tarball  (NOTE: mutually exclusive with --kilnfile)"`
 		StemcellsDirectories     []string `short:"sd"  long:"stemcells-directory"       description:"path to a directory containing stemcells  (NOTE: mutually exclusive with --kilnfile or --stemcell-tarball)"`
+		Strict                   bool     `            long:"strict"                    description:"fails on undefined variables and on variables or parts that are never used"`
 		StubReleases             bool     `short:"sr"  long:"stub-releases"             description:"skips importing release tarballs into the tile"`
 		VariableFiles            []string `short:"vf"  long:"variables-file"            description:"path to a file containing variables to interpolate"`
 		Variables                []string `short:"vr"  long:"variable"                  description:"key value pairs of variables to interpolate"`
+		VariablesYAML            []string `short:"vy"  long:"variable-yaml"             description:"key value pairs of variables to interpolate, with the value parsed as YAML"`
 		Version                  string   `short:"v"   long:"version"                   description:"version of the tile"`
 	}
 }
@@ -137,15 +199,26 @@ func NewBake(
 	jobsService jobsService,
 	propertiesService propertiesService,
 	runtimeConfigsService runtimeConfigsService,
+	partialsService partialsService,
 	iconService iconService,
 	metadataService metadataService,
+	bakeConfigService bakeConfigService,
+	opsFilesService opsFilesService,
 	checksummer checksummer,
+	signer signer,
+	sbomService sbomService,
+	provenanceService provenanceService,
+	migrationsService migrationsService,
 ) Bake {
 
 	return Bake{
 		interpolator:      interpolator,
 		tileWriter:        tileWriter,
 		checksummer:       checksummer,
+		signer:            signer,
+		sbom:              sbomService,
+		provenance:        provenanceService,
+		migrations:        migrationsService,
 		output:            output,
 		templateVariables: templateVariablesService,
 		boshVariables:     boshVariablesService,
@@ -156,8 +229,11 @@ func NewBake(
 		jobs:              jobsService,
 		properties:        propertiesService,
 		runtimeConfigs:    runtimeConfigsService,
+		partials:          partialsService,
 		icon:              iconService,
 		metadata:          metadataService,
+		bakeConfig:        bakeConfigService,
+		opsFiles:          opsFilesService,
 	}
 }
 
@@ -171,10 +247,19 @@ func (b Bake) Execute(args []string) error {
 		return errors.New("--jobs-directory flag requires --instance-groups-directory to also be specified")
 	}
 
-	if b.Options.OutputFile == "" && !b.Options.MetadataOnly {
+	config, err := b.bakeConfig.Read(b.Options.Config)
+	if err != nil {
+		return fmt.Errorf("failed to read bake config: %s", err)
+	}
+
+	if b.Options.OutputFile == "" && len(config.Variants) == 0 && !b.Options.MetadataOnly && !b.Options.PrintVariables {
 		return errors.New("--output-file must be provided unless using --metadata-only")
 	}
 
+	if b.Options.OutputFile != "" && len(config.Variants) > 0 {
+		return errors.New("--output-file cannot be provided when the bake config has variants")
+	}
+
 	if b.Options.Kilnfile != "" && b.Options.StemcellTarball != "" {
 		return errors.New("--kilnfile cannot be provided when using --stemcell-tarball")
 	}
@@ -191,12 +276,58 @@ func (b Bake) Execute(args []string) error {
 		return errors.New("--output-file cannot be provided when using --metadata-only")
 	}
 
+	if b.Options.ChecksumFormat != baking.ChecksumFormatHex && b.Options.ChecksumFormat != baking.ChecksumFormatShasum {
+		return fmt.Errorf("--checksum-format must be %s or %s", baking.ChecksumFormatHex, baking.ChecksumFormatShasum)
+	}
+
+	if b.Options.SBOM != "" && sbom.ValidateFormat(b.Options.SBOM) != nil {
+		return fmt.Errorf("--sbom must be %s or %s", sbom.FormatCycloneDX, sbom.FormatSPDX)
+	}
+
 	// TODO: Remove check after deprecation of --stemcell-tarball
 	if b.Options.StemcellTarball != "" {
 		b.output.Println("warning: --stemcell-tarball is being deprecated in favor of --stemcells-directory")
 	}
 
-	releaseManifests, err := b.releases.FromDirectories(b.Options.ReleaseDirectories)
+	variants := config.Variants
+	if len(variants) == 0 {
+		variants = []baking.BakeVariant{{OutputFile: b.Options.OutputFile}}
+	}
+
+	variantVariables := make([]map[string]interface{}, len(variants))
+	for i, variant := range variants {
+		variableFiles := append(append([]string{}, b.Options.VariableFiles...), variant.VariableFiles...)
+
+		templateVariables, templateVariableSources, err := b.templateVariables.FromPathsAndPairs(variableFiles, b.Options.Variables, b.Options.VariablesYAML)
+		if err != nil {
+			return variantError(variant, fmt.Errorf("failed to parse template variables: %s", err))
+		}
+		variantVariables[i] = templateVariables
+
+		if b.Options.PrintVariables {
+			if variant.Name != "" {
+				b.output.Printf("# %s\n", variant.Name)
+			}
+
+			err = b.printVariables(templateVariableSources)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if b.Options.PrintVariables {
+		return nil
+	}
+
+	if !b.Options.MetadataOnly && (len(b.Options.MigrationDirectories) > 0 || b.Options.PreviousTile != "") {
+		err = b.migrations.Validate(b.Options.MigrationDirectories, b.Options.PreviousTile)
+		if err != nil {
+			return fmt.Errorf("failed to validate migrations: %s", err)
+		}
+	}
+
+	releaseManifests, err := b.releases.FromDirectories(b.Options.ReleaseDirectories, b.Options.AllowPartOverrides)
 	if err != nil {
 		return fmt.Errorf("failed to parse releases: %s", err)
 	}
@@ -215,41 +346,41 @@ func (b Bake) Execute(args []string) error {
 		return fmt.Errorf("failed to parse stemcell: %s", err)
 	}
 
-	templateVariables, err := b.templateVariables.FromPathsAndPairs(b.Options.VariableFiles, b.Options.Variables)
-	if err != nil {
-		return fmt.Errorf("failed to parse template variables: %s", err)
-	}
-
-	boshVariables, err := b.boshVariables.FromDirectories(b.Options.BOSHVariableDirectories)
+	boshVariables, err := b.boshVariables.FromDirectories(b.Options.BOSHVariableDirectories, b.Options.AllowPartOverrides)
 	if err != nil {
 		return fmt.Errorf("failed to parse bosh variables: %s", err)
 	}
 
-	forms, err := b.forms.FromDirectories(b.Options.FormDirectories)
+	forms, err := b.forms.FromDirectories(b.Options.FormDirectories, b.Options.AllowPartOverrides)
 	if err != nil {
 		return fmt.Errorf("failed to parse forms: %s", err)
 	}
 
-	instanceGroups, err := b.instanceGroups.FromDirectories(b.Options.InstanceGroupDirectories)
+	instanceGroups, err := b.instanceGroups.FromDirectories(b.Options.InstanceGroupDirectories, b.Options.AllowPartOverrides)
 	if err != nil {
 		return fmt.Errorf("failed to parse instance groups: %s", err)
 	}
 
-	jobs, err := b.jobs.FromDirectories(b.Options.JobDirectories)
+	jobs, err := b.jobs.FromDirectories(b.Options.JobDirectories, b.Options.AllowPartOverrides)
 	if err != nil {
 		return fmt.Errorf("failed to parse jobs: %s", err)
 	}
 
-	propertyBlueprints, err := b.properties.FromDirectories(b.Options.PropertyDirectories)
+	propertyBlueprints, err := b.properties.FromDirectories(b.Options.PropertyDirectories, b.Options.AllowPartOverrides)
 	if err != nil {
 		return fmt.Errorf("failed to parse properties: %s", err)
 	}
 
-	runtimeConfigs, err := b.runtimeConfigs.FromDirectories(b.Options.RuntimeConfigDirectories)
+	runtimeConfigs, err := b.runtimeConfigs.FromDirectories(b.Options.RuntimeConfigDirectories, b.Options.AllowPartOverrides)
 	if err != nil {
 		return fmt.Errorf("failed to parse runtime configs: %s", err)
 	}
 
+	partials, err := b.partials.FromDirectories(b.Options.PartialDirectories, b.Options.AllowPartOverrides)
+	if err != nil {
+		return fmt.Errorf("failed to parse partials: %s", err)
+	}
+
 	icon, err := b.icon.Encode(b.Options.IconPath)
 	if err != nil {
 		return fmt.Errorf("failed to encode icon: %s", err)
@@ -260,9 +391,8 @@ func (b Bake) Execute(args []string) error {
 		return fmt.Errorf("failed to read metadata: %s", err)
 	}
 
-	interpolatedMetadata, err := b.interpolator.Interpolate(builder.InterpolateInput{
+	input := builder.InterpolateInput{
 		Version:            b.Options.Version,
-		Variables:          templateVariables,
 		BOSHVariables:      boshVariables,
 		ReleaseManifests:   releaseManifests,
 		StemcellManifests:  stemcellManifests,
@@ -273,38 +403,221 @@ func (b Bake) Execute(args []string) error {
 		Jobs:               jobs,
 		PropertyBlueprints: propertyBlueprints,
 		RuntimeConfigs:     runtimeConfigs,
+		Partials:           partials,
 		StubReleases:       b.Options.StubReleases,
-	}, metadata)
+		MetadataPath:       b.Options.Metadata,
+		AllowEnv:           b.Options.AllowEnv,
+		Strict:             b.Options.Strict,
+	}
+
+	// The releases, stemcells and parts above are shared by every variant, so
+	// release tarballs are only read once per bake. A variant's releases limit
+	// the release helper during interpolation, before its ops files apply.
+	for i, variant := range variants {
+		input.Variables = variantVariables[i]
+
+		input.ReleaseManifests, err = selectReleases(releaseManifests, variant.Releases, b.Options.StubReleases)
+		if err != nil {
+			return variantError(variant, err)
+		}
+
+		opsFiles := append(append(append([]string{}, config.OpsFiles...), variant.OpsFiles...), b.Options.OpsFiles...)
+
+		generatedFiles := map[string][]byte{}
+		if b.Options.Provenance && !b.Options.MetadataOnly {
+			generatedFiles[baking.ProvenanceFile], err = b.provenance.Generate(baking.ProvenanceInput{
+				Variant:             variant.Name,
+				Arguments:           args,
+				Variables:           input.Variables,
+				Kilnfile:            b.Options.Kilnfile,
+				RepositoryDirectory: filepath.Dir(b.Options.Metadata),
+				Files:               b.inputFiles(variant, opsFiles),
+			})
+			if err != nil {
+				return variantError(variant, fmt.Errorf("failed to generate provenance: %s", err))
+			}
+		}
+
+		err = b.bakeVariant(variant, input, metadata, opsFiles, generatedFiles)
+		if err != nil {
+			return variantError(variant, err)
+		}
+	}
+
+	return nil
+}
+
+// inputFiles returns the files and directories read to bake the variant,
+// other than releases and stemcells.
+func (b Bake) inputFiles(variant baking.BakeVariant, opsFiles []string) []string {
+	var files []string
+	for _, file := range []string{b.Options.Config, b.Options.Metadata, b.Options.IconPath} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	for _, paths := range [][]string{
+		b.Options.VariableFiles,
+		variant.VariableFiles,
+		opsFiles,
+		b.Options.BOSHVariableDirectories,
+		b.Options.FormDirectories,
+		b.Options.InstanceGroupDirectories,
+		b.Options.JobDirectories,
+		b.Options.PartialDirectories,
+		b.Options.PropertyDirectories,
+		b.Options.RuntimeConfigDirectories,
+		b.Options.MigrationDirectories,
+	} {
+		files = append(files, paths...)
+	}
+
+	return files
+}
+
+func (b Bake) bakeVariant(variant baking.BakeVariant, input builder.InterpolateInput, metadata []byte, opsFiles []string, generatedFiles map[string][]byte) error {
+	interpolatedMetadata, err := b.interpolator.Interpolate(input, metadata)
 	if err != nil {
 		return err
 	}
 
+	interpolatedMetadata, err = b.opsFiles.Apply(opsFiles, interpolatedMetadata)
+	if err != nil {
+		return fmt.Errorf("failed to apply ops files: %s", err)
+	}
+
 	if b.Options.MetadataOnly {
+		if variant.Name != "" {
+			b.output.Printf("--- # %s\n", variant.Name)
+		}
+
 		b.output.Printf("%s", interpolatedMetadata)
 		return nil
 	}
 
-	err = b.tileWriter.Write(interpolatedMetadata, builder.WriteInput{
-		OutputFile:           b.Options.OutputFile,
-		StubReleases:         b.Options.StubReleases,
-		MigrationDirectories: b.Options.MigrationDirectories,
-		ReleaseDirectories:   b.Options.ReleaseDirectories,
-		EmbedPaths:           b.Options.EmbedPaths,
+	checksums := append([]string{}, b.Options.Checksums...)
+	if b.Options.Sha256 {
+		checksums = append(checksums, "sha256")
+	}
+
+	if b.Options.SBOM != "" {
+		document, err := b.sbom.FromMetadata(b.Options.SBOM, interpolatedMetadata, b.Options.Kilnfile, releaseParts(input.ReleaseManifests))
+		if err != nil {
+			return fmt.Errorf("failed to generate SBOM: %s", err)
+		}
+
+		generatedFiles[path.Join("sbom", sbom.FileName(b.Options.SBOM))] = document
+	}
+
+	if len(generatedFiles) == 0 {
+		generatedFiles = nil
+	}
+
+	// The signer signs the SHA256 digest computed while the tile is written
+	// rather than reading the tile again.
+	hashes := checksums
+	if b.Options.SigningKey != "" {
+		hashes = append(append([]string{}, checksums...), "sha256")
+	}
+
+	digests, err := b.tileWriter.Write(interpolatedMetadata, builder.WriteInput{
+		OutputFile:            variant.OutputFile,
+		StubReleases:          b.Options.StubReleases,
+		MigrationDirectories:  b.Options.MigrationDirectories,
+		ReleaseDirectories:    b.Options.ReleaseDirectories,
+		AllowReleaseOverrides: b.Options.AllowPartOverrides,
+		EmbedPaths:            b.Options.EmbedPaths,
+		Checksums:             hashes,
+		GeneratedFiles:        generatedFiles,
 	})
 	if err != nil {
 		return err
 	}
 
-	if b.Options.Sha256 {
-		err = b.checksummer.Sum(b.Options.OutputFile)
+	if len(checksums) > 0 {
+		requested := builder.Digests{}
+		for _, algorithm := range checksums {
+			requested[algorithm] = digests[algorithm]
+		}
+
+		err = b.checksummer.Write(variant.OutputFile, requested, b.Options.ChecksumFormat)
+		if err != nil {
+			return fmt.Errorf("failed to write checksums: %s", err)
+		}
+	}
+
+	if b.Options.SigningKey != "" {
+		err = b.signer.Sign(variant.OutputFile, b.Options.SigningKey, digests["sha256"])
 		if err != nil {
-			return fmt.Errorf("failed to calculate checksum: %s", err)
+			return fmt.Errorf("failed to sign tile: %s", err)
 		}
 	}
 
 	return nil
 }
 
+func (b Bake) printVariables(sources []baking.TemplateVariableSource) error {
+	for _, variable := range sources {
+		value, err := yaml.Marshal(variable.Value)
+		if err != nil {
+			return err // should never happen
+		}
+
+		value, err = yamlConverter.YAMLToJSON(value)
+		if err != nil {
+			return err // should never happen
+		}
+
+		b.output.Printf("%s: %s (from %s)\n", variable.Name, value, variable.Source)
+	}
+
+	return nil
+}
+
+// selectReleases returns the release manifests named by a variant, or all of
+// them when the variant does not name any.
+func selectReleases(releaseManifests map[string]interface{}, names []string, stubReleases bool) (map[string]interface{}, error) {
+	if len(names) == 0 {
+		return releaseManifests, nil
+	}
+
+	selected := map[string]interface{}{}
+	for _, name := range names {
+		releaseManifest, ok := releaseManifests[name]
+		if !ok {
+			if stubReleases {
+				continue
+			}
+			return nil, fmt.Errorf("could not find release with name %q in the releases directories", name)
+		}
+		selected[name] = releaseManifest
+	}
+
+	return selected, nil
+}
+
+// releaseParts returns the release parts read from the releases directories,
+// skipping stubbed releases.
+func releaseParts(releaseManifests map[string]interface{}) []builder.Part {
+	var parts []builder.Part
+	for _, releaseManifest := range releaseManifests {
+		if part, ok := releaseManifest.(builder.Part); ok {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
+
+func variantError(variant baking.BakeVariant, err error) error {
+	if variant.Name == "" {
+		return err
+	}
+
+	return fmt.Errorf("variant %q: %w", variant.Name, err)
+}
+
 func (b Bake) Usage() jhanda.Usage {
 	return jhanda.Usage{
 		Description:      "Bakes tile metadata, stemcell, releases, and migrations into a format that can be consumed by OpsManager.",
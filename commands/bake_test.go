@@ -11,10 +11,12 @@ import (
 	"github.com/pivotal-cf/kiln/builder"
 	. "github.com/pivotal-cf/kiln/commands"
 	"github.com/pivotal-cf/kiln/commands/fakes"
+	"github.com/pivotal-cf/kiln/internal/baking"
 	"gopkg.in/yaml.v2"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 	. "github.com/pivotal-cf-experimental/gomegamatchers"
 )
 
@@ -31,10 +33,17 @@ var _ = Describe("Bake", func() {
 		fakePropertiesService        *fakes.PropertiesService
 		fakeReleasesService          *fakes.ReleasesService
 		fakeRuntimeConfigsService    *fakes.RuntimeConfigsService
+		fakePartialsService          *fakes.PartialsService
 		fakeStemcellService          *fakes.StemcellService
 		fakeTemplateVariablesService *fakes.TemplateVariablesService
 		fakeTileWriter               *fakes.TileWriter
 		fakeChecksummer              *fakes.Checksummer
+		fakeSigner                   *fakes.Signer
+		fakeSBOMService              *fakes.SBOMService
+		fakeProvenanceService        *fakes.ProvenanceService
+		fakeMigrationsService        *fakes.MigrationsService
+		fakeBakeConfigService        *fakes.BakeConfigService
+		fakeOpsFilesService          *fakes.OpsFilesService
 
 		otherReleasesDirectory string
 		someReleasesDirectory  string
@@ -69,15 +78,22 @@ var _ = Describe("Bake", func() {
 		fakePropertiesService = &fakes.PropertiesService{}
 		fakeReleasesService = &fakes.ReleasesService{}
 		fakeRuntimeConfigsService = &fakes.RuntimeConfigsService{}
+		fakePartialsService = &fakes.PartialsService{}
 		fakeStemcellService = &fakes.StemcellService{}
 		fakeTemplateVariablesService = &fakes.TemplateVariablesService{}
 		fakeTileWriter = &fakes.TileWriter{}
 		fakeChecksummer = &fakes.Checksummer{}
+		fakeSigner = &fakes.Signer{}
+		fakeSBOMService = &fakes.SBOMService{}
+		fakeProvenanceService = &fakes.ProvenanceService{}
+		fakeMigrationsService = &fakes.MigrationsService{}
+		fakeBakeConfigService = &fakes.BakeConfigService{}
+		fakeOpsFilesService = &fakes.OpsFilesService{}
 
 		fakeTemplateVariablesService.FromPathsAndPairsReturns(map[string]interface{}{
 			"some-variable-from-file": "some-variable-value-from-file",
 			"some-variable":           "some-variable-value",
-		}, nil)
+		}, nil, nil)
 
 		fakeReleasesService.FromDirectoriesReturns(map[string]interface{}{
 			"some-release-1": builder.ReleaseManifest{
@@ -144,12 +160,28 @@ var _ = Describe("Bake", func() {
 			},
 		}, nil)
 
+		fakePartialsService.FromDirectoriesReturns(map[string]interface{}{
+			"some-partial": builder.Metadata{
+				"name":       "some-partial",
+				"parameters": []string{"some-parameter"},
+				"template":   builder.Metadata{"name": `$( param "some-parameter" )`},
+			},
+		}, nil)
+
 		fakeIconService.EncodeReturns("some-encoded-icon", nil)
 
 		fakeMetadataService.ReadReturns([]byte("some-metadata"), nil)
 
 		fakeInterpolator.InterpolateReturns([]byte("some-interpolated-metadata"), nil)
 
+		fakeBakeConfigService.ReadReturns(baking.BakeConfig{
+			OpsFiles: []string{"some-config-dir/some-config-ops-file.yml"},
+		}, nil)
+
+		fakeOpsFilesService.ApplyReturns([]byte("some-patched-metadata"), nil)
+
+		fakeTileWriter.WriteReturns(builder.Digests{"sha256": "some-sha256"}, nil)
+
 		bake = NewBake(
 			fakeInterpolator,
 			fakeTileWriter,
@@ -163,9 +195,16 @@ var _ = Describe("Bake", func() {
 			fakeJobsService,
 			fakePropertiesService,
 			fakeRuntimeConfigsService,
+			fakePartialsService,
 			fakeIconService,
 			fakeMetadataService,
+			fakeBakeConfigService,
+			fakeOpsFilesService,
 			fakeChecksummer,
+			fakeSigner,
+			fakeSBOMService,
+			fakeProvenanceService,
+			fakeMigrationsService,
 		)
 	})
 
@@ -183,6 +222,7 @@ var _ = Describe("Bake", func() {
 				"--jobs-directory", "some-jobs-directory",
 				"--metadata", "some-metadata",
 				"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+				"--partials-directory", "some-partials-directory",
 				"--properties-directory", "some-properties-directory",
 				"--releases-directory", otherReleasesDirectory,
 				"--releases-directory", someReleasesDirectory,
@@ -195,14 +235,20 @@ var _ = Describe("Bake", func() {
 				"--migrations-directory", "some-other-migrations-directory",
 				"--variable", "some-variable=some-variable-value",
 				"--variables-file", "some-variables-file",
+				"--variable-yaml", "some-yaml-variable=[1, 2]",
 				"--sha256",
+				"--allow-env",
+				"--strict",
+				"--config", "some-config-dir/some-bake-config.yml",
+				"--ops-file", "some-ops-file.yml",
 			})
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeTemplateVariablesService.FromPathsAndPairsCallCount()).To(Equal(1))
-			varFiles, variables := fakeTemplateVariablesService.FromPathsAndPairsArgsForCall(0)
+			varFiles, variables, yamlVariables := fakeTemplateVariablesService.FromPathsAndPairsArgsForCall(0)
 			Expect(varFiles).To(Equal([]string{"some-variables-file"}))
 			Expect(variables).To(Equal([]string{"some-variable=some-variable-value"}))
+			Expect(yamlVariables).To(Equal([]string{"some-yaml-variable=[1, 2]"}))
 
 			Expect(fakeBOSHVariablesService.FromDirectoriesCallCount()).To(Equal(1))
 			Expect(fakeBOSHVariablesService.FromDirectoriesArgsForCall(0)).To(Equal([]string{
@@ -234,12 +280,18 @@ var _ = Describe("Bake", func() {
 				"some-runtime-configs-directory",
 			}))
 
+			Expect(fakePartialsService.FromDirectoriesCallCount()).To(Equal(1))
+			Expect(fakePartialsService.FromDirectoriesArgsForCall(0)).To(Equal([]string{"some-partials-directory"}))
+
 			Expect(fakeIconService.EncodeCallCount()).To(Equal(1))
 			Expect(fakeIconService.EncodeArgsForCall(0)).To(Equal("some-icon-path"))
 
 			Expect(fakeMetadataService.ReadCallCount()).To(Equal(1))
 			Expect(fakeMetadataService.ReadArgsForCall(0)).To(Equal("some-metadata"))
 
+			Expect(fakeBakeConfigService.ReadCallCount()).To(Equal(1))
+			Expect(fakeBakeConfigService.ReadArgsForCall(0)).To(Equal("some-config-dir/some-bake-config.yml"))
+
 			Expect(fakeInterpolator.InterpolateCallCount()).To(Equal(1))
 
 			input, metadata := fakeInterpolator.InterpolateArgsForCall(0)
@@ -307,24 +359,75 @@ var _ = Describe("Bake", func() {
 						"runtime_config": "some-addon-runtime-config",
 					},
 				},
+				Partials: map[string]interface{}{
+					"some-partial": builder.Metadata{
+						"name":       "some-partial",
+						"parameters": []string{"some-parameter"},
+						"template":   builder.Metadata{"name": `$( param "some-parameter" )`},
+					},
+				},
+				MetadataPath: "some-metadata",
+				AllowEnv:     true,
+				Strict:       true,
 			}))
 
 			Expect(string(metadata)).To(Equal("some-metadata"))
 
+			Expect(fakeOpsFilesService.ApplyCallCount()).To(Equal(1))
+			opsFiles, metadata := fakeOpsFilesService.ApplyArgsForCall(0)
+			Expect(opsFiles).To(Equal([]string{"some-config-dir/some-config-ops-file.yml", "some-ops-file.yml"}))
+			Expect(string(metadata)).To(Equal("some-interpolated-metadata"))
+
 			Expect(fakeTileWriter.WriteCallCount()).To(Equal(1))
 			metadata, writeInput := fakeTileWriter.WriteArgsForCall(0)
-			Expect(string(metadata)).To(Equal("some-interpolated-metadata"))
+			Expect(string(metadata)).To(Equal("some-patched-metadata"))
 			Expect(writeInput).To(Equal(builder.WriteInput{
 				OutputFile:           filepath.Join("some-output-dir", "some-product-file-1.2.3-build.4"),
 				StubReleases:         false,
 				MigrationDirectories: []string{"some-migrations-directory", "some-other-migrations-directory"},
 				ReleaseDirectories:   []string{otherReleasesDirectory, someReleasesDirectory},
 				EmbedPaths:           []string{"some-embed-path"},
+				Checksums:            []string{"sha256"},
 			}))
 
-			Expect(fakeChecksummer.SumCallCount()).To(Equal(1))
-			outputFilePath := fakeChecksummer.SumArgsForCall(0)
+			Expect(fakeChecksummer.WriteCallCount()).To(Equal(1))
+			outputFilePath, digests, format := fakeChecksummer.WriteArgsForCall(0)
 			Expect(outputFilePath).To(Equal(filepath.Join("some-output-dir", "some-product-file-1.2.3-build.4")))
+			Expect(digests).To(Equal(builder.Digests{"sha256": "some-sha256"}))
+			Expect(format).To(Equal("hex"))
+		})
+
+		Context("when the --checksum flag is specified", func() {
+			It("asks the tile writer for each checksum and writes them in the given format", func() {
+				err := bake.Execute([]string{
+					"--metadata", "some-metadata",
+					"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+					"--releases-directory", someReleasesDirectory,
+					"--checksum", "sha1",
+					"--checksum", "sha512",
+					"--checksum-format", "shasum",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, writeInput := fakeTileWriter.WriteArgsForCall(0)
+				Expect(writeInput.Checksums).To(Equal([]string{"sha1", "sha512"}))
+
+				_, _, format := fakeChecksummer.WriteArgsForCall(0)
+				Expect(format).To(Equal("shasum"))
+			})
+
+			Context("when the checksum format is not supported", func() {
+				It("returns an error", func() {
+					err := bake.Execute([]string{
+						"--metadata", "some-metadata",
+						"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+						"--checksum", "sha1",
+						"--checksum-format", "bsd",
+					})
+					Expect(err).To(MatchError("--checksum-format must be hex or shasum"))
+					Expect(fakeTileWriter.WriteCallCount()).To(Equal(0))
+				})
+			})
 		})
 
 		Context("when the --sha256 flag is not specified", func() {
@@ -352,7 +455,204 @@ var _ = Describe("Bake", func() {
 				})
 				Expect(err).NotTo(HaveOccurred())
 
-				Expect(fakeChecksummer.SumCallCount()).To(Equal(0))
+				Expect(fakeChecksummer.WriteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the --signing-key flag is specified", func() {
+			It("signs the output file with the key", func() {
+				err := bake.Execute([]string{
+					"--metadata", "some-metadata",
+					"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+					"--releases-directory", someReleasesDirectory,
+					"--sha256",
+					"--signing-key", "some-signing-key.pem",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSigner.SignCallCount()).To(Equal(1))
+				path, keyPath, sha256 := fakeSigner.SignArgsForCall(0)
+				Expect(path).To(Equal("some-output-dir/some-product-file-1.2.3-build.4"))
+				Expect(keyPath).To(Equal("some-signing-key.pem"))
+				Expect(sha256).To(Equal("some-sha256"))
+			})
+
+			It("signs the SHA256 digest computed while the tile is written", func() {
+				err := bake.Execute([]string{
+					"--metadata", "some-metadata",
+					"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+					"--releases-directory", someReleasesDirectory,
+					"--signing-key", "some-signing-key.pem",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, writeInput := fakeTileWriter.WriteArgsForCall(0)
+				Expect(writeInput.Checksums).To(Equal([]string{"sha256"}))
+				Expect(fakeChecksummer.WriteCallCount()).To(Equal(0))
+
+				_, _, sha256 := fakeSigner.SignArgsForCall(0)
+				Expect(sha256).To(Equal("some-sha256"))
+			})
+		})
+
+		Context("when the --sbom flag is specified", func() {
+			It("embeds the SBOM of the baked metadata in the tile", func() {
+				releasePart := builder.Part{
+					File: "some-releases/release1.tgz",
+					Path: "some-releases/release1.tgz",
+					Name: "some-release-1",
+					Metadata: builder.ReleaseManifest{
+						Name:   "some-release-1",
+						File:   "release1.tgz",
+						SHA256: "some-sha256",
+					},
+				}
+				fakeReleasesService.FromDirectoriesReturns(map[string]interface{}{"some-release-1": releasePart}, nil)
+				fakeSBOMService.FromMetadataReturns([]byte("some-sbom"), nil)
+
+				err := bake.Execute([]string{
+					"--metadata", "some-metadata",
+					"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+					"--releases-directory", someReleasesDirectory,
+					"--kilnfile", "Kilnfile",
+					"--sbom", "spdx",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSBOMService.FromMetadataCallCount()).To(Equal(1))
+				format, metadata, kilnfile, releases := fakeSBOMService.FromMetadataArgsForCall(0)
+				Expect(format).To(Equal("spdx"))
+				Expect(metadata).To(Equal([]byte("some-patched-metadata")))
+				Expect(kilnfile).To(Equal("Kilnfile"))
+				Expect(releases).To(Equal([]builder.Part{releasePart}))
+
+				_, writeInput := fakeTileWriter.WriteArgsForCall(0)
+				Expect(writeInput.GeneratedFiles).To(Equal(map[string][]byte{
+					"sbom/sbom.spdx.json": []byte("some-sbom"),
+				}))
+			})
+
+			Context("when the format is not supported", func() {
+				It("returns an error", func() {
+					err := bake.Execute([]string{
+						"--metadata", "some-metadata",
+						"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+						"--sbom", "swid",
+					})
+					Expect(err).To(MatchError("--sbom must be cyclonedx or spdx"))
+					Expect(fakeTileWriter.WriteCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the SBOM cannot be generated", func() {
+				It("returns an error", func() {
+					fakeSBOMService.FromMetadataReturns(nil, errors.New("failed"))
+
+					err := bake.Execute([]string{
+						"--metadata", "some-metadata",
+						"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+						"--sbom", "cyclonedx",
+					})
+					Expect(err).To(MatchError("failed to generate SBOM: failed"))
+					Expect(fakeTileWriter.WriteCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when the --provenance flag is specified", func() {
+			var args []string
+
+			BeforeEach(func() {
+				fakeTemplateVariablesService.FromPathsAndPairsReturns(map[string]interface{}{"some-variable": "some-value"}, nil, nil)
+				fakeProvenanceService.GenerateReturns([]byte("some-provenance"), nil)
+
+				args = []string{
+					"--metadata", "some-dir/some-metadata",
+					"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+					"--releases-directory", someReleasesDirectory,
+					"--forms-directory", "some-forms-directory",
+					"--variables-file", "some-variables-file",
+					"--ops-file", "some-ops-file",
+					"--kilnfile", "Kilnfile",
+					"--provenance",
+				}
+			})
+
+			It("embeds a provenance document in the tile", func() {
+				err := bake.Execute(args)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeProvenanceService.GenerateCallCount()).To(Equal(1))
+				Expect(fakeProvenanceService.GenerateArgsForCall(0)).To(Equal(baking.ProvenanceInput{
+					Arguments:           args,
+					Variables:           map[string]interface{}{"some-variable": "some-value"},
+					Kilnfile:            "Kilnfile",
+					RepositoryDirectory: "some-dir",
+					Files:               []string{"some-dir/some-metadata", "some-variables-file", "some-config-dir/some-config-ops-file.yml", "some-ops-file", "some-forms-directory"},
+				}))
+
+				_, writeInput := fakeTileWriter.WriteArgsForCall(0)
+				Expect(writeInput.GeneratedFiles).To(Equal(map[string][]byte{
+					"provenance/provenance.json": []byte("some-provenance"),
+				}))
+			})
+
+			Context("when the provenance cannot be generated", func() {
+				It("returns an error", func() {
+					fakeProvenanceService.GenerateReturns(nil, errors.New("failed"))
+
+					err := bake.Execute(args)
+					Expect(err).To(MatchError("failed to generate provenance: failed"))
+					Expect(fakeTileWriter.WriteCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when the --migrations-directory and --previous-tile flags are specified", func() {
+			var args []string
+
+			BeforeEach(func() {
+				args = []string{
+					"--metadata", "some-metadata",
+					"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+					"--releases-directory", someReleasesDirectory,
+					"--migrations-directory", "some-migrations-directory",
+					"--migrations-directory", "other-migrations-directory",
+					"--previous-tile", "some-product-1.2.2.pivotal",
+				}
+			})
+
+			It("validates the migrations", func() {
+				err := bake.Execute(args)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeMigrationsService.ValidateCallCount()).To(Equal(1))
+				directories, previousTile := fakeMigrationsService.ValidateArgsForCall(0)
+				Expect(directories).To(Equal([]string{"some-migrations-directory", "other-migrations-directory"}))
+				Expect(previousTile).To(Equal("some-product-1.2.2.pivotal"))
+			})
+
+			Context("when the migrations are not valid", func() {
+				It("returns an error", func() {
+					fakeMigrationsService.ValidateReturns(errors.New("some-migration.js does not start with a timestamp"))
+
+					err := bake.Execute(args)
+					Expect(err).To(MatchError("failed to validate migrations: some-migration.js does not start with a timestamp"))
+					Expect(fakeTileWriter.WriteCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when the --signing-key flag is not specified", func() {
+			It("does not sign the output file", func() {
+				err := bake.Execute([]string{
+					"--metadata", "some-metadata",
+					"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+					"--releases-directory", someReleasesDirectory,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSigner.SignCallCount()).To(Equal(0))
 			})
 		})
 
@@ -415,7 +715,7 @@ var _ = Describe("Bake", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				generatedMetadataContents, _ := fakeTileWriter.WriteArgsForCall(0)
-				Expect(generatedMetadataContents).To(HelpfullyMatchYAML("some-interpolated-metadata"))
+				Expect(generatedMetadataContents).To(HelpfullyMatchYAML("some-patched-metadata"))
 			})
 		})
 
@@ -438,6 +738,187 @@ var _ = Describe("Bake", func() {
 			})
 		})
 
+		Context("when the --allow-part-overrides flag is specified", func() {
+			It("allows the services to override parts from earlier directories", func() {
+				err := bake.Execute([]string{
+					"--metadata", "some-metadata",
+					"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+					"--releases-directory", someReleasesDirectory,
+					"--forms-directory", "some-forms-directory",
+					"--forms-directory", "some-variant-forms-directory",
+					"--allow-part-overrides",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, allowOverrides := fakeReleasesService.FromDirectoriesArgsForCall(0)
+				Expect(allowOverrides).To(BeTrue())
+
+				_, writeInput := fakeTileWriter.WriteArgsForCall(0)
+				Expect(writeInput.AllowReleaseOverrides).To(BeTrue())
+
+				directories, allowOverrides := fakeFormsService.FromDirectoriesArgsForCall(0)
+				Expect(directories).To(Equal([]string{"some-forms-directory", "some-variant-forms-directory"}))
+				Expect(allowOverrides).To(BeTrue())
+
+				for _, fromDirectoriesArgsForCall := range []func(int) ([]string, bool){
+					fakeBOSHVariablesService.FromDirectoriesArgsForCall,
+					fakeInstanceGroupsService.FromDirectoriesArgsForCall,
+					fakeJobsService.FromDirectoriesArgsForCall,
+					fakePropertiesService.FromDirectoriesArgsForCall,
+					fakeRuntimeConfigsService.FromDirectoriesArgsForCall,
+					fakePartialsService.FromDirectoriesArgsForCall,
+				} {
+					_, allowOverrides := fromDirectoriesArgsForCall(0)
+					Expect(allowOverrides).To(BeTrue())
+				}
+			})
+		})
+
+		Context("when the --print-variables flag is specified", func() {
+			It("prints each template variable with its source and does not bake", func() {
+				fakeTemplateVariablesService.FromPathsAndPairsReturns(nil, []baking.TemplateVariableSource{
+					{Name: "network.name", Value: "some-network", Source: "some-variables-file"},
+					{Name: "ports", Value: []interface{}{80, 443}, Source: "--variable-yaml"},
+				}, nil)
+
+				output := gbytes.NewBuffer()
+				fakeLogger.SetOutput(output)
+
+				err := bake.Execute([]string{
+					"--metadata", "some-metadata",
+					"--variables-file", "some-variables-file",
+					"--variable-yaml", "ports=[80, 443]",
+					"--print-variables",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(output).To(gbytes.Say(`network.name: "some-network" \(from some-variables-file\)`))
+				Expect(output).To(gbytes.Say(`ports: \[80,443\] \(from --variable-yaml\)`))
+
+				Expect(fakeReleasesService.FromDirectoriesCallCount()).To(Equal(0))
+				Expect(fakeInterpolator.InterpolateCallCount()).To(Equal(0))
+				Expect(fakeTileWriter.WriteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the bake config has variants", func() {
+			BeforeEach(func() {
+				fakeReleasesService.FromDirectoriesReturns(map[string]interface{}{
+					"some-release":  builder.ReleaseManifest{Name: "some-release", Version: "1.2.3"},
+					"other-release": builder.ReleaseManifest{Name: "other-release", Version: "4.5.6"},
+				}, nil)
+
+				fakeBakeConfigService.ReadReturns(baking.BakeConfig{
+					OpsFiles: []string{"some-shared-ops-file.yml"},
+					Variants: []baking.BakeVariant{
+						{
+							Name:       "full",
+							OutputFile: "some-output-dir/full.pivotal",
+						},
+						{
+							Name:          "small",
+							OutputFile:    "some-output-dir/small.pivotal",
+							VariableFiles: []string{"small-variables.yml"},
+							OpsFiles:      []string{"small-ops-file.yml"},
+							Releases:      []string{"some-release"},
+						},
+					},
+				}, nil)
+
+				fakeTemplateVariablesService.FromPathsAndPairsReturnsOnCall(0, map[string]interface{}{"footprint": "full"}, nil, nil)
+				fakeTemplateVariablesService.FromPathsAndPairsReturnsOnCall(1, map[string]interface{}{"footprint": "small"}, nil, nil)
+			})
+
+			It("bakes a tile for each variant from inputs parsed once", func() {
+				err := bake.Execute([]string{
+					"--config", "some-bake-config.yml",
+					"--metadata", "some-metadata",
+					"--releases-directory", someReleasesDirectory,
+					"--variables-file", "some-variables-file",
+					"--ops-file", "some-ops-file.yml",
+					"--sha256",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeReleasesService.FromDirectoriesCallCount()).To(Equal(1))
+				Expect(fakeMetadataService.ReadCallCount()).To(Equal(1))
+
+				Expect(fakeTemplateVariablesService.FromPathsAndPairsCallCount()).To(Equal(2))
+				varFiles, _, _ := fakeTemplateVariablesService.FromPathsAndPairsArgsForCall(0)
+				Expect(varFiles).To(Equal([]string{"some-variables-file"}))
+				varFiles, _, _ = fakeTemplateVariablesService.FromPathsAndPairsArgsForCall(1)
+				Expect(varFiles).To(Equal([]string{"some-variables-file", "small-variables.yml"}))
+
+				Expect(fakeInterpolator.InterpolateCallCount()).To(Equal(2))
+				input, _ := fakeInterpolator.InterpolateArgsForCall(0)
+				Expect(input.Variables).To(Equal(map[string]interface{}{"footprint": "full"}))
+				Expect(input.ReleaseManifests).To(HaveLen(2))
+				input, _ = fakeInterpolator.InterpolateArgsForCall(1)
+				Expect(input.Variables).To(Equal(map[string]interface{}{"footprint": "small"}))
+				Expect(input.ReleaseManifests).To(Equal(map[string]interface{}{
+					"some-release": builder.ReleaseManifest{Name: "some-release", Version: "1.2.3"},
+				}))
+
+				Expect(fakeOpsFilesService.ApplyCallCount()).To(Equal(2))
+				opsFiles, _ := fakeOpsFilesService.ApplyArgsForCall(0)
+				Expect(opsFiles).To(Equal([]string{"some-shared-ops-file.yml", "some-ops-file.yml"}))
+				opsFiles, _ = fakeOpsFilesService.ApplyArgsForCall(1)
+				Expect(opsFiles).To(Equal([]string{"some-shared-ops-file.yml", "small-ops-file.yml", "some-ops-file.yml"}))
+
+				Expect(fakeTileWriter.WriteCallCount()).To(Equal(2))
+				_, writeInput := fakeTileWriter.WriteArgsForCall(0)
+				Expect(writeInput.OutputFile).To(Equal("some-output-dir/full.pivotal"))
+				_, writeInput = fakeTileWriter.WriteArgsForCall(1)
+				Expect(writeInput.OutputFile).To(Equal("some-output-dir/small.pivotal"))
+
+				Expect(fakeChecksummer.WriteCallCount()).To(Equal(2))
+				outputFile, _, _ := fakeChecksummer.WriteArgsForCall(1)
+				Expect(outputFile).To(Equal("some-output-dir/small.pivotal"))
+			})
+
+			It("prints the metadata of each variant as a separate document", func() {
+				output := gbytes.NewBuffer()
+				fakeLogger.SetOutput(output)
+
+				err := bake.Execute([]string{
+					"--config", "some-bake-config.yml",
+					"--metadata", "some-metadata",
+					"--metadata-only",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(output).To(gbytes.Say("--- # full\nsome-patched-metadata\n--- # small\nsome-patched-metadata"))
+				Expect(fakeTileWriter.WriteCallCount()).To(Equal(0))
+			})
+
+			Context("when a variant names a release that is not available", func() {
+				It("returns an error", func() {
+					fakeBakeConfigService.ReadReturns(baking.BakeConfig{
+						Variants: []baking.BakeVariant{
+							{Name: "small", OutputFile: "small.pivotal", Releases: []string{"missing-release"}},
+						},
+					}, nil)
+
+					err := bake.Execute([]string{
+						"--config", "some-bake-config.yml",
+						"--metadata", "some-metadata",
+					})
+					Expect(err).To(MatchError(`variant "small": could not find release with name "missing-release" in the releases directories`))
+				})
+			})
+
+			Context("when --output-file is also provided", func() {
+				It("returns an error", func() {
+					err := bake.Execute([]string{
+						"--config", "some-bake-config.yml",
+						"--metadata", "some-metadata",
+						"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+					})
+					Expect(err).To(MatchError("--output-file cannot be provided when the bake config has variants"))
+				})
+			})
+		})
+
 		Context("when Kilnfile is specified", func() {
 			It("renders the stemcell criteria in tile metadata from that specified the Kilnfile.lock", func() {
 				outputFile := "some-output-dir/some-product-file-1.2.3-build.4"
@@ -477,7 +958,7 @@ var _ = Describe("Bake", func() {
 		Context("failure cases", func() {
 			Context("when the template variables service errors", func() {
 				It("returns an error", func() {
-					fakeTemplateVariablesService.FromPathsAndPairsReturns(nil, errors.New("parsing template variables failed"))
+					fakeTemplateVariablesService.FromPathsAndPairsReturns(nil, nil, errors.New("parsing template variables failed"))
 
 					err := bake.Execute([]string{
 						"--metadata", "some-metadata",
@@ -489,6 +970,20 @@ var _ = Describe("Bake", func() {
 				})
 			})
 
+			Context("when the partials service fails", func() {
+				It("returns an error", func() {
+					fakePartialsService.FromDirectoriesReturns(nil, errors.New("parsing partials failed"))
+
+					err := bake.Execute([]string{
+						"--metadata", "some-metadata",
+						"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+						"--partials-directory", "some-partials-directory",
+						"--releases-directory", someReleasesDirectory,
+					})
+					Expect(err).To(MatchError("failed to parse partials: parsing partials failed"))
+				})
+			})
+
 			Context("when the icon service fails", func() {
 				It("returns an error", func() {
 					fakeIconService.EncodeReturns("", errors.New("encoding icon failed"))
@@ -504,6 +999,36 @@ var _ = Describe("Bake", func() {
 				})
 			})
 
+			Context("when the bake config service fails", func() {
+				It("returns an error", func() {
+					fakeBakeConfigService.ReadReturns(baking.BakeConfig{}, errors.New("reading bake config failed"))
+
+					err := bake.Execute([]string{
+						"--config", "some-bake-config.yml",
+						"--metadata", "some-metadata",
+						"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+						"--releases-directory", someReleasesDirectory,
+					})
+
+					Expect(err).To(MatchError("failed to read bake config: reading bake config failed"))
+				})
+			})
+
+			Context("when the ops files service fails", func() {
+				It("returns an error", func() {
+					fakeOpsFilesService.ApplyReturns(nil, errors.New("applying ops files failed"))
+
+					err := bake.Execute([]string{
+						"--metadata", "some-metadata",
+						"--ops-file", "some-ops-file.yml",
+						"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+						"--releases-directory", someReleasesDirectory,
+					})
+
+					Expect(err).To(MatchError("failed to apply ops files: applying ops files failed"))
+				})
+			})
+
 			Context("when the metadata service fails", func() {
 				It("returns an error", func() {
 					fakeMetadataService.ReadReturns(nil, errors.New("reading metadata failed"))
@@ -803,7 +1328,7 @@ var _ = Describe("Bake", func() {
 
 			Context("when the checksummer returns an error", func() {
 				It("returns an error", func() {
-					fakeChecksummer.SumReturns(errors.New("failed"))
+					fakeChecksummer.WriteReturns(errors.New("failed"))
 
 					err := bake.Execute([]string{
 						"--embed", "some-embed-path",
@@ -828,7 +1353,22 @@ var _ = Describe("Bake", func() {
 						"--sha256",
 					})
 
-					Expect(err).To(MatchError(ContainSubstring("failed to calculate checksum: failed")))
+					Expect(err).To(MatchError(ContainSubstring("failed to write checksums: failed")))
+				})
+			})
+
+			Context("when the signer returns an error", func() {
+				It("returns an error", func() {
+					fakeSigner.SignReturns(errors.New("failed"))
+
+					err := bake.Execute([]string{
+						"--metadata", "some-metadata",
+						"--output-file", "some-output-dir/some-product-file-1.2.3-build.4",
+						"--releases-directory", someReleasesDirectory,
+						"--signing-key", "some-signing-key.pem",
+					})
+
+					Expect(err).To(MatchError("failed to sign tile: failed"))
 				})
 			})
 		})
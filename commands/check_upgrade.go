@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/internal/migrations"
+	"github.com/pivotal-cf/kiln/internal/tile"
+	"github.com/pivotal-cf/kiln/proofing"
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+type CheckUpgrade struct {
+	FS     billy.Filesystem
+	Logger *log.Logger
+
+	Options struct {
+		Old                  string   `long:"old"                       required:"true" description:"path to the previous .pivotal file or metadata file"`
+		New                  string   `long:"new"                       required:"true" description:"path to the current .pivotal file or metadata file"`
+		MigrationDirectories []string `short:"md" long:"migrations-directory"           description:"path to a directory containing the migrations of the current tile, defaults to the migrations in the current .pivotal file"`
+		JSON                 bool     `long:"json"                                      description:"output the hazards as JSON"`
+	}
+}
+
+func (c CheckUpgrade) Execute(args []string) error {
+	_, err := jhanda.Parse(&c.Options, args)
+	if err != nil {
+		return err
+	}
+
+	previous, err := readProductTemplate(c.FS, c.Options.Old)
+	if err != nil {
+		return err
+	}
+
+	current, err := readProductTemplate(c.FS, c.Options.New)
+	if err != nil {
+		return err
+	}
+
+	previousMigrations, err := c.tileMigrations(c.Options.Old)
+	if err != nil {
+		return err
+	}
+
+	migrations, err := c.migrations()
+	if err != nil {
+		return err
+	}
+
+	hazards := proofing.CheckUpgrade(previous, current, previousMigrations, migrations)
+
+	if c.Options.JSON {
+		if hazards == nil {
+			hazards = []proofing.UpgradeHazard{}
+		}
+
+		output, err := json.MarshalIndent(hazards, "", "  ")
+		if err != nil {
+			return err // NOTE: this cannot happen, the hazards only contain strings
+		}
+		c.Logger.Println(string(output))
+	} else if len(hazards) == 0 {
+		c.Logger.Println("No upgrade hazards found")
+	} else {
+		c.Logger.Println("Upgrade hazards:")
+		for _, hazard := range hazards {
+			c.Logger.Printf("- %s\n", hazard)
+		}
+	}
+
+	if len(hazards) > 0 {
+		return fmt.Errorf("found %d upgrade hazard(s)", len(hazards))
+	}
+
+	return nil
+}
+
+// migrations returns the sources of the migrations in the migrations
+// directories, or in the current .pivotal file when none are given, keyed by
+// file name.
+func (c CheckUpgrade) migrations() (map[string]string, error) {
+	if len(c.Options.MigrationDirectories) == 0 {
+		return c.tileMigrations(c.Options.New)
+	}
+
+	loaded, err := migrations.Load(c.FS, c.Options.MigrationDirectories)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := map[string]string{}
+	for _, migration := range loaded {
+		sources[migration.Name] = migration.Source
+	}
+
+	return sources, nil
+}
+
+// tileMigrations returns the sources of the migrations in a .pivotal file
+// keyed by file name, and nothing for a metadata file.
+func (c CheckUpgrade) tileMigrations(tilePath string) (map[string]string, error) {
+	if filepath.Ext(tilePath) != ".pivotal" {
+		return nil, nil
+	}
+
+	files, err := tile.ReadFiles(c.FS, tilePath, func(name string) bool {
+		return path.Dir(name) == migrations.TileDirectory && strings.HasSuffix(name, ".js")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read migrations from %q: %w", tilePath, err)
+	}
+
+	sources := map[string]string{}
+	for name, contents := range files {
+		sources[path.Base(name)] = string(contents)
+	}
+
+	return sources, nil
+}
+
+func (c CheckUpgrade) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command compares the metadata of a previous and a current tile and reports upgrade hazards: property blueprints removed or renamed without a migration referencing them, property blueprint type changes, property blueprints that are no longer configurable, removed job types, an inconsistent minimum_version_for_upgrade and stemcell OS changes.",
+		ShortDescription: "reports upgrade hazards between two tiles",
+		Flags:            c.Options,
+	}
+}
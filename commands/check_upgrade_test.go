@@ -0,0 +1,138 @@
+package commands_test
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"log"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/commands"
+)
+
+var _ = Describe("CheckUpgrade", func() {
+	var _ jhanda.Command = CheckUpgrade{}
+
+	const (
+		previousMetadata = `---
+product_version: 1.1.0
+property_blueprints:
+- name: some-property
+  type: string
+- name: removed-property
+  type: string
+`
+		currentMetadata = `---
+product_version: 1.2.0
+property_blueprints:
+- name: some-property
+  type: string
+`
+		migration = `exports.migrate = function(input) {
+  delete input.properties[".properties.removed-property"];
+  return input;
+};`
+	)
+
+	var (
+		fs     billy.Filesystem
+		output *gbytes.Buffer
+		cmd    CheckUpgrade
+	)
+
+	writeTile := func(path string, files map[string]string) {
+		f, err := fs.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		zw := zip.NewWriter(f)
+		for name, contents := range files {
+			w, err := zw.Create(name)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte(contents))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(zw.Close()).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		cmd = CheckUpgrade{
+			FS:     fs,
+			Logger: log.New(output, "", 0),
+		}
+
+		Expect(util.WriteFile(fs, "previous.yml", []byte(previousMetadata), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "current.yml", []byte(currentMetadata), 0644)).To(Succeed())
+	})
+
+	It("prints the upgrade hazards and returns an error", func() {
+		err := cmd.Execute([]string{"--old", "previous.yml", "--new", "current.yml"})
+		Expect(err).To(MatchError("found 1 upgrade hazard(s)"))
+
+		Expect(output).To(gbytes.Say("Upgrade hazards:"))
+		Expect(output).To(gbytes.Say("- property blueprint .properties.removed-property was removed and no migration references it"))
+	})
+
+	Context("when a migration in the migrations directories references the removed property", func() {
+		It("does not report it", func() {
+			Expect(util.WriteFile(fs, "migrations/201801010000_remove.js", []byte(migration), 0644)).To(Succeed())
+
+			err := cmd.Execute([]string{"--old", "previous.yml", "--new", "current.yml", "--migrations-directory", "migrations"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(gbytes.Say("No upgrade hazards found"))
+		})
+	})
+
+	Context("when the current tile contains a migration referencing the removed property", func() {
+		It("does not report it", func() {
+			writeTile("current.pivotal", map[string]string{
+				"metadata/metadata.yml":                currentMetadata,
+				"migrations/v1/201801010000_remove.js": migration,
+			})
+
+			err := cmd.Execute([]string{"--old", "previous.yml", "--new", "current.pivotal"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(gbytes.Say("No upgrade hazards found"))
+		})
+	})
+
+	Context("when the previous tile already contains the migration", func() {
+		It("reports the removed property", func() {
+			writeTile("previous.pivotal", map[string]string{
+				"metadata/metadata.yml":                previousMetadata,
+				"migrations/v1/201801010000_remove.js": migration,
+			})
+			writeTile("current.pivotal", map[string]string{
+				"metadata/metadata.yml":                currentMetadata,
+				"migrations/v1/201801010000_remove.js": migration,
+			})
+
+			err := cmd.Execute([]string{"--old", "previous.pivotal", "--new", "current.pivotal"})
+			Expect(err).To(MatchError("found 1 upgrade hazard(s)"))
+			Expect(output).To(gbytes.Say("- property blueprint .properties.removed-property was removed and no migration references it"))
+		})
+	})
+
+	Context("when the --json flag is given", func() {
+		It("prints the hazards as JSON", func() {
+			err := cmd.Execute([]string{"--old", "previous.yml", "--new", "current.yml", "--json"})
+			Expect(err).To(HaveOccurred())
+
+			var hazards []map[string]string
+			Expect(json.Unmarshal(output.Contents(), &hazards)).To(Succeed())
+			Expect(hazards).To(Equal([]map[string]string{{
+				"kind":    "removed_property_blueprint",
+				"name":    ".properties.removed-property",
+				"message": "property blueprint .properties.removed-property was removed and no migration references it",
+			}}))
+		})
+	})
+})
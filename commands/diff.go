@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/internal/tile"
+	"github.com/pivotal-cf/kiln/proofing"
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+type Diff struct {
+	FS     billy.Filesystem
+	Logger *log.Logger
+
+	Options struct {
+		Old  string `long:"old"  required:"true" description:"path to the previous .pivotal file or metadata file"`
+		New  string `long:"new"  required:"true" description:"path to the current .pivotal file or metadata file"`
+		JSON bool   `long:"json"                 description:"output the changes as JSON"`
+	}
+}
+
+func (d Diff) Execute(args []string) error {
+	_, err := jhanda.Parse(&d.Options, args)
+	if err != nil {
+		return err
+	}
+
+	previous, err := readProductTemplate(d.FS, d.Options.Old)
+	if err != nil {
+		return err
+	}
+
+	current, err := readProductTemplate(d.FS, d.Options.New)
+	if err != nil {
+		return err
+	}
+
+	changes := proofing.Diff(previous, current)
+
+	if d.Options.JSON {
+		if changes == nil {
+			changes = []proofing.Change{}
+		}
+
+		output, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return err // NOTE: this cannot happen, the changes were unmarshalled from YAML
+		}
+		d.Logger.Println(string(output))
+		return nil
+	}
+
+	if len(changes) == 0 {
+		d.Logger.Println("No changes")
+		return nil
+	}
+
+	for _, change := range changes {
+		d.Logger.Println(change)
+	}
+
+	return nil
+}
+
+func (d Diff) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command prints the changes to releases, property blueprints, forms, job types, errands and stemcell criteria between two tiles or two baked metadata files.",
+		ShortDescription: "prints the metadata changes between two tiles",
+		Flags:            d.Options,
+	}
+}
+
+// readProductTemplate parses the metadata in the .pivotal file or baked
+// metadata file at path.
+func readProductTemplate(fs billy.Filesystem, path string) (proofing.ProductTemplate, error) {
+	var (
+		metadata []byte
+		err      error
+	)
+
+	if filepath.Ext(path) == ".pivotal" {
+		metadata, err = tile.ReadMetadata(fs, path)
+	} else {
+		var file billy.File
+		file, err = fs.Open(path)
+		if err == nil {
+			metadata, err = ioutil.ReadAll(file)
+			file.Close()
+		}
+	}
+	if err != nil {
+		return proofing.ProductTemplate{}, fmt.Errorf("could not read metadata from %q: %w", path, err)
+	}
+
+	productTemplate, err := proofing.Parse(bytes.NewReader(metadata))
+	if err != nil {
+		return proofing.ProductTemplate{}, fmt.Errorf("could not parse metadata from %q: %w", path, err)
+	}
+
+	return productTemplate, nil
+}
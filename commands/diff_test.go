@@ -0,0 +1,156 @@
+package commands_test
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"log"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/commands"
+)
+
+var _ = Describe("Diff", func() {
+	var _ jhanda.Command = Diff{}
+
+	const (
+		previousMetadata = `---
+releases:
+- name: some-release
+  version: 1.0.0
+property_blueprints:
+- name: some-property
+  type: string
+`
+		currentMetadata = `---
+releases:
+- name: some-release
+  version: 1.1.0
+property_blueprints:
+- name: some-property
+  type: string
+- name: other-property
+  type: boolean
+`
+	)
+
+	var (
+		fs     billy.Filesystem
+		output *gbytes.Buffer
+		diff   Diff
+	)
+
+	writeFile := func(path, contents string) {
+		f, err := fs.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.Write([]byte(contents))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+	}
+
+	writeTile := func(path, metadata string) {
+		f, err := fs.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		zw := zip.NewWriter(f)
+		w, err := zw.Create("metadata/metadata.yml")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Write([]byte(metadata))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zw.Close()).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		diff = Diff{
+			FS:     fs,
+			Logger: log.New(output, "", 0),
+		}
+	})
+
+	Context("when given two metadata files", func() {
+		BeforeEach(func() {
+			writeFile("previous.yml", previousMetadata)
+			writeFile("current.yml", currentMetadata)
+		})
+
+		It("prints the changes", func() {
+			Expect(diff.Execute([]string{"--old", "previous.yml", "--new", "current.yml"})).To(Succeed())
+
+			Expect(output).To(gbytes.Say(`~ release some-release: version 1.0.0 -> 1.1.0`))
+			Expect(output).To(gbytes.Say(`\+ property_blueprint .properties.other-property`))
+		})
+
+		Context("when the --json flag is provided", func() {
+			It("prints the changes as JSON", func() {
+				Expect(diff.Execute([]string{"--old", "previous.yml", "--new", "current.yml", "--json"})).To(Succeed())
+
+				var changes []map[string]interface{}
+				Expect(json.Unmarshal(output.Contents(), &changes)).To(Succeed())
+				Expect(changes).To(Equal([]map[string]interface{}{
+					{"type": "changed", "kind": "release", "name": "some-release", "field": "version", "old": "1.0.0", "new": "1.1.0"},
+					{"type": "added", "kind": "property_blueprint", "name": ".properties.other-property"},
+				}))
+			})
+		})
+
+		Context("when nothing changed", func() {
+			It("says so", func() {
+				Expect(diff.Execute([]string{"--old", "previous.yml", "--new", "previous.yml"})).To(Succeed())
+				Expect(output).To(gbytes.Say("No changes"))
+			})
+		})
+	})
+
+	Context("when given two tiles", func() {
+		BeforeEach(func() {
+			writeTile("previous.pivotal", previousMetadata)
+			writeTile("current.pivotal", currentMetadata)
+		})
+
+		It("prints the changes", func() {
+			Expect(diff.Execute([]string{"--old", "previous.pivotal", "--new", "current.pivotal"})).To(Succeed())
+
+			Expect(output).To(gbytes.Say(`~ release some-release: version 1.0.0 -> 1.1.0`))
+			Expect(output).To(gbytes.Say(`\+ property_blueprint .properties.other-property`))
+		})
+	})
+
+	Context("failure cases", func() {
+		Context("when a file does not exist", func() {
+			It("returns an error", func() {
+				writeFile("current.yml", currentMetadata)
+
+				err := diff.Execute([]string{"--old", "missing.yml", "--new", "current.yml"})
+				Expect(err).To(MatchError(ContainSubstring(`could not read metadata from "missing.yml"`)))
+			})
+		})
+
+		Context("when a tile has no metadata", func() {
+			It("returns an error", func() {
+				writeFile("previous.yml", previousMetadata)
+				writeFile("broken.pivotal", "not a zip")
+
+				err := diff.Execute([]string{"--old", "previous.yml", "--new", "broken.pivotal"})
+				Expect(err).To(MatchError(ContainSubstring(`could not read metadata from "broken.pivotal"`)))
+			})
+		})
+
+		Context("when a metadata file is malformed", func() {
+			It("returns an error", func() {
+				writeFile("previous.yml", previousMetadata)
+				writeFile("current.yml", "%%%")
+
+				err := diff.Execute([]string{"--old", "previous.yml", "--new", "current.yml"})
+				Expect(err).To(MatchError(ContainSubstring(`could not parse metadata from "current.yml"`)))
+			})
+		})
+	})
+})
@@ -3,80 +3,86 @@ package fakes
 
 import (
 	"sync"
+
+	"github.com/pivotal-cf/kiln/builder"
 )
 
 type Checksummer struct {
-	SumStub        func(string) error
-	sumMutex       sync.RWMutex
-	sumArgsForCall []struct {
+	WriteStub        func(string, builder.Digests, string) error
+	writeMutex       sync.RWMutex
+	writeArgsForCall []struct {
 		arg1 string
+		arg2 builder.Digests
+		arg3 string
 	}
-	sumReturns struct {
+	writeReturns struct {
 		result1 error
 	}
-	sumReturnsOnCall map[int]struct {
+	writeReturnsOnCall map[int]struct {
 		result1 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *Checksummer) Sum(arg1 string) error {
-	fake.sumMutex.Lock()
-	ret, specificReturn := fake.sumReturnsOnCall[len(fake.sumArgsForCall)]
-	fake.sumArgsForCall = append(fake.sumArgsForCall, struct {
+func (fake *Checksummer) Write(arg1 string, arg2 builder.Digests, arg3 string) error {
+	fake.writeMutex.Lock()
+	ret, specificReturn := fake.writeReturnsOnCall[len(fake.writeArgsForCall)]
+	fake.writeArgsForCall = append(fake.writeArgsForCall, struct {
 		arg1 string
-	}{arg1})
-	fake.recordInvocation("Sum", []interface{}{arg1})
-	fake.sumMutex.Unlock()
-	if fake.SumStub != nil {
-		return fake.SumStub(arg1)
+		arg2 builder.Digests
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Write", []interface{}{arg1, arg2, arg3})
+	fake.writeMutex.Unlock()
+	if fake.WriteStub != nil {
+		return fake.WriteStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.sumReturns
+	fakeReturns := fake.writeReturns
 	return fakeReturns.result1
 }
 
-func (fake *Checksummer) SumCallCount() int {
-	fake.sumMutex.RLock()
-	defer fake.sumMutex.RUnlock()
-	return len(fake.sumArgsForCall)
+func (fake *Checksummer) WriteCallCount() int {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return len(fake.writeArgsForCall)
 }
 
-func (fake *Checksummer) SumCalls(stub func(string) error) {
-	fake.sumMutex.Lock()
-	defer fake.sumMutex.Unlock()
-	fake.SumStub = stub
+func (fake *Checksummer) WriteCalls(stub func(string, builder.Digests, string) error) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = stub
 }
 
-func (fake *Checksummer) SumArgsForCall(i int) string {
-	fake.sumMutex.RLock()
-	defer fake.sumMutex.RUnlock()
-	argsForCall := fake.sumArgsForCall[i]
-	return argsForCall.arg1
+func (fake *Checksummer) WriteArgsForCall(i int) (string, builder.Digests, string) {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	argsForCall := fake.writeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *Checksummer) SumReturns(result1 error) {
-	fake.sumMutex.Lock()
-	defer fake.sumMutex.Unlock()
-	fake.SumStub = nil
-	fake.sumReturns = struct {
+func (fake *Checksummer) WriteReturns(result1 error) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = nil
+	fake.writeReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *Checksummer) SumReturnsOnCall(i int, result1 error) {
-	fake.sumMutex.Lock()
-	defer fake.sumMutex.Unlock()
-	fake.SumStub = nil
-	if fake.sumReturnsOnCall == nil {
-		fake.sumReturnsOnCall = make(map[int]struct {
+func (fake *Checksummer) WriteReturnsOnCall(i int, result1 error) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = nil
+	if fake.writeReturnsOnCall == nil {
+		fake.writeReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.sumReturnsOnCall[i] = struct {
+	fake.writeReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
@@ -84,8 +90,8 @@ func (fake *Checksummer) SumReturnsOnCall(i int, result1 error) {
 func (fake *Checksummer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.sumMutex.RLock()
-	defer fake.sumMutex.RUnlock()
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -6,10 +6,11 @@ import (
 )
 
 type FormsService struct {
-	FromDirectoriesStub        func([]string) (map[string]interface{}, error)
+	FromDirectoriesStub        func([]string, bool) (map[string]interface{}, error)
 	fromDirectoriesMutex       sync.RWMutex
 	fromDirectoriesArgsForCall []struct {
 		arg1 []string
+		arg2 bool
 	}
 	fromDirectoriesReturns struct {
 		result1 map[string]interface{}
@@ -23,7 +24,7 @@ type FormsService struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FormsService) FromDirectories(arg1 []string) (map[string]interface{}, error) {
+func (fake *FormsService) FromDirectories(arg1 []string, arg2 bool) (map[string]interface{}, error) {
 	var arg1Copy []string
 	if arg1 != nil {
 		arg1Copy = make([]string, len(arg1))
@@ -33,11 +34,12 @@ func (fake *FormsService) FromDirectories(arg1 []string) (map[string]interface{}
 	ret, specificReturn := fake.fromDirectoriesReturnsOnCall[len(fake.fromDirectoriesArgsForCall)]
 	fake.fromDirectoriesArgsForCall = append(fake.fromDirectoriesArgsForCall, struct {
 		arg1 []string
-	}{arg1Copy})
-	fake.recordInvocation("FromDirectories", []interface{}{arg1Copy})
+		arg2 bool
+	}{arg1Copy, arg2})
+	fake.recordInvocation("FromDirectories", []interface{}{arg1Copy, arg2})
 	fake.fromDirectoriesMutex.Unlock()
 	if fake.FromDirectoriesStub != nil {
-		return fake.FromDirectoriesStub(arg1)
+		return fake.FromDirectoriesStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -52,17 +54,17 @@ func (fake *FormsService) FromDirectoriesCallCount() int {
 	return len(fake.fromDirectoriesArgsForCall)
 }
 
-func (fake *FormsService) FromDirectoriesCalls(stub func([]string) (map[string]interface{}, error)) {
+func (fake *FormsService) FromDirectoriesCalls(stub func([]string, bool) (map[string]interface{}, error)) {
 	fake.fromDirectoriesMutex.Lock()
 	defer fake.fromDirectoriesMutex.Unlock()
 	fake.FromDirectoriesStub = stub
 }
 
-func (fake *FormsService) FromDirectoriesArgsForCall(i int) []string {
+func (fake *FormsService) FromDirectoriesArgsForCall(i int) ([]string, bool) {
 	fake.fromDirectoriesMutex.RLock()
 	defer fake.fromDirectoriesMutex.RUnlock()
 	argsForCall := fake.fromDirectoriesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FormsService) FromDirectoriesReturns(result1 map[string]interface{}, result2 error) {
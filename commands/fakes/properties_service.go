@@ -6,10 +6,11 @@ import (
 )
 
 type PropertiesService struct {
-	FromDirectoriesStub        func([]string) (map[string]interface{}, error)
+	FromDirectoriesStub        func([]string, bool) (map[string]interface{}, error)
 	fromDirectoriesMutex       sync.RWMutex
 	fromDirectoriesArgsForCall []struct {
 		arg1 []string
+		arg2 bool
 	}
 	fromDirectoriesReturns struct {
 		result1 map[string]interface{}
@@ -23,7 +24,7 @@ type PropertiesService struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *PropertiesService) FromDirectories(arg1 []string) (map[string]interface{}, error) {
+func (fake *PropertiesService) FromDirectories(arg1 []string, arg2 bool) (map[string]interface{}, error) {
 	var arg1Copy []string
 	if arg1 != nil {
 		arg1Copy = make([]string, len(arg1))
@@ -33,11 +34,12 @@ func (fake *PropertiesService) FromDirectories(arg1 []string) (map[string]interf
 	ret, specificReturn := fake.fromDirectoriesReturnsOnCall[len(fake.fromDirectoriesArgsForCall)]
 	fake.fromDirectoriesArgsForCall = append(fake.fromDirectoriesArgsForCall, struct {
 		arg1 []string
-	}{arg1Copy})
-	fake.recordInvocation("FromDirectories", []interface{}{arg1Copy})
+		arg2 bool
+	}{arg1Copy, arg2})
+	fake.recordInvocation("FromDirectories", []interface{}{arg1Copy, arg2})
 	fake.fromDirectoriesMutex.Unlock()
 	if fake.FromDirectoriesStub != nil {
-		return fake.FromDirectoriesStub(arg1)
+		return fake.FromDirectoriesStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -52,17 +54,17 @@ func (fake *PropertiesService) FromDirectoriesCallCount() int {
 	return len(fake.fromDirectoriesArgsForCall)
 }
 
-func (fake *PropertiesService) FromDirectoriesCalls(stub func([]string) (map[string]interface{}, error)) {
+func (fake *PropertiesService) FromDirectoriesCalls(stub func([]string, bool) (map[string]interface{}, error)) {
 	fake.fromDirectoriesMutex.Lock()
 	defer fake.fromDirectoriesMutex.Unlock()
 	fake.FromDirectoriesStub = stub
 }
 
-func (fake *PropertiesService) FromDirectoriesArgsForCall(i int) []string {
+func (fake *PropertiesService) FromDirectoriesArgsForCall(i int) ([]string, bool) {
 	fake.fromDirectoriesMutex.RLock()
 	defer fake.fromDirectoriesMutex.RUnlock()
 	argsForCall := fake.fromDirectoriesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *PropertiesService) FromDirectoriesReturns(result1 map[string]interface{}, result2 error) {
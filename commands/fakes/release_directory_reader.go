@@ -0,0 +1,113 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/kiln/builder"
+)
+
+type ReleaseDirectoryReader struct {
+	ReleasesInDirectoryStub        func(string) ([]builder.Part, error)
+	releasesInDirectoryMutex       sync.RWMutex
+	releasesInDirectoryArgsForCall []struct {
+		arg1 string
+	}
+	releasesInDirectoryReturns struct {
+		result1 []builder.Part
+		result2 error
+	}
+	releasesInDirectoryReturnsOnCall map[int]struct {
+		result1 []builder.Part
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ReleaseDirectoryReader) ReleasesInDirectory(arg1 string) ([]builder.Part, error) {
+	fake.releasesInDirectoryMutex.Lock()
+	ret, specificReturn := fake.releasesInDirectoryReturnsOnCall[len(fake.releasesInDirectoryArgsForCall)]
+	fake.releasesInDirectoryArgsForCall = append(fake.releasesInDirectoryArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ReleasesInDirectory", []interface{}{arg1})
+	fake.releasesInDirectoryMutex.Unlock()
+	if fake.ReleasesInDirectoryStub != nil {
+		return fake.ReleasesInDirectoryStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.releasesInDirectoryReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ReleaseDirectoryReader) ReleasesInDirectoryCallCount() int {
+	fake.releasesInDirectoryMutex.RLock()
+	defer fake.releasesInDirectoryMutex.RUnlock()
+	return len(fake.releasesInDirectoryArgsForCall)
+}
+
+func (fake *ReleaseDirectoryReader) ReleasesInDirectoryCalls(stub func(string) ([]builder.Part, error)) {
+	fake.releasesInDirectoryMutex.Lock()
+	defer fake.releasesInDirectoryMutex.Unlock()
+	fake.ReleasesInDirectoryStub = stub
+}
+
+func (fake *ReleaseDirectoryReader) ReleasesInDirectoryArgsForCall(i int) string {
+	fake.releasesInDirectoryMutex.RLock()
+	defer fake.releasesInDirectoryMutex.RUnlock()
+	argsForCall := fake.releasesInDirectoryArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ReleaseDirectoryReader) ReleasesInDirectoryReturns(result1 []builder.Part, result2 error) {
+	fake.releasesInDirectoryMutex.Lock()
+	defer fake.releasesInDirectoryMutex.Unlock()
+	fake.ReleasesInDirectoryStub = nil
+	fake.releasesInDirectoryReturns = struct {
+		result1 []builder.Part
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseDirectoryReader) ReleasesInDirectoryReturnsOnCall(i int, result1 []builder.Part, result2 error) {
+	fake.releasesInDirectoryMutex.Lock()
+	defer fake.releasesInDirectoryMutex.Unlock()
+	fake.ReleasesInDirectoryStub = nil
+	if fake.releasesInDirectoryReturnsOnCall == nil {
+		fake.releasesInDirectoryReturnsOnCall = make(map[int]struct {
+			result1 []builder.Part
+			result2 error
+		})
+	}
+	fake.releasesInDirectoryReturnsOnCall[i] = struct {
+		result1 []builder.Part
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseDirectoryReader) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.releasesInDirectoryMutex.RLock()
+	defer fake.releasesInDirectoryMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ReleaseDirectoryReader) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
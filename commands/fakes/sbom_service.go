@@ -0,0 +1,129 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/kiln/builder"
+)
+
+type SBOMService struct {
+	FromMetadataStub        func(string, []byte, string, []builder.Part) ([]byte, error)
+	fromMetadataMutex       sync.RWMutex
+	fromMetadataArgsForCall []struct {
+		arg1 string
+		arg2 []byte
+		arg3 string
+		arg4 []builder.Part
+	}
+	fromMetadataReturns struct {
+		result1 []byte
+		result2 error
+	}
+	fromMetadataReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *SBOMService) FromMetadata(arg1 string, arg2 []byte, arg3 string, arg4 []builder.Part) ([]byte, error) {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg4Copy []builder.Part
+	if arg4 != nil {
+		arg4Copy = make([]builder.Part, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.fromMetadataMutex.Lock()
+	ret, specificReturn := fake.fromMetadataReturnsOnCall[len(fake.fromMetadataArgsForCall)]
+	fake.fromMetadataArgsForCall = append(fake.fromMetadataArgsForCall, struct {
+		arg1 string
+		arg2 []byte
+		arg3 string
+		arg4 []builder.Part
+	}{arg1, arg2Copy, arg3, arg4Copy})
+	fake.recordInvocation("FromMetadata", []interface{}{arg1, arg2Copy, arg3, arg4Copy})
+	fake.fromMetadataMutex.Unlock()
+	if fake.FromMetadataStub != nil {
+		return fake.FromMetadataStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.fromMetadataReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SBOMService) FromMetadataCallCount() int {
+	fake.fromMetadataMutex.RLock()
+	defer fake.fromMetadataMutex.RUnlock()
+	return len(fake.fromMetadataArgsForCall)
+}
+
+func (fake *SBOMService) FromMetadataCalls(stub func(string, []byte, string, []builder.Part) ([]byte, error)) {
+	fake.fromMetadataMutex.Lock()
+	defer fake.fromMetadataMutex.Unlock()
+	fake.FromMetadataStub = stub
+}
+
+func (fake *SBOMService) FromMetadataArgsForCall(i int) (string, []byte, string, []builder.Part) {
+	fake.fromMetadataMutex.RLock()
+	defer fake.fromMetadataMutex.RUnlock()
+	argsForCall := fake.fromMetadataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *SBOMService) FromMetadataReturns(result1 []byte, result2 error) {
+	fake.fromMetadataMutex.Lock()
+	defer fake.fromMetadataMutex.Unlock()
+	fake.FromMetadataStub = nil
+	fake.fromMetadataReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SBOMService) FromMetadataReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.fromMetadataMutex.Lock()
+	defer fake.fromMetadataMutex.Unlock()
+	fake.FromMetadataStub = nil
+	if fake.fromMetadataReturnsOnCall == nil {
+		fake.fromMetadataReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.fromMetadataReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SBOMService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.fromMetadataMutex.RLock()
+	defer fake.fromMetadataMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *SBOMService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
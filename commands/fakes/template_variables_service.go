@@ -3,28 +3,33 @@ package fakes
 
 import (
 	"sync"
+
+	"github.com/pivotal-cf/kiln/internal/baking"
 )
 
 type TemplateVariablesService struct {
-	FromPathsAndPairsStub        func([]string, []string) (map[string]interface{}, error)
+	FromPathsAndPairsStub        func([]string, []string, []string) (map[string]interface{}, []baking.TemplateVariableSource, error)
 	fromPathsAndPairsMutex       sync.RWMutex
 	fromPathsAndPairsArgsForCall []struct {
 		arg1 []string
 		arg2 []string
+		arg3 []string
 	}
 	fromPathsAndPairsReturns struct {
 		result1 map[string]interface{}
-		result2 error
+		result2 []baking.TemplateVariableSource
+		result3 error
 	}
 	fromPathsAndPairsReturnsOnCall map[int]struct {
 		result1 map[string]interface{}
-		result2 error
+		result2 []baking.TemplateVariableSource
+		result3 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *TemplateVariablesService) FromPathsAndPairs(arg1 []string, arg2 []string) (map[string]interface{}, error) {
+func (fake *TemplateVariablesService) FromPathsAndPairs(arg1 []string, arg2 []string, arg3 []string) (map[string]interface{}, []baking.TemplateVariableSource, error) {
 	var arg1Copy []string
 	if arg1 != nil {
 		arg1Copy = make([]string, len(arg1))
@@ -35,22 +40,28 @@ func (fake *TemplateVariablesService) FromPathsAndPairs(arg1 []string, arg2 []st
 		arg2Copy = make([]string, len(arg2))
 		copy(arg2Copy, arg2)
 	}
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
 	fake.fromPathsAndPairsMutex.Lock()
 	ret, specificReturn := fake.fromPathsAndPairsReturnsOnCall[len(fake.fromPathsAndPairsArgsForCall)]
 	fake.fromPathsAndPairsArgsForCall = append(fake.fromPathsAndPairsArgsForCall, struct {
 		arg1 []string
 		arg2 []string
-	}{arg1Copy, arg2Copy})
-	fake.recordInvocation("FromPathsAndPairs", []interface{}{arg1Copy, arg2Copy})
+		arg3 []string
+	}{arg1Copy, arg2Copy, arg3Copy})
+	fake.recordInvocation("FromPathsAndPairs", []interface{}{arg1Copy, arg2Copy, arg3Copy})
 	fake.fromPathsAndPairsMutex.Unlock()
 	if fake.FromPathsAndPairsStub != nil {
-		return fake.FromPathsAndPairsStub(arg1, arg2)
+		return fake.FromPathsAndPairsStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
 	fakeReturns := fake.fromPathsAndPairsReturns
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *TemplateVariablesService) FromPathsAndPairsCallCount() int {
@@ -59,43 +70,46 @@ func (fake *TemplateVariablesService) FromPathsAndPairsCallCount() int {
 	return len(fake.fromPathsAndPairsArgsForCall)
 }
 
-func (fake *TemplateVariablesService) FromPathsAndPairsCalls(stub func([]string, []string) (map[string]interface{}, error)) {
+func (fake *TemplateVariablesService) FromPathsAndPairsCalls(stub func([]string, []string, []string) (map[string]interface{}, []baking.TemplateVariableSource, error)) {
 	fake.fromPathsAndPairsMutex.Lock()
 	defer fake.fromPathsAndPairsMutex.Unlock()
 	fake.FromPathsAndPairsStub = stub
 }
 
-func (fake *TemplateVariablesService) FromPathsAndPairsArgsForCall(i int) ([]string, []string) {
+func (fake *TemplateVariablesService) FromPathsAndPairsArgsForCall(i int) ([]string, []string, []string) {
 	fake.fromPathsAndPairsMutex.RLock()
 	defer fake.fromPathsAndPairsMutex.RUnlock()
 	argsForCall := fake.fromPathsAndPairsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *TemplateVariablesService) FromPathsAndPairsReturns(result1 map[string]interface{}, result2 error) {
+func (fake *TemplateVariablesService) FromPathsAndPairsReturns(result1 map[string]interface{}, result2 []baking.TemplateVariableSource, result3 error) {
 	fake.fromPathsAndPairsMutex.Lock()
 	defer fake.fromPathsAndPairsMutex.Unlock()
 	fake.FromPathsAndPairsStub = nil
 	fake.fromPathsAndPairsReturns = struct {
 		result1 map[string]interface{}
-		result2 error
-	}{result1, result2}
+		result2 []baking.TemplateVariableSource
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *TemplateVariablesService) FromPathsAndPairsReturnsOnCall(i int, result1 map[string]interface{}, result2 error) {
+func (fake *TemplateVariablesService) FromPathsAndPairsReturnsOnCall(i int, result1 map[string]interface{}, result2 []baking.TemplateVariableSource, result3 error) {
 	fake.fromPathsAndPairsMutex.Lock()
 	defer fake.fromPathsAndPairsMutex.Unlock()
 	fake.FromPathsAndPairsStub = nil
 	if fake.fromPathsAndPairsReturnsOnCall == nil {
 		fake.fromPathsAndPairsReturnsOnCall = make(map[int]struct {
 			result1 map[string]interface{}
-			result2 error
+			result2 []baking.TemplateVariableSource
+			result3 error
 		})
 	}
 	fake.fromPathsAndPairsReturnsOnCall[i] = struct {
 		result1 map[string]interface{}
-		result2 error
-	}{result1, result2}
+		result2 []baking.TemplateVariableSource
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *TemplateVariablesService) Invocations() map[string][][]interface{} {
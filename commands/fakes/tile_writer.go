@@ -8,23 +8,25 @@ import (
 )
 
 type TileWriter struct {
-	WriteStub        func([]byte, builder.WriteInput) error
+	WriteStub        func([]byte, builder.WriteInput) (builder.Digests, error)
 	writeMutex       sync.RWMutex
 	writeArgsForCall []struct {
 		arg1 []byte
 		arg2 builder.WriteInput
 	}
 	writeReturns struct {
-		result1 error
+		result1 builder.Digests
+		result2 error
 	}
 	writeReturnsOnCall map[int]struct {
-		result1 error
+		result1 builder.Digests
+		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *TileWriter) Write(arg1 []byte, arg2 builder.WriteInput) error {
+func (fake *TileWriter) Write(arg1 []byte, arg2 builder.WriteInput) (builder.Digests, error) {
 	var arg1Copy []byte
 	if arg1 != nil {
 		arg1Copy = make([]byte, len(arg1))
@@ -42,10 +44,10 @@ func (fake *TileWriter) Write(arg1 []byte, arg2 builder.WriteInput) error {
 		return fake.WriteStub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
 	fakeReturns := fake.writeReturns
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *TileWriter) WriteCallCount() int {
@@ -54,7 +56,7 @@ func (fake *TileWriter) WriteCallCount() int {
 	return len(fake.writeArgsForCall)
 }
 
-func (fake *TileWriter) WriteCalls(stub func([]byte, builder.WriteInput) error) {
+func (fake *TileWriter) WriteCalls(stub func([]byte, builder.WriteInput) (builder.Digests, error)) {
 	fake.writeMutex.Lock()
 	defer fake.writeMutex.Unlock()
 	fake.WriteStub = stub
@@ -67,27 +69,30 @@ func (fake *TileWriter) WriteArgsForCall(i int) ([]byte, builder.WriteInput) {
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *TileWriter) WriteReturns(result1 error) {
+func (fake *TileWriter) WriteReturns(result1 builder.Digests, result2 error) {
 	fake.writeMutex.Lock()
 	defer fake.writeMutex.Unlock()
 	fake.WriteStub = nil
 	fake.writeReturns = struct {
-		result1 error
-	}{result1}
+		result1 builder.Digests
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *TileWriter) WriteReturnsOnCall(i int, result1 error) {
+func (fake *TileWriter) WriteReturnsOnCall(i int, result1 builder.Digests, result2 error) {
 	fake.writeMutex.Lock()
 	defer fake.writeMutex.Unlock()
 	fake.WriteStub = nil
 	if fake.writeReturnsOnCall == nil {
 		fake.writeReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 builder.Digests
+			result2 error
 		})
 	}
 	fake.writeReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 builder.Digests
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *TileWriter) Invocations() map[string][][]interface{} {
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/internal/migrations"
+	"github.com/pivotal-cf/kiln/proofing"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/util"
+)
+
+type GenerateMigration struct {
+	FS     billy.Filesystem
+	Logger *log.Logger
+	Now    func() time.Time
+
+	Options struct {
+		Old                 string `           long:"old"                  required:"true"            description:"path to the previous .pivotal file or metadata file"`
+		New                 string `           long:"new"                  required:"true"            description:"path to the current .pivotal file or metadata file"`
+		MigrationsDirectory string `short:"md" long:"migrations-directory" default:"migrations"        description:"path to the directory to write the migration to"`
+		Description         string `short:"d"  long:"description"          default:"rename_properties" description:"description in the file name of the migration, after the timestamp"`
+	}
+}
+
+func (g GenerateMigration) Execute(args []string) error {
+	_, err := jhanda.Parse(&g.Options, args)
+	if err != nil {
+		return err
+	}
+
+	if g.Now == nil {
+		g.Now = time.Now
+	}
+
+	previous, err := readProductTemplate(g.FS, g.Options.Old)
+	if err != nil {
+		return err
+	}
+
+	current, err := readProductTemplate(g.FS, g.Options.New)
+	if err != nil {
+		return err
+	}
+
+	renames := proofing.DetectPropertyRenames(previous, current)
+	if len(renames) == 0 {
+		g.Logger.Println("No property blueprint renames found")
+		return nil
+	}
+
+	g.Logger.Println("Property blueprint renames:")
+	for _, rename := range renames {
+		g.Logger.Printf("- %s -> %s\n", rename.From, rename.To)
+	}
+
+	var existing []migrations.Migration
+	if _, err := g.FS.Stat(g.Options.MigrationsDirectory); err == nil {
+		existing, err = migrations.Load(g.FS, []string{g.Options.MigrationsDirectory})
+		if err != nil {
+			return err
+		}
+	}
+
+	path := filepath.Join(g.Options.MigrationsDirectory, migrations.NextName(existing, g.Now(), g.Options.Description))
+
+	err = util.WriteFile(g.FS, path, migrations.RenameMigration(renames), 0644)
+	if err != nil {
+		return fmt.Errorf("could not write migration: %w", err)
+	}
+
+	g.Logger.Printf("Wrote %s, review it and add a fixture to test it with kiln test-migrations\n", path)
+
+	return nil
+}
+
+func (g GenerateMigration) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command detects property blueprints that were renamed between two tiles, with the same type and options, and writes a timestamped JavaScript migration that moves their values to the new names.",
+		ShortDescription: "generates a migration for renamed property blueprints",
+		Flags:            g.Options,
+	}
+}
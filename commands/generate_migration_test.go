@@ -0,0 +1,100 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/commands"
+)
+
+var _ = Describe("GenerateMigration", func() {
+	var _ jhanda.Command = GenerateMigration{}
+
+	const (
+		previousMetadata = `---
+property_blueprints:
+- name: old-name
+  type: string
+- name: some-property
+  type: boolean
+`
+		currentMetadata = `---
+property_blueprints:
+- name: new-name
+  type: string
+- name: some-property
+  type: boolean
+`
+	)
+
+	var (
+		fs     billy.Filesystem
+		output *gbytes.Buffer
+		cmd    GenerateMigration
+	)
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		cmd = GenerateMigration{
+			FS:     fs,
+			Logger: log.New(output, "", 0),
+			Now: func() time.Time {
+				return time.Date(2020, 3, 4, 15, 39, 0, 0, time.UTC)
+			},
+		}
+
+		Expect(util.WriteFile(fs, "previous.yml", []byte(previousMetadata), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "current.yml", []byte(currentMetadata), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "migrations/201711131111_first.js", []byte("exports.migrate = function(input) { return input; };"), 0644)).To(Succeed())
+	})
+
+	It("writes a migration that moves the renamed properties", func() {
+		err := cmd.Execute([]string{"--old", "previous.yml", "--new", "current.yml"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output).To(gbytes.Say("Property blueprint renames:"))
+		Expect(output).To(gbytes.Say(`- \.properties\.old-name -> \.properties\.new-name`))
+		Expect(output).To(gbytes.Say(`Wrote migrations/202003041539_rename_properties.js`))
+
+		f, err := fs.Open("migrations/202003041539_rename_properties.js")
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+
+		source, err := ioutil.ReadAll(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(source)).To(ContainSubstring(`rename(properties, ".properties.old-name", ".properties.new-name");`))
+	})
+
+	Context("when the migrations directory and description are given", func() {
+		It("writes the migration there", func() {
+			err := cmd.Execute([]string{"--old", "previous.yml", "--new", "current.yml", "--migrations-directory", "other-migrations", "--description", "rename_old_name"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = fs.Stat("other-migrations/202003041539_rename_old_name.js")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when no property blueprint was renamed", func() {
+		It("does not write a migration", func() {
+			err := cmd.Execute([]string{"--old", "previous.yml", "--new", "previous.yml"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(output).To(gbytes.Say("No property blueprint renames found"))
+
+			infos, err := fs.ReadDir("migrations")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(infos).To(HaveLen(1))
+		})
+	})
+})
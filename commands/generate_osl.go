@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/builder"
+	"github.com/pivotal-cf/kiln/internal/cargo"
+	"github.com/pivotal-cf/kiln/internal/osl"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/util"
+	"gopkg.in/yaml.v2"
+)
+
+//go:generate counterfeiter -o ./fakes/release_directory_reader.go --fake-name ReleaseDirectoryReader . releaseDirectoryReader
+type releaseDirectoryReader interface {
+	ReleasesInDirectory(directoryPath string) ([]builder.Part, error)
+}
+
+type GenerateOSL struct {
+	FS              billy.Filesystem
+	Logger          *log.Logger
+	ReleasesService releaseDirectoryReader
+
+	Options struct {
+		ReleaseDirectories []string `short:"rd" long:"releases-directory"                    description:"path to a directory containing release tarballs, defaults to releases"`
+		Kilnfile           string   `short:"kf" long:"kilnfile"           default:"Kilnfile" description:"path to Kilnfile"`
+		Version            string   `short:"v"  long:"version-file"       default:"version"  description:"path to version file"`
+		OutputFile         string   `short:"o"  long:"output-file"                           description:"path to write the document to, defaults to open_source_license_<slug>_<major.minor>.txt"`
+	}
+}
+
+func (g GenerateOSL) Execute(args []string) error {
+	_, err := jhanda.Parse(&g.Options, args)
+	if err != nil {
+		return err
+	}
+
+	slug, err := g.slug()
+	if err != nil {
+		return err
+	}
+
+	version, err := g.version()
+	if err != nil {
+		return err
+	}
+
+	directories := g.Options.ReleaseDirectories
+	if len(directories) == 0 {
+		directories = []string{"releases"}
+	}
+
+	var releases []osl.Release
+	for _, directory := range directories {
+		parts, err := g.ReleasesService.ReleasesInDirectory(directory)
+		if err != nil {
+			return fmt.Errorf("could not read releases in %q: %w", directory, err)
+		}
+
+		for _, part := range parts {
+			manifest, ok := part.Metadata.(builder.ReleaseManifest)
+			if !ok {
+				continue
+			}
+
+			files, err := osl.ReadLicenses(g.FS, part.File)
+			if err != nil {
+				return err
+			}
+
+			if len(files) == 0 {
+				g.Logger.Printf("Warning: %s %s does not contain %s", manifest.Name, manifest.Version, osl.LicenseArchive)
+			}
+
+			releases = append(releases, osl.Release{Name: manifest.Name, Version: manifest.Version, Files: files})
+		}
+	}
+
+	outputFile := g.Options.OutputFile
+	if outputFile == "" {
+		outputFile = fmt.Sprintf("open_source_license_%s_%s.txt", slug, version)
+	}
+
+	err = util.WriteFile(g.FS, outputFile, osl.Document(slug, version, releases), 0644)
+	if err != nil {
+		return fmt.Errorf("could not write open source license: %w", err)
+	}
+
+	g.Logger.Printf("Wrote the licenses of %d releases to %s", len(releases), outputFile)
+	g.Logger.Printf("Upload it to %s on Pivnet with file type %q and file version %q so that kiln publish attaches it", slug, oslFileType, version)
+
+	return nil
+}
+
+func (g GenerateOSL) slug() (string, error) {
+	file, err := g.FS.Open(g.Options.Kilnfile)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var kilnfile cargo.Kilnfile
+	if err := yaml.NewDecoder(file).Decode(&kilnfile); err != nil {
+		return "", fmt.Errorf("could not parse Kilnfile: %s", err)
+	}
+
+	return kilnfile.Slug, nil
+}
+
+// version returns the major and minor version of the tile, which publish
+// matches against the file version of the open source license.
+func (g GenerateOSL) version() (string, error) {
+	file, err := g.FS.Open(g.Options.Version)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	contents, err := ioutil.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := semver.NewVersion(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return "", fmt.Errorf("could not parse version: %w", err)
+	}
+
+	rv, err := ReleaseVersionFromBuildVersion(version, "ga")
+	if err != nil {
+		return "", err
+	}
+
+	return rv.MajorAndMinor(), nil
+}
+
+func (g GenerateOSL) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command assembles the license.tgz archives of the release tarballs in the releases directories into an Open Source License document for the tile version.",
+		ShortDescription: "generates an open source license document from release tarballs",
+		Flags:            g.Options,
+	}
+}
@@ -0,0 +1,127 @@
+package commands_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"log"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	"github.com/pivotal-cf/kiln/builder"
+	"github.com/pivotal-cf/kiln/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/commands"
+)
+
+var _ = Describe("GenerateOSL", func() {
+	var _ jhanda.Command = GenerateOSL{}
+
+	var (
+		fs              billy.Filesystem
+		output          *gbytes.Buffer
+		releasesService *fakes.ReleaseDirectoryReader
+		cmd             GenerateOSL
+	)
+
+	tgz := func(name string, contents []byte) []byte {
+		var b bytes.Buffer
+		gw := gzip.NewWriter(&b)
+		tw := tar.NewWriter(gw)
+		Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write(contents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+		Expect(gw.Close()).To(Succeed())
+		return b.Bytes()
+	}
+
+	readFile := func(path string) string {
+		f, err := fs.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+
+		contents, err := ioutil.ReadAll(f)
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		releasesService = new(fakes.ReleaseDirectoryReader)
+		cmd = GenerateOSL{
+			FS:              fs,
+			Logger:          log.New(output, "", 0),
+			ReleasesService: releasesService,
+		}
+
+		Expect(util.WriteFile(fs, "Kilnfile", []byte("slug: some-product\n"), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "version", []byte("1.2.3-build.4\n"), 0644)).To(Succeed())
+
+		license := tgz("./LICENSE", []byte("some-license"))
+		Expect(util.WriteFile(fs, "releases/some-release-1.0.0.tgz", tgz("./license.tgz", license), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "releases/other-release-2.0.0.tgz", tgz("./release.MF", []byte("name: other-release")), 0644)).To(Succeed())
+
+		releasesService.ReleasesInDirectoryReturns([]builder.Part{
+			{File: "releases/some-release-1.0.0.tgz", Metadata: builder.ReleaseManifest{Name: "some-release", Version: "1.0.0"}},
+			{File: "releases/other-release-2.0.0.tgz", Metadata: builder.ReleaseManifest{Name: "other-release", Version: "2.0.0"}},
+		}, nil)
+	})
+
+	It("writes the licenses of every release to a document named after the tile version", func() {
+		err := cmd.Execute([]string{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(releasesService.ReleasesInDirectoryCallCount()).To(Equal(1))
+		Expect(releasesService.ReleasesInDirectoryArgsForCall(0)).To(Equal("releases"))
+
+		document := readFile("open_source_license_some-product_1.2.txt")
+		Expect(document).To(HavePrefix("Open Source License for some-product 1.2\n"))
+		Expect(document).To(ContainSubstring("other-release 2.0.0"))
+		Expect(document).To(ContainSubstring("some-release 1.0.0\n"))
+		Expect(document).To(ContainSubstring("--- LICENSE ---\n\nsome-license\n"))
+
+		Expect(output).To(gbytes.Say("Warning: other-release 2.0.0 does not contain license.tgz"))
+		Expect(output).To(gbytes.Say("Wrote the licenses of 2 releases to open_source_license_some-product_1.2.txt"))
+		Expect(output).To(gbytes.Say(`file type "Open Source License" and file version "1.2"`))
+	})
+
+	Context("when the releases directories and output file are given", func() {
+		It("reads those directories and writes to that file", func() {
+			err := cmd.Execute([]string{"--releases-directory", "some-releases", "--releases-directory", "other-releases", "--output-file", "osl.txt"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(releasesService.ReleasesInDirectoryCallCount()).To(Equal(2))
+			Expect(releasesService.ReleasesInDirectoryArgsForCall(0)).To(Equal("some-releases"))
+			Expect(releasesService.ReleasesInDirectoryArgsForCall(1)).To(Equal("other-releases"))
+			Expect(readFile("osl.txt")).To(HavePrefix("Open Source License for some-product 1.2\n"))
+		})
+	})
+
+	Context("when the releases cannot be read", func() {
+		It("returns an error", func() {
+			releasesService.ReleasesInDirectoryReturns(nil, errors.New("boom"))
+
+			err := cmd.Execute([]string{})
+			Expect(err).To(MatchError(`could not read releases in "releases": boom`))
+		})
+	})
+
+	Context("when the version file is not a semantic version", func() {
+		It("returns an error", func() {
+			Expect(util.WriteFile(fs, "version", []byte("banana"), 0644)).To(Succeed())
+
+			err := cmd.Execute([]string{})
+			Expect(err).To(MatchError(ContainSubstring("could not parse version")))
+		})
+	})
+})
@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/internal/tile"
+	"github.com/pivotal-cf/kiln/proofing"
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+const (
+	releaseStatusOK            = "ok"
+	releaseStatusSHA1Mismatch  = "sha1 mismatch"
+	releaseStatusMissing       = "missing from tile"
+	releaseStatusNotInMetadata = "not in metadata"
+)
+
+type Inspect struct {
+	FS     billy.Filesystem
+	Logger *log.Logger
+
+	Options struct {
+		Tile string `short:"t" long:"tile" required:"true" description:"path to a .pivotal file"`
+		JSON bool   `          long:"json"                 description:"output the report as JSON"`
+	}
+}
+
+type inspection struct {
+	Name             string             `json:"name"`
+	ProductVersion   string             `json:"product_version"`
+	StemcellCriteria inspectedStemcell  `json:"stemcell_criteria"`
+	Releases         []inspectedRelease `json:"releases"`
+	Migrations       []string           `json:"migrations"`
+	EmbedPaths       []string           `json:"embed_paths"`
+}
+
+type inspectedStemcell struct {
+	OS      string `json:"os"`
+	Version string `json:"version"`
+}
+
+type inspectedRelease struct {
+	Name         string `json:"name,omitempty"`
+	Version      string `json:"version,omitempty"`
+	File         string `json:"file"`
+	Size         int64  `json:"size"`
+	SHA1         string `json:"sha1,omitempty"`
+	MetadataSHA1 string `json:"metadata_sha1,omitempty"`
+	Status       string `json:"status"`
+}
+
+func (i Inspect) Execute(args []string) error {
+	_, err := jhanda.Parse(&i.Options, args)
+	if err != nil {
+		return err
+	}
+
+	t, err := tile.Read(i.FS, i.Options.Tile)
+	if err != nil {
+		return fmt.Errorf("could not read tile: %w", err)
+	}
+
+	productTemplate, err := proofing.Parse(bytes.NewReader(t.Metadata))
+	if err != nil {
+		return fmt.Errorf("could not parse tile metadata: %w", err)
+	}
+
+	result := inspectTile(productTemplate, t.Files)
+
+	if i.Options.JSON {
+		output, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err // NOTE: this cannot happen, the report only contains strings and integers
+		}
+		i.Logger.Println(string(output))
+		return nil
+	}
+
+	i.printInspection(result)
+
+	return nil
+}
+
+func (i Inspect) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command prints a summary of the metadata, releases, migrations and embedded files of a .pivotal file.",
+		ShortDescription: "prints a summary of a tile",
+		Flags:            i.Options,
+	}
+}
+
+func inspectTile(productTemplate proofing.ProductTemplate, files []tile.File) inspection {
+	result := inspection{
+		Name:           productTemplate.Name,
+		ProductVersion: productTemplate.ProductVersion,
+		StemcellCriteria: inspectedStemcell{
+			OS:      productTemplate.StemcellCriteria.OS,
+			Version: productTemplate.StemcellCriteria.Version,
+		},
+		Releases:   []inspectedRelease{},
+		Migrations: []string{},
+		EmbedPaths: []string{},
+	}
+
+	releaseFiles := map[string]tile.File{}
+	for _, f := range files {
+		switch {
+		case strings.HasPrefix(f.Name, "releases/"):
+			releaseFiles[path.Base(f.Name)] = f
+		case strings.HasPrefix(f.Name, "migrations/"):
+			result.Migrations = append(result.Migrations, f.Name)
+		case strings.HasPrefix(f.Name, "embed/"):
+			result.EmbedPaths = append(result.EmbedPaths, f.Name)
+		}
+	}
+
+	for _, r := range productTemplate.Releases {
+		inspected := inspectedRelease{
+			Name:         r.Name,
+			Version:      r.Version,
+			File:         r.File,
+			MetadataSHA1: r.SHA1,
+			Status:       releaseStatusMissing,
+		}
+
+		f, ok := releaseFiles[r.File]
+		if ok {
+			delete(releaseFiles, r.File)
+
+			inspected.Size = f.Size
+			inspected.SHA1 = f.SHA1
+			inspected.Status = releaseStatusOK
+			if r.SHA1 != "" && r.SHA1 != f.SHA1 {
+				inspected.Status = releaseStatusSHA1Mismatch
+			}
+		}
+
+		result.Releases = append(result.Releases, inspected)
+	}
+
+	var unlisted []string
+	for name := range releaseFiles {
+		unlisted = append(unlisted, name)
+	}
+	sort.Strings(unlisted)
+
+	for _, name := range unlisted {
+		f := releaseFiles[name]
+		result.Releases = append(result.Releases, inspectedRelease{
+			File:   name,
+			Size:   f.Size,
+			SHA1:   f.SHA1,
+			Status: releaseStatusNotInMetadata,
+		})
+	}
+
+	return result
+}
+
+func (i Inspect) printInspection(result inspection) {
+	i.Logger.Printf("Name: %s\n", result.Name)
+	i.Logger.Printf("Product version: %s\n", result.ProductVersion)
+	i.Logger.Printf("Stemcell criteria: %s %s\n", result.StemcellCriteria.OS, result.StemcellCriteria.Version)
+
+	i.Logger.Println("Releases:")
+	for _, r := range result.Releases {
+		switch r.Status {
+		case releaseStatusMissing:
+			i.Logger.Printf("- %s %s (%s): %s\n", r.Name, r.Version, r.File, r.Status)
+		case releaseStatusNotInMetadata:
+			i.Logger.Printf("- %s (%d bytes, sha1 %s): %s\n", r.File, r.Size, r.SHA1, r.Status)
+		case releaseStatusSHA1Mismatch:
+			i.Logger.Printf("- %s %s (%s, %d bytes, sha1 %s): %s, metadata has %s\n", r.Name, r.Version, r.File, r.Size, r.SHA1, r.Status, r.MetadataSHA1)
+		default:
+			i.Logger.Printf("- %s %s (%s, %d bytes, sha1 %s): %s\n", r.Name, r.Version, r.File, r.Size, r.SHA1, r.Status)
+		}
+	}
+
+	i.Logger.Println("Migrations:")
+	for _, migration := range result.Migrations {
+		i.Logger.Printf("- %s\n", migration)
+	}
+
+	i.Logger.Println("Embedded files:")
+	for _, embedPath := range result.EmbedPaths {
+		i.Logger.Printf("- %s\n", embedPath)
+	}
+}
@@ -0,0 +1,148 @@
+package commands_test
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"log"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/commands"
+)
+
+var _ = Describe("Inspect", func() {
+	var _ jhanda.Command = Inspect{}
+
+	const metadata = `---
+name: some-product
+product_version: 1.2.3
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "621.0"
+releases:
+- name: some-release
+  version: 1.0.0
+  file: some-release-1.0.0.tgz
+  sha1: 85315e4e02237be6e21c93a5b93f5309c0d80ee9
+- name: other-release
+  version: 2.0.0
+  file: other-release-2.0.0.tgz
+  sha1: bad-sha1
+- name: missing-release
+  version: 3.0.0
+  file: missing-release-3.0.0.tgz
+  sha1: some-sha1
+`
+
+	var (
+		fs      billy.Filesystem
+		output  *gbytes.Buffer
+		inspect Inspect
+	)
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		inspect = Inspect{
+			FS:     fs,
+			Logger: log.New(output, "", 0),
+		}
+
+		f, err := fs.Create("some-tile.pivotal")
+		Expect(err).NotTo(HaveOccurred())
+
+		zw := zip.NewWriter(f)
+		for _, file := range []struct{ name, contents string }{
+			{"metadata/metadata.yml", metadata},
+			{"migrations/v1/201911191111_some-migration.js", "some-migration"},
+			{"releases/some-release-1.0.0.tgz", "some-release"},
+			{"releases/other-release-2.0.0.tgz", "other-release"},
+			{"releases/stale-release-0.0.1.tgz", "stale-release"},
+			{"embed/some-dir/some-file", "some-file"},
+		} {
+			w, err := zw.Create(file.name)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte(file.contents))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(zw.Close()).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+	})
+
+	It("prints a summary of the tile", func() {
+		Expect(inspect.Execute([]string{"--tile", "some-tile.pivotal"})).To(Succeed())
+
+		Expect(output).To(gbytes.Say("Name: some-product"))
+		Expect(output).To(gbytes.Say("Product version: 1.2.3"))
+		Expect(output).To(gbytes.Say("Stemcell criteria: ubuntu-xenial 621.0"))
+		Expect(output).To(gbytes.Say("Releases:"))
+		Expect(output).To(gbytes.Say(`- some-release 1.0.0 \(some-release-1.0.0.tgz, 12 bytes, sha1 85315e4e02237be6e21c93a5b93f5309c0d80ee9\): ok`))
+		Expect(output).To(gbytes.Say(`- other-release 2.0.0 \(other-release-2.0.0.tgz, 13 bytes, sha1 [0-9a-f]{40}\): sha1 mismatch, metadata has bad-sha1`))
+		Expect(output).To(gbytes.Say(`- missing-release 3.0.0 \(missing-release-3.0.0.tgz\): missing from tile`))
+		Expect(output).To(gbytes.Say(`- stale-release-0.0.1.tgz \(13 bytes, sha1 [0-9a-f]{40}\): not in metadata`))
+		Expect(output).To(gbytes.Say("Migrations:"))
+		Expect(output).To(gbytes.Say("- migrations/v1/201911191111_some-migration.js"))
+		Expect(output).To(gbytes.Say("Embedded files:"))
+		Expect(output).To(gbytes.Say("- embed/some-dir/some-file"))
+	})
+
+	Context("when the --json flag is provided", func() {
+		It("prints the summary as JSON", func() {
+			Expect(inspect.Execute([]string{"--tile", "some-tile.pivotal", "--json"})).To(Succeed())
+
+			var result struct {
+				Name             string `json:"name"`
+				ProductVersion   string `json:"product_version"`
+				StemcellCriteria struct {
+					OS      string `json:"os"`
+					Version string `json:"version"`
+				} `json:"stemcell_criteria"`
+				Releases []struct {
+					Name   string `json:"name"`
+					File   string `json:"file"`
+					Size   int64  `json:"size"`
+					Status string `json:"status"`
+				} `json:"releases"`
+				Migrations []string `json:"migrations"`
+				EmbedPaths []string `json:"embed_paths"`
+			}
+			Expect(json.Unmarshal(output.Contents(), &result)).To(Succeed())
+
+			Expect(result.Name).To(Equal("some-product"))
+			Expect(result.ProductVersion).To(Equal("1.2.3"))
+			Expect(result.StemcellCriteria.OS).To(Equal("ubuntu-xenial"))
+			Expect(result.StemcellCriteria.Version).To(Equal("621.0"))
+			Expect(result.Releases).To(HaveLen(4))
+			Expect(result.Releases[0].Name).To(Equal("some-release"))
+			Expect(result.Releases[0].Size).To(Equal(int64(12)))
+			Expect(result.Releases[0].Status).To(Equal("ok"))
+			Expect(result.Releases[1].Status).To(Equal("sha1 mismatch"))
+			Expect(result.Releases[2].Status).To(Equal("missing from tile"))
+			Expect(result.Releases[3].File).To(Equal("stale-release-0.0.1.tgz"))
+			Expect(result.Releases[3].Status).To(Equal("not in metadata"))
+			Expect(result.Migrations).To(Equal([]string{"migrations/v1/201911191111_some-migration.js"}))
+			Expect(result.EmbedPaths).To(Equal([]string{"embed/some-dir/some-file"}))
+		})
+	})
+
+	Context("failure cases", func() {
+		Context("when the tile cannot be read", func() {
+			It("returns an error", func() {
+				err := inspect.Execute([]string{"--tile", "missing.pivotal"})
+				Expect(err).To(MatchError(ContainSubstring("could not read tile")))
+			})
+		})
+
+		Context("when the flags cannot be parsed", func() {
+			It("returns an error", func() {
+				err := inspect.Execute([]string{"--unknown-flag"})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/proofing"
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+type Lint struct {
+	FS     billy.Filesystem
+	Logger *log.Logger
+
+	Options struct {
+		Metadata string `short:"m" long:"metadata" required:"true" description:"path to a baked metadata file"`
+	}
+}
+
+func (l Lint) Execute(args []string) error {
+	_, err := jhanda.Parse(&l.Options, args)
+	if err != nil {
+		return err
+	}
+
+	file, err := l.FS.Open(l.Options.Metadata)
+	if err != nil {
+		return fmt.Errorf("could not open metadata: %w", err)
+	}
+	defer file.Close()
+
+	productTemplate, err := proofing.Parse(file)
+	if err != nil {
+		return fmt.Errorf("could not parse metadata: %w", err)
+	}
+
+	report := productTemplate.CheckPropertyReferences()
+	if report.Empty() {
+		l.Logger.Println("No property reference problems found")
+		return nil
+	}
+
+	if len(report.UndefinedReferences) > 0 {
+		l.Logger.Println("Undefined property references:")
+		for _, reference := range report.UndefinedReferences {
+			l.Logger.Printf("- %s (in %s)\n", reference.Accessor, reference.Source)
+		}
+	}
+
+	if len(report.UnreferencedBlueprints) > 0 {
+		l.Logger.Println("Property blueprints that are never referenced:")
+		for _, property := range report.UnreferencedBlueprints {
+			l.Logger.Printf("- %s\n", property)
+		}
+	}
+
+	if len(report.ConfigurableBlueprintsNotInForm) > 0 {
+		l.Logger.Println("Configurable property blueprints that do not appear in any form:")
+		for _, property := range report.ConfigurableBlueprintsNotInForm {
+			l.Logger.Printf("- %s\n", property)
+		}
+	}
+
+	if len(report.UndefinedReferences) > 0 {
+		return fmt.Errorf("found %d undefined property reference(s)", len(report.UndefinedReferences))
+	}
+
+	return nil
+}
+
+func (l Lint) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command checks the property accessors in baked tile metadata against its property blueprints and forms.",
+		ShortDescription: "checks property references in tile metadata",
+		Flags:            l.Options,
+	}
+}
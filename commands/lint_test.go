@@ -0,0 +1,116 @@
+package commands_test
+
+import (
+	"log"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/commands"
+)
+
+var _ = Describe("Lint", func() {
+	var _ jhanda.Command = Lint{}
+
+	var (
+		fs     billy.Filesystem
+		output *gbytes.Buffer
+		lint   Lint
+	)
+
+	writeMetadata := func(contents string) {
+		f, err := fs.Create("metadata.yml")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.Write([]byte(contents))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		lint = Lint{
+			FS:     fs,
+			Logger: log.New(output, "", 0),
+		}
+	})
+
+	Context("when every property reference resolves", func() {
+		BeforeEach(func() {
+			writeMetadata(`---
+form_types:
+- name: some-form
+  property_inputs:
+  - reference: .properties.some-property
+property_blueprints:
+- name: some-property
+  type: string
+  configurable: true
+job_types:
+- name: some-job
+  manifest: |
+    some-key: (( .properties.some-property.value ))
+`)
+		})
+
+		It("succeeds", func() {
+			Expect(lint.Execute([]string{"--metadata", "metadata.yml"})).To(Succeed())
+			Expect(output).To(gbytes.Say("No property reference problems found"))
+		})
+	})
+
+	Context("when there are problems", func() {
+		BeforeEach(func() {
+			writeMetadata(`---
+property_blueprints:
+- name: some-property
+  type: string
+  configurable: true
+job_types:
+- name: some-job
+  manifest: |
+    some-key: (( .properties.some-missing-property.value ))
+`)
+		})
+
+		It("reports them and returns an error", func() {
+			err := lint.Execute([]string{"--metadata", "metadata.yml"})
+			Expect(err).To(MatchError("found 1 undefined property reference(s)"))
+
+			Expect(output).To(gbytes.Say("Undefined property references:"))
+			Expect(output).To(gbytes.Say(`- .properties.some-missing-property.value \(in job_types\[some-job\].manifest\)`))
+			Expect(output).To(gbytes.Say("Property blueprints that are never referenced:"))
+			Expect(output).To(gbytes.Say("- .properties.some-property"))
+			Expect(output).To(gbytes.Say("Configurable property blueprints that do not appear in any form:"))
+			Expect(output).To(gbytes.Say("- .properties.some-property"))
+		})
+	})
+
+	Context("failure cases", func() {
+		Context("when the metadata file does not exist", func() {
+			It("returns an error", func() {
+				err := lint.Execute([]string{"--metadata", "missing.yml"})
+				Expect(err).To(MatchError(ContainSubstring("could not open metadata")))
+			})
+		})
+
+		Context("when the metadata file is malformed", func() {
+			It("returns an error", func() {
+				writeMetadata("%%%")
+				err := lint.Execute([]string{"--metadata", "metadata.yml"})
+				Expect(err).To(MatchError(ContainSubstring("could not parse metadata")))
+			})
+		})
+
+		Context("when the flags cannot be parsed", func() {
+			It("returns an error", func() {
+				err := lint.Execute([]string{"--unknown-flag"})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strconv"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/internal/cargo"
+	"github.com/pivotal-cf/kiln/internal/cargo/opsman"
+	"github.com/pivotal-cf/kiln/proofing"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/util"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type Manifest struct {
+	FS     billy.Filesystem
+	Logger *log.Logger
+
+	Options struct {
+		Metadata       string `short:"m" long:"metadata"        required:"true" description:"path to the .pivotal file or metadata file"`
+		Config         string `short:"c" long:"config"          required:"true" description:"path to the product configuration in the format of om configure-product"`
+		DeploymentName string `short:"n" long:"deployment-name"                 description:"name of the deployment, defaults to the product name"`
+		OutputFile     string `short:"o" long:"output-file"                     description:"path to write the manifest to, defaults to stdout"`
+	}
+}
+
+// manifestConfig is the subset of the om configure-product format that
+// describes a deployment, with the static IPs of each job and the values
+// only Ops Manager knows, which om does not configure.
+type manifestConfig struct {
+	ProductProperties map[string]struct {
+		Value interface{} `yaml:"value"`
+	} `yaml:"product-properties"`
+	NetworkProperties struct {
+		Network struct {
+			Name string `yaml:"name"`
+		} `yaml:"network"`
+		SingletonAvailabilityZone struct {
+			Name string `yaml:"name"`
+		} `yaml:"singleton_availability_zone"`
+		OtherAvailabilityZones []struct {
+			Name string `yaml:"name"`
+		} `yaml:"other_availability_zones"`
+	} `yaml:"network-properties"`
+	ResourceConfig map[string]struct {
+		Instances    interface{} `yaml:"instances"`
+		InstanceType struct {
+			ID string `yaml:"id"`
+		} `yaml:"instance_type"`
+		PersistentDisk struct {
+			SizeMB string `yaml:"size_mb"`
+		} `yaml:"persistent_disk"`
+		StaticIPs []string `yaml:"static_ips"`
+	} `yaml:"resource-config"`
+
+	// Self, Director and OtherProducts are the values of the $self.*,
+	// $director.* and ..other-product accessors.
+	Self          map[string]interface{} `yaml:"self"`
+	Director      map[string]interface{} `yaml:"director"`
+	OtherProducts map[string]interface{} `yaml:"other-products"`
+}
+
+func (m Manifest) Execute(args []string) error {
+	_, err := jhanda.Parse(&m.Options, args)
+	if err != nil {
+		return err
+	}
+
+	template, err := readProductTemplate(m.FS, m.Options.Metadata)
+	if err != nil {
+		return err
+	}
+
+	config, err := m.readConfig(template)
+	if err != nil {
+		return err
+	}
+
+	if config.DeploymentName == "" {
+		config.DeploymentName = template.Name
+	}
+
+	manifest, err := cargo.NewGenerator().Execute(template, config)
+	if err != nil {
+		return fmt.Errorf("could not generate manifest: %w", err)
+	}
+
+	output, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err // NOTE: this cannot happen, the manifest was unmarshalled from YAML
+	}
+
+	if m.Options.OutputFile == "" {
+		m.Logger.Print(string(output))
+		return nil
+	}
+
+	err = util.WriteFile(m.FS, m.Options.OutputFile, output, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// readConfig converts the product configuration into an OpsManagerConfig.
+// Job types default to automatic instances and the "default" VM type, and
+// are deployed to the other availability zones, or to the singleton
+// availability zone when there are none.
+func (m Manifest) readConfig(template proofing.ProductTemplate) (cargo.OpsManagerConfig, error) {
+	file, err := m.FS.Open(m.Options.Config)
+	if err != nil {
+		return cargo.OpsManagerConfig{}, fmt.Errorf("could not read config: %w", err)
+	}
+	defer file.Close()
+
+	contents, err := ioutil.ReadAll(file)
+	if err != nil {
+		return cargo.OpsManagerConfig{}, fmt.Errorf("could not read config: %w", err)
+	}
+
+	var mc manifestConfig
+	err = yaml.Unmarshal(contents, &mc)
+	if err != nil {
+		return cargo.OpsManagerConfig{}, fmt.Errorf("could not parse config: %w", err)
+	}
+
+	if mc.NetworkProperties.Network.Name == "" {
+		return cargo.OpsManagerConfig{}, fmt.Errorf("config %q does not set network-properties.network.name", m.Options.Config)
+	}
+
+	config := cargo.OpsManagerConfig{
+		DeploymentName: m.Options.DeploymentName,
+		Network:        mc.NetworkProperties.Network.Name,
+		Properties:     map[string]interface{}{},
+		Self:           mc.Self,
+		Director:       mc.Director,
+		OtherProducts:  mc.OtherProducts,
+	}
+
+	for _, az := range mc.NetworkProperties.OtherAvailabilityZones {
+		config.AvailabilityZones = append(config.AvailabilityZones, az.Name)
+	}
+
+	if singleton := mc.NetworkProperties.SingletonAvailabilityZone.Name; len(config.AvailabilityZones) == 0 && singleton != "" {
+		config.AvailabilityZones = []string{singleton}
+	}
+
+	for property, value := range mc.ProductProperties {
+		config.Properties[property] = value.Value
+	}
+
+	for name := range mc.ResourceConfig {
+		if !hasJobType(template, name) {
+			return cargo.OpsManagerConfig{}, fmt.Errorf("resource-config.%s does not match a job type", name)
+		}
+	}
+
+	for _, jobType := range template.JobTypes {
+		name := jobType.Name
+		rc := mc.ResourceConfig[name]
+
+		resourceConfig := opsman.ResourceConfig{
+			Name:      name,
+			Instances: opsman.ResourceConfigInstances{Value: -1},
+			VMType:    "default",
+			StaticIPs: rc.StaticIPs,
+		}
+
+		switch instances := rc.Instances.(type) {
+		case int:
+			resourceConfig.Instances.Value = instances
+		case nil:
+		default:
+			if instances != "automatic" {
+				return cargo.OpsManagerConfig{}, fmt.Errorf("resource-config.%s.instances must be a number or automatic, got %v", name, instances)
+			}
+		}
+
+		if id := rc.InstanceType.ID; id != "" && id != "automatic" {
+			resourceConfig.VMType = id
+		}
+
+		if size := rc.PersistentDisk.SizeMB; size != "" && size != "automatic" {
+			resourceConfig.PersistentDisk, err = strconv.Atoi(size)
+			if err != nil {
+				return cargo.OpsManagerConfig{}, fmt.Errorf("resource-config.%s.persistent_disk.size_mb must be a number or automatic, got %q", name, size)
+			}
+		}
+
+		config.ResourceConfigs = append(config.ResourceConfigs, resourceConfig)
+	}
+
+	return config, nil
+}
+
+func hasJobType(template proofing.ProductTemplate, name string) bool {
+	for _, jobType := range template.JobTypes {
+		if jobType.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Manifest) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command renders a BOSH deployment manifest from tile metadata and a product configuration in the format of om configure-product, evaluating the property accessors in the job manifests, so the jobs of a tile can be deployed to a BOSH director without Ops Manager.",
+		ShortDescription: "renders a BOSH deployment manifest from tile metadata",
+		Flags:            m.Options,
+	}
+}
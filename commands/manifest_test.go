@@ -0,0 +1,272 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"log"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf-experimental/gomegamatchers"
+	. "github.com/pivotal-cf/kiln/commands"
+)
+
+var _ = Describe("Manifest", func() {
+	var _ jhanda.Command = Manifest{}
+
+	const (
+		metadata = `---
+name: some-product
+releases:
+- name: some-release
+  version: 1.2.3
+  sha1: some-sha1
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "621.0"
+property_blueprints:
+- name: hostname
+  type: string
+job_types:
+- name: some-job
+  instance_definition:
+    default: 1
+  templates:
+  - name: some-template
+    release: some-release
+    manifest: |
+      hostname: (( .properties.hostname.value ))
+- name: other-job
+  instance_definition:
+    default: 1
+  templates:
+  - name: other-template
+    release: some-release
+`
+		config = `---
+product-properties:
+  .properties.hostname:
+    value: example.com
+network-properties:
+  network:
+    name: some-network
+  other_availability_zones:
+  - name: z1
+resource-config:
+  some-job:
+    instances: 2
+    instance_type:
+      id: large
+    persistent_disk:
+      size_mb: "10240"
+`
+		expectedManifest = `---
+name: some-product
+releases:
+- name: some-release
+  version: 1.2.3
+  sha1: some-sha1
+stemcells:
+- alias: default
+  os: ubuntu-xenial
+  version: "621.0"
+update:
+  canaries: 1
+  canary_watch_time: "30000-300000"
+  update_watch_time: "30000-300000"
+  max_in_flight: 1
+  max_errors: 2
+  serial: false
+variables: []
+instance_groups:
+- name: some-job
+  azs: [z1]
+  lifecycle: service
+  stemcell: default
+  instances: 2
+  vm_type: large
+  persistent_disk: 10240
+  networks:
+  - name: some-network
+  jobs:
+  - name: some-template
+    release: some-release
+    provides: {}
+    consumes: {}
+    properties:
+      hostname: example.com
+  properties: {}
+- name: other-job
+  azs: [z1]
+  lifecycle: service
+  stemcell: default
+  instances: 1
+  vm_type: default
+  networks:
+  - name: some-network
+  jobs:
+  - name: other-template
+    release: some-release
+    provides: {}
+    consumes: {}
+    properties: {}
+  properties: {}
+`
+	)
+
+	var (
+		fs     billy.Filesystem
+		output *gbytes.Buffer
+		cmd    Manifest
+	)
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		cmd = Manifest{
+			FS:     fs,
+			Logger: log.New(output, "", 0),
+		}
+
+		Expect(util.WriteFile(fs, "metadata.yml", []byte(metadata), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "config.yml", []byte(config), 0644)).To(Succeed())
+	})
+
+	It("prints the manifest", func() {
+		err := cmd.Execute([]string{"--metadata", "metadata.yml", "--config", "config.yml"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.Contents()).To(HelpfullyMatchYAML(expectedManifest))
+	})
+
+	Context("when an output file and deployment name are given", func() {
+		It("writes the manifest to the file", func() {
+			err := cmd.Execute([]string{"--metadata", "metadata.yml", "--config", "config.yml", "--deployment-name", "dev", "--output-file", "manifest.yml"})
+			Expect(err).NotTo(HaveOccurred())
+
+			f, err := fs.Open("manifest.yml")
+			Expect(err).NotTo(HaveOccurred())
+			defer f.Close()
+
+			contents, err := ioutil.ReadAll(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(HavePrefix("name: dev\n"))
+		})
+	})
+
+	Context("when the config sets static IPs, a singleton availability zone and values only Ops Manager knows", func() {
+		It("evaluates the accessors with them", func() {
+			Expect(util.WriteFile(fs, "metadata.yml", []byte(`---
+name: some-product
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "621.0"
+job_types:
+- name: some-job
+  instance_definition:
+    default: 1
+  templates:
+  - name: some-template
+    release: some-release
+    manifest: |
+      ip: (( .ip ))
+      first_ip: (( .some-job.first_ip ))
+      azs: (( .some-job.availability_zones ))
+      client_secret: (( $self.uaa_client_secret ))
+      director: (( $director.hostname ))
+      system_domain: (( ..cf.properties.system_domain.value ))
+`), 0644)).To(Succeed())
+			Expect(util.WriteFile(fs, "config.yml", []byte(`---
+network-properties:
+  network:
+    name: some-network
+  singleton_availability_zone:
+    name: z1
+resource-config:
+  some-job:
+    static_ips: [10.0.0.1]
+self:
+  uaa_client_secret: some-secret
+director:
+  hostname: director.example.com
+other-products:
+  ..cf.properties.system_domain.value: sys.example.com
+`), 0644)).To(Succeed())
+
+			err := cmd.Execute([]string{"--metadata", "metadata.yml", "--config", "config.yml"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(output.Contents()).To(HelpfullyMatchYAML(`---
+name: some-product
+releases: []
+stemcells:
+- alias: default
+  os: ubuntu-xenial
+  version: "621.0"
+update:
+  canaries: 1
+  canary_watch_time: "30000-300000"
+  update_watch_time: "30000-300000"
+  max_in_flight: 1
+  max_errors: 2
+  serial: false
+variables: []
+instance_groups:
+- name: some-job
+  azs: [z1]
+  lifecycle: service
+  stemcell: default
+  instances: 1
+  vm_type: default
+  networks:
+  - name: some-network
+    static_ips: [10.0.0.1]
+  jobs:
+  - name: some-template
+    release: some-release
+    provides: {}
+    consumes: {}
+    properties:
+      ip: 10.0.0.1
+      first_ip: 10.0.0.1
+      azs: [z1]
+      client_secret: some-secret
+      director: director.example.com
+      system_domain: sys.example.com
+  properties: {}
+`))
+		})
+	})
+
+	Context("when the config does not set a network", func() {
+		It("returns an error", func() {
+			Expect(util.WriteFile(fs, "config.yml", []byte("product-properties: {}"), 0644)).To(Succeed())
+
+			err := cmd.Execute([]string{"--metadata", "metadata.yml", "--config", "config.yml"})
+			Expect(err).To(MatchError(`config "config.yml" does not set network-properties.network.name`))
+		})
+	})
+
+	Context("when the resource config names an unknown job type", func() {
+		It("returns an error", func() {
+			Expect(util.WriteFile(fs, "config.yml", []byte("network-properties: {network: {name: n}}\nresource-config: {missing-job: {instances: 1}}"), 0644)).To(Succeed())
+
+			err := cmd.Execute([]string{"--metadata", "metadata.yml", "--config", "config.yml"})
+			Expect(err).To(MatchError("resource-config.missing-job does not match a job type"))
+		})
+	})
+
+	Context("when a property accessor cannot be evaluated", func() {
+		It("returns an error", func() {
+			Expect(util.WriteFile(fs, "config.yml", []byte("network-properties: {network: {name: n}}"), 0644)).To(Succeed())
+
+			err := cmd.Execute([]string{"--metadata", "metadata.yml", "--config", "config.yml"})
+			Expect(err).To(MatchError(ContainSubstring(`property ".properties.hostname" is required but has no value`)))
+		})
+	})
+})
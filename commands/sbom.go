@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/builder"
+	"github.com/pivotal-cf/kiln/internal/sbom"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/util"
+)
+
+type SBOM struct {
+	FS              billy.Filesystem
+	Logger          *log.Logger
+	KilnVersion     string
+	ReleasesService releaseDirectoryReader
+
+	Options struct {
+		Tile               string   `short:"t"  long:"tile"               description:"path to a .pivotal file"`
+		Metadata           string   `short:"m"  long:"metadata"           description:"path to a baked metadata file, such as the output of kiln bake --metadata-only"`
+		Kilnfile           string   `short:"kf" long:"kilnfile"           description:"path to the Kilnfile, its Kilnfile.lock adds the release source and remote path of each release"`
+		ReleaseDirectories []string `short:"rd" long:"releases-directory" description:"path to a directory containing the release tarballs of --metadata, used to compute their SHA256"`
+		Format             string   `short:"f"  long:"format"             description:"format of the SBOM: cyclonedx or spdx" default:"cyclonedx"`
+		OutputFile         string   `short:"o"  long:"output-file"        description:"path to write the SBOM to, defaults to stdout"`
+	}
+}
+
+func (s SBOM) Execute(args []string) error {
+	_, err := jhanda.Parse(&s.Options, args)
+	if err != nil {
+		return err
+	}
+
+	if (s.Options.Tile == "") == (s.Options.Metadata == "") {
+		return errors.New("exactly one of --tile or --metadata must be provided")
+	}
+
+	err = sbom.ValidateFormat(s.Options.Format)
+	if err != nil {
+		return err
+	}
+
+	service := sbom.NewService(s.FS, s.KilnVersion)
+
+	var document []byte
+	if s.Options.Tile != "" {
+		document, err = service.FromTile(s.Options.Format, s.Options.Tile, s.Options.Kilnfile)
+	} else {
+		var metadata []byte
+		metadata, err = s.readFile(s.Options.Metadata)
+		if err != nil {
+			return fmt.Errorf("could not read metadata: %w", err)
+		}
+
+		var releases []builder.Part
+		for _, directory := range s.Options.ReleaseDirectories {
+			parts, err := s.ReleasesService.ReleasesInDirectory(directory)
+			if err != nil {
+				return fmt.Errorf("could not read releases in %q: %w", directory, err)
+			}
+
+			releases = append(releases, parts...)
+		}
+
+		document, err = service.FromMetadata(s.Options.Format, metadata, s.Options.Kilnfile, releases)
+	}
+	if err != nil {
+		return fmt.Errorf("could not generate SBOM: %w", err)
+	}
+
+	if s.Options.OutputFile == "" {
+		s.Logger.Printf("%s", document)
+		return nil
+	}
+
+	err = util.WriteFile(s.FS, s.Options.OutputFile, document, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write SBOM: %w", err)
+	}
+
+	return nil
+}
+
+func (s SBOM) readFile(path string) ([]byte, error) {
+	f, err := s.FS.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+}
+
+func (s SBOM) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command writes a CycloneDX or SPDX software bill of materials listing the releases and stemcell of a tile or of baked metadata.",
+		ShortDescription: "generates a software bill of materials",
+		Flags:            s.Options,
+	}
+}
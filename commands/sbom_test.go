@@ -0,0 +1,130 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/kiln/builder"
+	. "github.com/pivotal-cf/kiln/commands"
+	"github.com/pivotal-cf/kiln/commands/fakes"
+)
+
+var _ = Describe("SBOM", func() {
+	var _ jhanda.Command = SBOM{}
+
+	const metadata = `---
+name: some-product
+product_version: 1.2.3
+releases:
+- name: some-release
+  version: 1.0.0
+  file: some-release-1.0.0.tgz
+  sha1: some-sha1
+`
+
+	var (
+		fs              billy.Filesystem
+		output          *gbytes.Buffer
+		releasesService *fakes.ReleaseDirectoryReader
+		cmd             SBOM
+	)
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		releasesService = &fakes.ReleaseDirectoryReader{}
+		cmd = SBOM{
+			FS:              fs,
+			Logger:          log.New(output, "", 0),
+			KilnVersion:     "0.15.0",
+			ReleasesService: releasesService,
+		}
+
+		Expect(util.WriteFile(fs, "metadata.yml", []byte(metadata), 0644)).To(Succeed())
+	})
+
+	It("prints a CycloneDX SBOM of the metadata", func() {
+		err := cmd.Execute([]string{"--metadata", "metadata.yml"})
+		Expect(err).NotTo(HaveOccurred())
+
+		var document map[string]interface{}
+		Expect(json.Unmarshal(output.Contents(), &document)).To(Succeed())
+		Expect(document).To(HaveKeyWithValue("bomFormat", "CycloneDX"))
+	})
+
+	Context("when the format and output file are given", func() {
+		It("writes the SBOM in that format", func() {
+			err := cmd.Execute([]string{"--metadata", "metadata.yml", "--format", "spdx", "--output-file", "sbom.spdx.json"})
+			Expect(err).NotTo(HaveOccurred())
+
+			f, err := fs.Open("sbom.spdx.json")
+			Expect(err).NotTo(HaveOccurred())
+			defer f.Close()
+
+			contents, err := ioutil.ReadAll(f)
+			Expect(err).NotTo(HaveOccurred())
+
+			var document map[string]interface{}
+			Expect(json.Unmarshal(contents, &document)).To(Succeed())
+			Expect(document).To(HaveKeyWithValue("spdxVersion", "SPDX-2.3"))
+			Expect(output.Contents()).To(BeEmpty())
+		})
+	})
+
+	Context("when neither --tile nor --metadata is given", func() {
+		It("returns an error", func() {
+			err := cmd.Execute([]string{})
+			Expect(err).To(MatchError("exactly one of --tile or --metadata must be provided"))
+		})
+	})
+
+	Context("when releases directories are given", func() {
+		It("uses the SHA256 of the releases read from them", func() {
+			releasesService.ReleasesInDirectoryReturns([]builder.Part{
+				{
+					File: "releases/some-release-1.0.0.tgz",
+					Name: "some-release",
+					Metadata: builder.ReleaseManifest{
+						Name:    "some-release",
+						Version: "1.0.0",
+						File:    "some-release-1.0.0.tgz",
+						SHA256:  "some-sha256",
+					},
+				},
+			}, nil)
+
+			err := cmd.Execute([]string{"--metadata", "metadata.yml", "--releases-directory", "releases"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(releasesService.ReleasesInDirectoryCallCount()).To(Equal(1))
+			Expect(releasesService.ReleasesInDirectoryArgsForCall(0)).To(Equal("releases"))
+			Expect(output).To(gbytes.Say("some-sha256"))
+		})
+
+		Context("when a releases directory cannot be read", func() {
+			It("returns an error", func() {
+				releasesService.ReleasesInDirectoryReturns(nil, errors.New("no such directory"))
+
+				err := cmd.Execute([]string{"--metadata", "metadata.yml", "--releases-directory", "missing"})
+				Expect(err).To(MatchError(`could not read releases in "missing": no such directory`))
+			})
+		})
+	})
+
+	Context("when the format is not supported", func() {
+		It("returns an error", func() {
+			err := cmd.Execute([]string{"--metadata", "metadata.yml", "--format", "swid"})
+			Expect(err).To(MatchError(`unsupported SBOM format "swid", expected cyclonedx or spdx`))
+		})
+	})
+})
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/internal/migrations"
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+const migrationTimeout = 10 * time.Second
+
+type TestMigrations struct {
+	FS     billy.Filesystem
+	Logger *log.Logger
+
+	Options struct {
+		MigrationDirectories []string `short:"md" long:"migrations-directory" description:"path to a directory containing migrations, defaults to migrations"`
+		FixturesDirectory    string   `short:"fd" long:"fixtures-directory"   description:"path to a directory containing a directory with an input.json and an output.json for each fixture, defaults to the tests directory of the first migrations directory"`
+	}
+}
+
+func (t TestMigrations) Execute(args []string) error {
+	_, err := jhanda.Parse(&t.Options, args)
+	if err != nil {
+		return err
+	}
+
+	directories := t.Options.MigrationDirectories
+	if len(directories) == 0 {
+		directories = []string{"migrations"}
+	}
+
+	fixturesDirectory := t.Options.FixturesDirectory
+	if fixturesDirectory == "" {
+		fixturesDirectory = filepath.Join(directories[0], migrations.TestsDirectory)
+	}
+
+	loaded, err := migrations.Load(t.FS, directories)
+	if err != nil {
+		return err
+	}
+
+	fixtures, err := migrations.LoadFixtures(t.FS, fixturesDirectory)
+	if err != nil {
+		return err
+	}
+
+	if len(fixtures) == 0 {
+		return fmt.Errorf("no migration fixtures found in %q", fixturesDirectory)
+	}
+
+	t.Logger.Printf("Running %d migrations against %d fixtures...", len(loaded), len(fixtures))
+
+	runner := migrations.NewRunner(migrationTimeout)
+
+	var failed int
+	for _, fixture := range fixtures {
+		output, err := runner.Run(loaded, fixture.Input)
+		if err != nil {
+			failed++
+			t.Logger.Printf("FAIL %s\n  %s", fixture.Name, err)
+			continue
+		}
+
+		differences, err := migrations.Diff(fixture.Output, output)
+		if err != nil {
+			failed++
+			t.Logger.Printf("FAIL %s\n  %s", fixture.Name, err)
+			continue
+		}
+
+		if len(differences) > 0 {
+			failed++
+			t.Logger.Printf("FAIL %s", fixture.Name)
+			for _, difference := range differences {
+				t.Logger.Printf("  %s", difference)
+			}
+			continue
+		}
+
+		t.Logger.Printf("PASS %s", fixture.Name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d migration fixtures failed", failed, len(fixtures))
+	}
+
+	return nil
+}
+
+func (t TestMigrations) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command applies the JavaScript migrations, in file-name order, to the input.json of each fixture and compares the result to its output.json.",
+		ShortDescription: "runs migrations against fixture installations",
+		Flags:            t.Options,
+	}
+}
@@ -0,0 +1,80 @@
+package commands_test
+
+import (
+	"log"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/commands"
+)
+
+var _ = Describe("TestMigrations", func() {
+	var _ jhanda.Command = TestMigrations{}
+
+	var (
+		fs     billy.Filesystem
+		output *gbytes.Buffer
+		cmd    TestMigrations
+	)
+
+	writeFile := func(path, contents string) {
+		Expect(util.WriteFile(fs, path, []byte(contents), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		cmd = TestMigrations{
+			FS:     fs,
+			Logger: log.New(output, "", 0),
+		}
+
+		writeFile("migrations/201801010000_rename.js", `exports.migrate = function(input) {
+  input.properties[".properties.new"] = input.properties[".properties.old"];
+  delete input.properties[".properties.old"];
+  return input;
+};`)
+		writeFile("migrations/201901010000_increment.js", `exports.migrate = function(input) {
+  input.properties[".properties.new"].value += 1;
+  return input;
+};`)
+		writeFile("migrations/tests/rename/input.json", `{"properties": {".properties.old": {"value": 1}}}`)
+		writeFile("migrations/tests/rename/output.json", `{"properties": {".properties.new": {"value": 2}}}`)
+	})
+
+	It("applies the migrations in order to each fixture", func() {
+		err := cmd.Execute([]string{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output).To(gbytes.Say("Running 2 migrations against 1 fixtures..."))
+		Expect(output).To(gbytes.Say("PASS rename"))
+	})
+
+	Context("when the output of a fixture does not match", func() {
+		It("prints the differences and returns an error", func() {
+			writeFile("other-tests/wrong/input.json", `{"properties": {".properties.old": {"value": 1}}}`)
+			writeFile("other-tests/wrong/output.json", `{"properties": {".properties.new": {"value": 1}}}`)
+			writeFile("other-tests/broken/input.json", `{"properties": {}}`)
+			writeFile("other-tests/broken/output.json", `{"properties": {}}`)
+
+			err := cmd.Execute([]string{"--migrations-directory", "migrations", "--fixtures-directory", "other-tests"})
+			Expect(err).To(MatchError("2 of 2 migration fixtures failed"))
+
+			Expect(output).To(gbytes.Say(`FAIL broken\n  migration 201901010000_increment.js failed: TypeError`))
+			Expect(output).To(gbytes.Say(`FAIL wrong\n  /properties/.properties.new/value: expected 1, got 2`))
+		})
+	})
+
+	Context("when there are no fixtures", func() {
+		It("returns an error", func() {
+			err := cmd.Execute([]string{"--fixtures-directory", "missing"})
+			Expect(err).To(MatchError(`no migration fixtures found in "missing"`))
+		})
+	})
+})
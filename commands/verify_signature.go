@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/internal/signing"
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+type VerifySignature struct {
+	FS     billy.Filesystem
+	Logger *log.Logger
+
+	Options struct {
+		Tile      string `short:"t" long:"tile"       required:"true" description:"path to a .pivotal file"`
+		PublicKey string `short:"k" long:"public-key" required:"true" description:"path to the ed25519 or PGP public key matching the signing key"`
+		Signature string `short:"s" long:"signature"                  description:"path to the detached signature, defaults to the tile path with a .sig extension"`
+	}
+}
+
+func (v VerifySignature) Execute(args []string) error {
+	_, err := jhanda.Parse(&v.Options, args)
+	if err != nil {
+		return err
+	}
+
+	signaturePath := v.Options.Signature
+	if signaturePath == "" {
+		signaturePath = v.Options.Tile + signing.SignatureExtension
+	}
+
+	publicKey, err := v.readFile(v.Options.PublicKey)
+	if err != nil {
+		return fmt.Errorf("could not read public key: %w", err)
+	}
+
+	signature, err := v.readFile(signaturePath)
+	if err != nil {
+		return fmt.Errorf("could not read signature: %w", err)
+	}
+
+	tile, err := v.FS.Open(v.Options.Tile)
+	if err != nil {
+		return fmt.Errorf("could not read tile: %w", err)
+	}
+	defer tile.Close()
+
+	err = signing.Verify(publicKey, tile, signature)
+	if err != nil {
+		return fmt.Errorf("could not verify the signature of %s: %w", v.Options.Tile, err)
+	}
+
+	v.Logger.Printf("The signature of %s is valid\n", v.Options.Tile)
+
+	return nil
+}
+
+func (v VerifySignature) readFile(path string) ([]byte, error) {
+	f, err := v.FS.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+}
+
+func (v VerifySignature) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command checks the detached signature that kiln bake --signing-key writes next to a .pivotal file.",
+		ShortDescription: "verifies the signature of a tile",
+		Flags:            v.Options,
+	}
+}
@@ -0,0 +1,80 @@
+package commands_test
+
+import (
+	"log"
+	"strings"
+
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/commands"
+	"github.com/pivotal-cf/kiln/internal/signing"
+	test_helpers "github.com/pivotal-cf/kiln/internal/test-helpers"
+)
+
+var _ = Describe("VerifySignature", func() {
+	var _ jhanda.Command = VerifySignature{}
+
+	var (
+		fs              billy.Filesystem
+		output          *gbytes.Buffer
+		verifySignature VerifySignature
+	)
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		output = gbytes.NewBuffer()
+		verifySignature = VerifySignature{
+			FS:     fs,
+			Logger: log.New(output, "", 0),
+		}
+
+		privateKey, publicKey, err := test_helpers.GenerateEd25519Keys()
+		Expect(err).NotTo(HaveOccurred())
+
+		signature, err := signing.Sign(privateKey, strings.NewReader("some-tile-contents"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(util.WriteFile(fs, "some-tile.pivotal", []byte("some-tile-contents"), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "some-tile.pivotal.sig", signature, 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "public-key.pem", publicKey, 0644)).To(Succeed())
+	})
+
+	It("verifies the signature next to the tile", func() {
+		err := verifySignature.Execute([]string{"--tile", "some-tile.pivotal", "--public-key", "public-key.pem"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output).To(gbytes.Say("The signature of some-tile.pivotal is valid"))
+	})
+
+	Context("when the signature path is given", func() {
+		It("verifies that signature", func() {
+			err := fs.Rename("some-tile.pivotal.sig", "signature")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = verifySignature.Execute([]string{"--tile", "some-tile.pivotal", "--public-key", "public-key.pem", "--signature", "signature"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when the tile has changed since it was signed", func() {
+		It("returns an error", func() {
+			Expect(util.WriteFile(fs, "some-tile.pivotal", []byte("some-other-tile-contents"), 0644)).To(Succeed())
+
+			err := verifySignature.Execute([]string{"--tile", "some-tile.pivotal", "--public-key", "public-key.pem"})
+			Expect(err).To(MatchError("could not verify the signature of some-tile.pivotal: signature does not match"))
+		})
+	})
+
+	Context("when the signature does not exist", func() {
+		It("returns an error", func() {
+			err := verifySignature.Execute([]string{"--tile", "some-tile.pivotal", "--public-key", "public-key.pem", "--signature", "missing.sig"})
+			Expect(err).To(MatchError(ContainSubstring("could not read signature")))
+		})
+	})
+})
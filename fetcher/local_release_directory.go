@@ -8,7 +8,6 @@ import (
 	"github.com/pivotal-cf/kiln/internal/baking"
 	release "github.com/pivotal-cf/kiln/release"
 	"gopkg.in/src-d/go-billy.v4"
-	"gopkg.in/src-d/go-billy.v4/osfs"
 	"io"
 	"log"
 	"os"
@@ -37,14 +36,9 @@ func (l LocalReleaseDirectory) GetLocalReleases(releasesDir string) ([]release.L
 	for _, rel := range rawReleases {
 		releaseManifest := rel.Metadata.(builder.ReleaseManifest)
 		id := release.ID{Name: releaseManifest.Name, Version: releaseManifest.Version}
-		localPath := rel.File
-		sha1, err := CalculateSum(localPath, osfs.New(""))
-
-		if err != nil {
-			return nil, fmt.Errorf("couldn't calculate SHA1 sum of %q: %w", localPath, err) // untested
-		}
-
-		outputReleases = append(outputReleases, release.Local{ID: id, LocalPath: localPath, SHA1: sha1})
+		// The releases service has already hashed the tarball, or found its
+		// SHA1 in the release cache, so it is not read again here.
+		outputReleases = append(outputReleases, release.Local{ID: id, LocalPath: rel.File, SHA1: releaseManifest.SHA1})
 	}
 	return outputReleases, nil
 }
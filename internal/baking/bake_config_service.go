@@ -0,0 +1,91 @@
+package baking
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/src-d/go-billy.v4"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// BakeConfig holds bake settings that are kept in a file rather than passed
+// as flags. Relative paths are resolved against the directory of the file.
+type BakeConfig struct {
+	OpsFiles []string      `yaml:"ops_files"`
+	Variants []BakeVariant `yaml:"variants"`
+}
+
+// BakeVariant is one tile built from the shared inputs of a bake, with its
+// own variables, ops files and subset of releases. Its variables files and
+// ops files are applied after the shared ones.
+type BakeVariant struct {
+	Name          string   `yaml:"name"`
+	OutputFile    string   `yaml:"output_file"`
+	VariableFiles []string `yaml:"variables_files"`
+	OpsFiles      []string `yaml:"ops_files"`
+	Releases      []string `yaml:"releases"`
+}
+
+type BakeConfigService struct {
+	filesystem billy.Filesystem
+}
+
+func NewBakeConfigService(fs billy.Filesystem) BakeConfigService {
+	return BakeConfigService{filesystem: fs}
+}
+
+func (s BakeConfigService) Read(path string) (BakeConfig, error) {
+	if path == "" {
+		return BakeConfig{}, nil
+	}
+
+	file, err := s.filesystem.Open(path)
+	if err != nil {
+		return BakeConfig{}, fmt.Errorf("unable to open bake config %q: %w", path, err)
+	}
+	defer file.Close()
+
+	var config BakeConfig
+	err = yaml.NewDecoder(file).Decode(&config)
+	if err != nil {
+		return BakeConfig{}, fmt.Errorf("unable to YAML parse bake config %q: %w", path, err)
+	}
+
+	resolveBakeConfigPaths(path, config.OpsFiles)
+
+	names := map[string]bool{}
+	for i, variant := range config.Variants {
+		if variant.Name == "" {
+			return BakeConfig{}, fmt.Errorf("variant %d in bake config %q does not have a name", i, path)
+		}
+
+		if names[variant.Name] {
+			return BakeConfig{}, fmt.Errorf("variant %q is defined more than once in bake config %q", variant.Name, path)
+		}
+		names[variant.Name] = true
+
+		if variant.OutputFile == "" {
+			return BakeConfig{}, fmt.Errorf("variant %q in bake config %q does not have an output_file", variant.Name, path)
+		}
+
+		config.Variants[i].OutputFile = resolveBakeConfigPath(path, variant.OutputFile)
+		resolveBakeConfigPaths(path, variant.VariableFiles)
+		resolveBakeConfigPaths(path, variant.OpsFiles)
+	}
+
+	return config, nil
+}
+
+func resolveBakeConfigPaths(configPath string, paths []string) {
+	for i, path := range paths {
+		paths[i] = resolveBakeConfigPath(configPath, path)
+	}
+}
+
+func resolveBakeConfigPath(configPath, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(configPath), path)
+}
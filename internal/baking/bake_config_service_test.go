@@ -0,0 +1,111 @@
+package baking_test
+
+import (
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	. "github.com/pivotal-cf/kiln/internal/baking"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BakeConfigService", func() {
+	Describe("Read", func() {
+		var (
+			filesystem billy.Filesystem
+			service    BakeConfigService
+		)
+
+		BeforeEach(func() {
+			filesystem = memfs.New()
+			service = NewBakeConfigService(filesystem)
+
+			Expect(util.WriteFile(filesystem, "config/bake.yml", []byte(`---
+ops_files:
+- ops/small-footprint.yml
+- /absolute/ops.yml
+`), 0644)).To(Succeed())
+		})
+
+		It("resolves ops files relative to the config file", func() {
+			config, err := service.Read("config/bake.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(BakeConfig{
+				OpsFiles: []string{"config/ops/small-footprint.yml", "/absolute/ops.yml"},
+			}))
+		})
+
+		It("reads variants and resolves their paths", func() {
+			Expect(util.WriteFile(filesystem, "config/variants.yml", []byte(`---
+variants:
+- name: full
+  output_file: tiles/full.pivotal
+- name: small
+  output_file: tiles/small.pivotal
+  variables_files: [small/variables.yml]
+  ops_files: [small/ops.yml]
+  releases: [some-release]
+`), 0644)).To(Succeed())
+
+			config, err := service.Read("config/variants.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Variants).To(Equal([]BakeVariant{
+				{
+					Name:       "full",
+					OutputFile: "config/tiles/full.pivotal",
+				},
+				{
+					Name:          "small",
+					OutputFile:    "config/tiles/small.pivotal",
+					VariableFiles: []string{"config/small/variables.yml"},
+					OpsFiles:      []string{"config/small/ops.yml"},
+					Releases:      []string{"some-release"},
+				},
+			}))
+		})
+
+		Context("when no path is given", func() {
+			It("returns an empty config", func() {
+				config, err := service.Read("")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config).To(Equal(BakeConfig{}))
+			})
+		})
+
+		Context("failure cases", func() {
+			Context("when the file does not exist", func() {
+				It("returns an error", func() {
+					_, err := service.Read("missing.yml")
+					Expect(err).To(MatchError(ContainSubstring(`unable to open bake config "missing.yml"`)))
+				})
+			})
+
+			DescribeTable("when a variant is invalid",
+				func(variants, message string) {
+					Expect(util.WriteFile(filesystem, "invalid.yml", []byte(variants), 0644)).To(Succeed())
+
+					_, err := service.Read("invalid.yml")
+					Expect(err).To(MatchError(message))
+				},
+				Entry("without a name", `variants: [{output_file: a.pivotal}]`,
+					`variant 0 in bake config "invalid.yml" does not have a name`),
+				Entry("without an output file", `variants: [{name: small}]`,
+					`variant "small" in bake config "invalid.yml" does not have an output_file`),
+				Entry("with a duplicate name", `variants: [{name: small, output_file: a.pivotal}, {name: small, output_file: b.pivotal}]`,
+					`variant "small" is defined more than once in bake config "invalid.yml"`),
+			)
+
+			Context("when the file is not valid YAML", func() {
+				It("returns an error", func() {
+					Expect(util.WriteFile(filesystem, "invalid.yml", []byte("\t\t\t"), 0644)).To(Succeed())
+
+					_, err := service.Read("invalid.yml")
+					Expect(err).To(MatchError(ContainSubstring(`unable to YAML parse bake config "invalid.yml"`)))
+				})
+			})
+		})
+	})
+})
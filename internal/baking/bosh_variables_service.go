@@ -12,22 +12,25 @@ func NewBOSHVariablesService(logger logger, reader directoryReader) BOSHVariable
 	}
 }
 
-func (s BOSHVariablesService) FromDirectories(directories []string) (map[string]interface{}, error) {
+func (s BOSHVariablesService) FromDirectories(directories []string, allowOverrides bool) (map[string]interface{}, error) {
 	if len(directories) == 0 {
 		return nil, nil
 	}
-	boshVariables := map[string]interface{}{}
+	boshVariables := newPartSet("BOSH variable", allowOverrides, s.logger)
 
-	for _, directory := range directories {
+	for i, directory := range directories {
 		directoryVariables, err := s.reader.Read(directory)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, boshVariable := range directoryVariables {
-			boshVariables[boshVariable.Name] = boshVariable.Metadata
+			err = boshVariables.add(i, boshVariable.Path, boshVariable)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	return boshVariables, nil
+	return boshVariables.parts, nil
 }
@@ -41,13 +41,16 @@ var _ = Describe("BOSHVariablesService", func() {
 		})
 
 		It("parses template variables from a collection of files", func() {
-			variables, err := service.FromDirectories([]string{"some-bosh-variables"})
+			variables, err := service.FromDirectories([]string{"some-bosh-variables"}, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(variables).To(Equal(map[string]interface{}{
-				"some-key": builder.Metadata{
-					"type": "user",
-					"options": map[string]interface{}{
-						"username": "some-username",
+				"some-key": builder.Part{
+					Name: "some-key",
+					Metadata: builder.Metadata{
+						"type": "user",
+						"options": map[string]interface{}{
+							"username": "some-username",
+						},
 					},
 				},
 			}))
@@ -56,11 +59,11 @@ var _ = Describe("BOSHVariablesService", func() {
 		Context("failure cases", func() {
 			Context("when the directories argument is empty", func() {
 				It("returns nothing", func() {
-					boshVariables, err := service.FromDirectories([]string{})
+					boshVariables, err := service.FromDirectories([]string{}, false)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(boshVariables).To(BeNil())
 
-					boshVariables, err = service.FromDirectories(nil)
+					boshVariables, err = service.FromDirectories(nil, false)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(boshVariables).To(BeNil())
 
@@ -74,7 +77,7 @@ var _ = Describe("BOSHVariablesService", func() {
 					It("returns an error", func() {
 						reader.ReadReturns(nil, errors.New("failed to read"))
 
-						_, err := service.FromDirectories([]string{"some-bosh-variables"})
+						_, err := service.FromDirectories([]string{"some-bosh-variables"}, false)
 						Expect(err).To(MatchError("failed to read"))
 					})
 				})
@@ -1,11 +1,22 @@
 package baking
 
 import (
-	"crypto/sha256"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/kiln/builder"
+)
+
+const (
+	// ChecksumFormatHex writes only the hex encoded digest.
+	ChecksumFormatHex = "hex"
+
+	// ChecksumFormatShasum writes the digest and file name in the format read
+	// by sha1sum, sha256sum, sha512sum and shasum with the -c flag.
+	ChecksumFormatShasum = "shasum"
 )
 
 type Checksummer struct {
@@ -16,31 +27,35 @@ func NewChecksummer(logger logger) Checksummer {
 	return Checksummer{logger: logger}
 }
 
-func (c Checksummer) Sum(path string) error {
-	c.logger.Println(fmt.Sprintf("Calculating SHA256 checksum of %s...", path))
-
-	hash := sha256.New()
-
-	file, err := os.Open(path)
-	if err != nil {
-		return err
+// Write writes each digest of the file at path to a file next to it named
+// after the algorithm, for example tile.pivotal.sha256.
+func (c Checksummer) Write(path string, digests builder.Digests, format string) error {
+	var algorithms []string
+	for algorithm := range digests {
+		algorithms = append(algorithms, algorithm)
 	}
-
-	defer file.Close()
-
-	_, err = io.Copy(hash, file)
-	if err != nil {
-		return err
-	}
-
-	hexsum := fmt.Sprintf("%x", hash.Sum(nil))
-
-	err = ioutil.WriteFile(fmt.Sprintf("%s.sha256", path), []byte(hexsum), 0644)
-	if err != nil {
-		return err
+	sort.Strings(algorithms)
+
+	for _, algorithm := range algorithms {
+		digest := digests[algorithm]
+
+		var contents string
+		switch format {
+		case ChecksumFormatHex:
+			contents = digest
+		case ChecksumFormatShasum:
+			contents = fmt.Sprintf("%s  %s\n", digest, filepath.Base(path))
+		default:
+			return fmt.Errorf("unsupported checksum format %q, expected %s or %s", format, ChecksumFormatHex, ChecksumFormatShasum)
+		}
+
+		err := ioutil.WriteFile(fmt.Sprintf("%s.%s", path, algorithm), []byte(contents), 0644)
+		if err != nil {
+			return err
+		}
+
+		c.logger.Println(fmt.Sprintf("%s checksum: %s", strings.ToUpper(algorithm), digest))
 	}
 
-	c.logger.Println(fmt.Sprintf("SHA256 checksum: %s", hexsum))
-
 	return nil
 }
@@ -2,11 +2,11 @@ package baking_test
 
 import (
 	"fmt"
-	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/pivotal-cf/kiln/builder"
 	. "github.com/pivotal-cf/kiln/internal/baking"
 	"github.com/pivotal-cf/kiln/internal/baking/fakes"
 
@@ -19,6 +19,8 @@ var _ = Describe("Checksummer", func() {
 		logger      *fakes.Logger
 		checksummer Checksummer
 		tmpdir      string
+		path        string
+		digests     builder.Digests
 	)
 
 	BeforeEach(func() {
@@ -29,17 +31,11 @@ var _ = Describe("Checksummer", func() {
 		tmpdir, err = ioutil.TempDir("", "")
 		Expect(err).NotTo(HaveOccurred())
 
-		file, err := os.Create(filepath.Join(tmpdir, "fixture"))
-		Expect(err).NotTo(HaveOccurred())
-
-		fixture, err := os.Open("fixtures/file.txt")
-		Expect(err).NotTo(HaveOccurred())
-
-		_, err = io.Copy(file, fixture)
-		Expect(err).NotTo(HaveOccurred())
-
-		Expect(file.Close()).To(Succeed())
-		Expect(fixture.Close()).To(Succeed())
+		path = filepath.Join(tmpdir, "tile.pivotal")
+		digests = builder.Digests{
+			"sha256": "2a89f69f18679fef3a1f833d1c5e561cc24ea02ce85b3fb7fae21dd971c9c9cd",
+			"sha1":   "some-sha1",
+		}
 	})
 
 	AfterEach(func() {
@@ -48,28 +44,35 @@ var _ = Describe("Checksummer", func() {
 		Expect(os.RemoveAll(tmpdir)).To(Succeed())
 	})
 
-	It("prints the sha256 checksum of the file at the given path", func() {
-		path := filepath.Join(tmpdir, "fixture")
-		err := checksummer.Sum(path)
+	It("prints each checksum", func() {
+		err := checksummer.Write(path, digests, ChecksumFormatHex)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(logger.PrintlnCallCount()).To(Equal(2))
+		Expect(logger.PrintlnArgsForCall(0)[0]).To(Equal("SHA1 checksum: some-sha1"))
+		Expect(logger.PrintlnArgsForCall(1)[0]).To(Equal("SHA256 checksum: 2a89f69f18679fef3a1f833d1c5e561cc24ea02ce85b3fb7fae21dd971c9c9cd"))
+	})
 
-		line := logger.PrintlnArgsForCall(0)[0]
-		Expect(line).To(Equal(fmt.Sprintf("Calculating SHA256 checksum of %s...", path)))
+	It("writes each checksum as bare hex to a file named after the algorithm next to the tile", func() {
+		err := checksummer.Write(path, digests, ChecksumFormatHex)
+		Expect(err).NotTo(HaveOccurred())
 
-		line = logger.PrintlnArgsForCall(1)[0]
-		Expect(line).To(Equal("SHA256 checksum: 2a89f69f18679fef3a1f833d1c5e561cc24ea02ce85b3fb7fae21dd971c9c9cd"))
+		contents, err := ioutil.ReadFile(fmt.Sprintf("%s.sha256", path))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("2a89f69f18679fef3a1f833d1c5e561cc24ea02ce85b3fb7fae21dd971c9c9cd"))
+
+		contents, err = ioutil.ReadFile(fmt.Sprintf("%s.sha1", path))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-sha1"))
 	})
 
-	It("writes the checksum to a .sha256 file in the same directory as the output file", func() {
-		path := filepath.Join(tmpdir, "fixture")
-		err := checksummer.Sum(path)
+	It("writes each checksum in the shasum format", func() {
+		err := checksummer.Write(path, digests, ChecksumFormatShasum)
 		Expect(err).NotTo(HaveOccurred())
 
 		contents, err := ioutil.ReadFile(fmt.Sprintf("%s.sha256", path))
 		Expect(err).NotTo(HaveOccurred())
-		Expect(string(contents)).To(Equal("2a89f69f18679fef3a1f833d1c5e561cc24ea02ce85b3fb7fae21dd971c9c9cd"))
+		Expect(string(contents)).To(Equal("2a89f69f18679fef3a1f833d1c5e561cc24ea02ce85b3fb7fae21dd971c9c9cd  tile.pivotal\n"))
 	})
 
 	Context("when the directory does not have write permissions", func() {
@@ -77,16 +80,15 @@ var _ = Describe("Checksummer", func() {
 			err := os.Chmod(tmpdir, 0544)
 			Expect(err).NotTo(HaveOccurred())
 
-			path := filepath.Join(tmpdir, "fixture")
-			err = checksummer.Sum(path)
-			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("open %s.sha256: permission denied", path))))
+			err = checksummer.Write(path, digests, ChecksumFormatHex)
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("open %s.sha1: permission denied", path))))
 		})
 	})
 
-	Context("when the file does not exist", func() {
+	Context("when the format is not supported", func() {
 		It("returns an error", func() {
-			err := checksummer.Sum("fixtures/does-not-exist.txt")
-			Expect(err).To(MatchError(ContainSubstring("open fixtures/does-not-exist.txt: no such file or directory")))
+			err := checksummer.Write(path, digests, "bsd")
+			Expect(err).To(MatchError(`unsupported checksum format "bsd", expected hex or shasum`))
 		})
 	})
 })
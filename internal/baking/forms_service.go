@@ -12,24 +12,27 @@ func NewFormsService(logger logger, reader directoryReader) FormsService {
 	}
 }
 
-func (fs FormsService) FromDirectories(directories []string) (map[string]interface{}, error) {
+func (fs FormsService) FromDirectories(directories []string, allowOverrides bool) (map[string]interface{}, error) {
 	if len(directories) == 0 {
 		return nil, nil
 	}
 
 	fs.logger.Println("Reading form files...")
 
-	forms := map[string]interface{}{}
-	for _, directory := range directories {
+	forms := newPartSet("form", allowOverrides, fs.logger)
+	for i, directory := range directories {
 		directoryForms, err := fs.reader.Read(directory)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, directoryForm := range directoryForms {
-			forms[directoryForm.Name] = directoryForm.Metadata
+			err = forms.add(i, directoryForm.Path, directoryForm)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	return forms, nil
+	return forms.parts, nil
 }
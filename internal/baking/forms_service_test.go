@@ -25,6 +25,7 @@ var _ = Describe("FormsService", func() {
 			reader.ReadReturnsOnCall(1, []builder.Part{
 				{
 					File: "some-form-file",
+					Path: "other-forms/some-form-file",
 					Name: "some-form-name",
 					Metadata: map[string]interface{}{
 						"some-key": "some-value",
@@ -36,12 +37,17 @@ var _ = Describe("FormsService", func() {
 		})
 
 		It("parses the forms passed in a set of directories", func() {
-			forms, err := service.FromDirectories([]string{"some-forms", "other-forms"})
+			forms, err := service.FromDirectories([]string{"some-forms", "other-forms"}, false)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(forms).To(Equal(map[string]interface{}{
-				"some-form-name": map[string]interface{}{
-					"some-key": "some-value",
+				"some-form-name": builder.Part{
+					File: "some-form-file",
+					Path: "other-forms/some-form-file",
+					Name: "some-form-name",
+					Metadata: map[string]interface{}{
+						"some-key": "some-value",
+					},
 				},
 			}))
 
@@ -53,13 +59,76 @@ var _ = Describe("FormsService", func() {
 			Expect(reader.ReadArgsForCall(1)).To(Equal("other-forms"))
 		})
 
+		Context("when a form name is defined in more than one directory", func() {
+			BeforeEach(func() {
+				reader.ReadReturnsOnCall(0, []builder.Part{
+					{
+						File:     "base-form-file",
+						Path:     "some-forms/base-form-file",
+						Name:     "some-form-name",
+						Metadata: map[string]interface{}{"some-key": "base-value"},
+					},
+				}, nil)
+			})
+
+			It("returns an error naming both files", func() {
+				_, err := service.FromDirectories([]string{"some-forms", "other-forms"}, false)
+				Expect(err).To(MatchError(`form "some-form-name" is defined in both "some-forms/base-form-file" and "other-forms/some-form-file"`))
+			})
+
+			Context("when overrides are allowed", func() {
+				It("uses the form from the later directory", func() {
+					forms, err := service.FromDirectories([]string{"some-forms", "other-forms"}, true)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(forms).To(Equal(map[string]interface{}{
+						"some-form-name": builder.Part{
+							File: "some-form-file",
+							Path: "other-forms/some-form-file",
+							Name: "some-form-name",
+							Metadata: map[string]interface{}{
+								"some-key": "some-value",
+							},
+						},
+					}))
+
+					Expect(logger.PrintlnCallCount()).To(Equal(2))
+					Expect(logger.PrintlnArgsForCall(1)).To(Equal([]interface{}{
+						`Overriding form "some-form-name" from "some-forms/base-form-file" with "other-forms/some-form-file"`,
+					}))
+				})
+			})
+		})
+
+		Context("when a form name is defined twice in the same directory", func() {
+			It("returns an error even when overrides are allowed", func() {
+				reader.ReadReturnsOnCall(0, []builder.Part{
+					{File: "some-form-file", Path: "some-forms/some-form-file", Name: "some-form-name"},
+					{File: "duplicate-form-file", Path: "some-forms/duplicate-form-file", Name: "some-form-name"},
+				}, nil)
+
+				_, err := service.FromDirectories([]string{"some-forms"}, true)
+				Expect(err).To(MatchError(`form "some-form-name" is defined in both "some-forms/some-form-file" and "some-forms/duplicate-form-file"`))
+			})
+
+			It("names the files in nested directories by their path", func() {
+				reader.ReadReturnsOnCall(0, []builder.Part{
+					{File: "some-form-file", Path: "some-forms/nested/some-form-file", Name: "some-form-name"},
+					{File: "some-form-file", Path: "some-forms/other-nested/some-form-file", Name: "some-form-name"},
+				}, nil)
+
+				_, err := service.FromDirectories([]string{"some-forms"}, true)
+				Expect(err).To(MatchError(`form "some-form-name" is defined in both "some-forms/nested/some-form-file" and "some-forms/other-nested/some-form-file"`))
+			})
+		})
+
 		Context("when there are no directories to parse", func() {
 			It("returns nothing", func() {
-				forms, err := service.FromDirectories([]string{})
+				forms, err := service.FromDirectories([]string{}, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(forms).To(BeNil())
 
-				forms, err = service.FromDirectories(nil)
+				forms, err = service.FromDirectories(nil, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(forms).To(BeNil())
 
@@ -73,7 +142,7 @@ var _ = Describe("FormsService", func() {
 				It("returns an error", func() {
 					reader.ReadReturns(nil, errors.New("failed to read"))
 
-					_, err := service.FromDirectories([]string{"some-forms"})
+					_, err := service.FromDirectories([]string{"some-forms"}, false)
 					Expect(err).To(MatchError("failed to read"))
 				})
 			})
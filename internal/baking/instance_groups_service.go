@@ -12,24 +12,27 @@ func NewInstanceGroupsService(logger logger, reader directoryReader) InstanceGro
 	}
 }
 
-func (igs InstanceGroupsService) FromDirectories(directories []string) (map[string]interface{}, error) {
+func (igs InstanceGroupsService) FromDirectories(directories []string, allowOverrides bool) (map[string]interface{}, error) {
 	if len(directories) == 0 {
 		return nil, nil
 	}
 
 	igs.logger.Println("Reading instance group files...")
 
-	instanceGroups := map[string]interface{}{}
-	for _, directory := range directories {
+	instanceGroups := newPartSet("instance group", allowOverrides, igs.logger)
+	for i, directory := range directories {
 		directoryInstanceGroups, err := igs.reader.Read(directory)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, instanceGroup := range directoryInstanceGroups {
-			instanceGroups[instanceGroup.Name] = instanceGroup.Metadata
+			err = instanceGroups.add(i, instanceGroup.Path, instanceGroup)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	return instanceGroups, nil
+	return instanceGroups.parts, nil
 }
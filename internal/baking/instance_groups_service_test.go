@@ -22,7 +22,7 @@ var _ = Describe("InstanceGroupsService", func() {
 		BeforeEach(func() {
 			logger = &fakes.Logger{}
 			reader = &fakes.DirectoryReader{}
-			reader.ReadReturns([]builder.Part{
+			reader.ReadReturnsOnCall(1, []builder.Part{
 				{
 					Name: "some-instance-group",
 					Metadata: builder.Metadata{
@@ -35,11 +35,14 @@ var _ = Describe("InstanceGroupsService", func() {
 		})
 
 		It("parses the instance groups passed in a set of directories", func() {
-			instanceGroups, err := service.FromDirectories([]string{"some-instance-groups", "other-instance-groups"})
+			instanceGroups, err := service.FromDirectories([]string{"some-instance-groups", "other-instance-groups"}, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(instanceGroups).To(Equal(map[string]interface{}{
-				"some-instance-group": builder.Metadata{
-					"key": "value",
+				"some-instance-group": builder.Part{
+					Name: "some-instance-group",
+					Metadata: builder.Metadata{
+						"key": "value",
+					},
 				},
 			}))
 
@@ -53,11 +56,11 @@ var _ = Describe("InstanceGroupsService", func() {
 
 		Context("when the directories argument is empty", func() {
 			It("returns nothing", func() {
-				instanceGroups, err := service.FromDirectories([]string{})
+				instanceGroups, err := service.FromDirectories([]string{}, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(instanceGroups).To(BeNil())
 
-				instanceGroups, err = service.FromDirectories(nil)
+				instanceGroups, err = service.FromDirectories(nil, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(instanceGroups).To(BeNil())
 
@@ -71,7 +74,7 @@ var _ = Describe("InstanceGroupsService", func() {
 				It("returns an error", func() {
 					reader.ReadReturns(nil, errors.New("failed to read"))
 
-					_, err := service.FromDirectories([]string{"some-instance-groups"})
+					_, err := service.FromDirectories([]string{"some-instance-groups"}, false)
 					Expect(err).To(MatchError("failed to read"))
 				})
 			})
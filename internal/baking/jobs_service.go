@@ -12,24 +12,27 @@ func NewJobsService(logger logger, reader directoryReader) JobsService {
 	}
 }
 
-func (js JobsService) FromDirectories(directories []string) (map[string]interface{}, error) {
+func (js JobsService) FromDirectories(directories []string, allowOverrides bool) (map[string]interface{}, error) {
 	if len(directories) == 0 {
 		return nil, nil
 	}
 
 	js.logger.Println("Reading jobs files...")
 
-	jobs := map[string]interface{}{}
-	for _, directory := range directories {
+	jobs := newPartSet("job", allowOverrides, js.logger)
+	for i, directory := range directories {
 		directoryJobs, err := js.reader.Read(directory)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, job := range directoryJobs {
-			jobs[job.Name] = job.Metadata
+			err = jobs.add(i, job.Path, job)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	return jobs, nil
+	return jobs.parts, nil
 }
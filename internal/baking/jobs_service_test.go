@@ -22,7 +22,7 @@ var _ = Describe("JobsService", func() {
 		BeforeEach(func() {
 			logger = &fakes.Logger{}
 			reader = &fakes.DirectoryReader{}
-			reader.ReadReturns([]builder.Part{
+			reader.ReadReturnsOnCall(1, []builder.Part{
 				{
 					Name: "some-job",
 					Metadata: builder.Metadata{
@@ -35,11 +35,14 @@ var _ = Describe("JobsService", func() {
 		})
 
 		It("parses the jobs passed in a set of directories", func() {
-			jobs, err := service.FromDirectories([]string{"some-jobs", "other-jobs"})
+			jobs, err := service.FromDirectories([]string{"some-jobs", "other-jobs"}, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(jobs).To(Equal(map[string]interface{}{
-				"some-job": builder.Metadata{
-					"key": "value",
+				"some-job": builder.Part{
+					Name: "some-job",
+					Metadata: builder.Metadata{
+						"key": "value",
+					},
 				},
 			}))
 
@@ -53,11 +56,11 @@ var _ = Describe("JobsService", func() {
 
 		Context("when the directories argument is empty", func() {
 			It("returns nothing", func() {
-				jobs, err := service.FromDirectories(nil)
+				jobs, err := service.FromDirectories(nil, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(jobs).To(BeNil())
 
-				jobs, err = service.FromDirectories([]string{})
+				jobs, err = service.FromDirectories([]string{}, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(jobs).To(BeNil())
 			})
@@ -68,7 +71,7 @@ var _ = Describe("JobsService", func() {
 				It("returns an error", func() {
 					reader.ReadReturns(nil, errors.New("failed to read"))
 
-					_, err := service.FromDirectories([]string{"some-jobs"})
+					_, err := service.FromDirectories([]string{"some-jobs"}, false)
 					Expect(err).To(MatchError("failed to read"))
 				})
 			})
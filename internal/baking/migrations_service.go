@@ -0,0 +1,45 @@
+package baking
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pivotal-cf/kiln/internal/migrations"
+	"github.com/pivotal-cf/kiln/internal/tile"
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+type MigrationsService struct {
+	filesystem billy.Filesystem
+}
+
+func NewMigrationsService(fs billy.Filesystem) MigrationsService {
+	return MigrationsService{filesystem: fs}
+}
+
+// Validate checks the names of the migrations in the directories, and when
+// previousTile is given, that they keep the order of the migrations it
+// shipped.
+func (s MigrationsService) Validate(directories []string, previousTile string) error {
+	loaded, err := migrations.Load(s.filesystem, directories)
+	if err != nil {
+		return err
+	}
+
+	var shipped []string
+	if previousTile != "" {
+		names, err := tile.FileNames(s.filesystem, previousTile)
+		if err != nil {
+			return fmt.Errorf("could not read previous tile: %w", err)
+		}
+
+		for _, name := range names {
+			if path.Dir(name) == migrations.TileDirectory && strings.HasSuffix(name, ".js") {
+				shipped = append(shipped, path.Base(name))
+			}
+		}
+	}
+
+	return migrations.Validate(loaded, shipped)
+}
@@ -0,0 +1,69 @@
+package baking_test
+
+import (
+	"archive/zip"
+
+	. "github.com/pivotal-cf/kiln/internal/baking"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+)
+
+var _ = Describe("MigrationsService", func() {
+	var (
+		fs      billy.Filesystem
+		service MigrationsService
+	)
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		service = NewMigrationsService(fs)
+
+		Expect(util.WriteFile(fs, "migrations/201711131111_first.js", []byte(""), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "migrations/201801010000_second.js", []byte(""), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "migrations/tests/migration_test.js", []byte(""), 0644)).To(Succeed())
+	})
+
+	It("validates the migrations in the directories", func() {
+		Expect(service.Validate([]string{"migrations"}, "")).To(Succeed())
+
+		Expect(util.WriteFile(fs, "other-migrations/201801010000_second.js", []byte(""), 0644)).To(Succeed())
+
+		err := service.Validate([]string{"migrations", "other-migrations"}, "")
+		Expect(err).To(MatchError(ContainSubstring("are both packaged as migrations/v1/201801010000_second.js")))
+	})
+
+	Context("when a previous tile is given", func() {
+		BeforeEach(func() {
+			f, err := fs.Create("previous.pivotal")
+			Expect(err).NotTo(HaveOccurred())
+
+			zw := zip.NewWriter(f)
+			for _, name := range []string{"metadata/metadata.yml", "migrations/v1/201711131111_first.js", "migrations/v1/201801010000_second.js"} {
+				_, err := zw.Create(name)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(zw.Close()).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+		})
+
+		It("reports migrations that sort before the ones it shipped", func() {
+			Expect(service.Validate([]string{"migrations"}, "previous.pivotal")).To(Succeed())
+
+			Expect(util.WriteFile(fs, "migrations/201712010000_late.js", []byte(""), 0644)).To(Succeed())
+
+			err := service.Validate([]string{"migrations"}, "previous.pivotal")
+			Expect(err).To(MatchError("migrations/201712010000_late.js sorts before 201801010000_second.js, which shipped in the previous tile"))
+		})
+
+		Context("when the previous tile does not exist", func() {
+			It("returns an error", func() {
+				err := service.Validate([]string{"migrations"}, "missing.pivotal")
+				Expect(err).To(MatchError(ContainSubstring("could not read previous tile")))
+			})
+		})
+	})
+})
@@ -0,0 +1,72 @@
+package baking
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/src-d/go-billy.v4"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type OpsFilesService struct {
+	filesystem billy.Filesystem
+}
+
+func NewOpsFilesService(fs billy.Filesystem) OpsFilesService {
+	return OpsFilesService{filesystem: fs}
+}
+
+// Apply patches the metadata with each ops file in the order given. Ops files
+// use the go-patch syntax of the BOSH CLI.
+func (s OpsFilesService) Apply(paths []string, metadata []byte) ([]byte, error) {
+	if len(paths) == 0 {
+		return metadata, nil
+	}
+
+	var document interface{}
+	err := yaml.Unmarshal(metadata, &document)
+	if err != nil {
+		return nil, fmt.Errorf("unable to YAML parse metadata: %w", err)
+	}
+
+	for _, path := range paths {
+		ops, err := s.read(path)
+		if err != nil {
+			return nil, err
+		}
+
+		document, err = ops.Apply(document)
+		if err != nil {
+			return nil, fmt.Errorf("unable to apply ops file %q: %w", path, err)
+		}
+	}
+
+	return yaml.Marshal(document)
+}
+
+func (s OpsFilesService) read(path string) (patch.Ops, error) {
+	file, err := s.filesystem.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open ops file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	contents, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ops file %q: %w", path, err)
+	}
+
+	var definitions []patch.OpDefinition
+	err = yaml.Unmarshal(contents, &definitions)
+	if err != nil {
+		return nil, fmt.Errorf("unable to YAML parse ops file %q: %w", path, err)
+	}
+
+	ops, err := patch.NewOpsFromDefinitions(definitions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ops file %q: %w", path, err)
+	}
+
+	return ops, nil
+}
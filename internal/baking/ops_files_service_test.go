@@ -0,0 +1,100 @@
+package baking_test
+
+import (
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	. "github.com/pivotal-cf/kiln/internal/baking"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf-experimental/gomegamatchers"
+)
+
+var _ = Describe("OpsFilesService", func() {
+	Describe("Apply", func() {
+		var (
+			filesystem billy.Filesystem
+			service    OpsFilesService
+			metadata   []byte
+		)
+
+		BeforeEach(func() {
+			filesystem = memfs.New()
+			service = NewOpsFilesService(filesystem)
+
+			metadata = []byte(`---
+name: some-product
+job_types:
+- name: web
+  instance_definition:
+    default: 3
+- name: worker
+  instance_definition:
+    default: 2
+`)
+
+			Expect(util.WriteFile(filesystem, "small-footprint.yml", []byte(`---
+- type: replace
+  path: /job_types/name=web/instance_definition/default
+  value: 1
+- type: remove
+  path: /job_types/name=worker
+`), 0644)).To(Succeed())
+
+			Expect(util.WriteFile(filesystem, "rename.yml", []byte(`---
+- type: replace
+  path: /name
+  value: some-small-product
+`), 0644)).To(Succeed())
+		})
+
+		It("applies each ops file in order", func() {
+			patched, err := service.Apply([]string{"small-footprint.yml", "rename.yml"}, metadata)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patched).To(HelpfullyMatchYAML(`---
+name: some-small-product
+job_types:
+- name: web
+  instance_definition:
+    default: 1
+`))
+		})
+
+		Context("when there are no ops files", func() {
+			It("returns the metadata unchanged", func() {
+				patched, err := service.Apply(nil, metadata)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(patched).To(Equal(metadata))
+			})
+		})
+
+		Context("failure cases", func() {
+			Context("when the ops file does not exist", func() {
+				It("returns an error", func() {
+					_, err := service.Apply([]string{"missing.yml"}, metadata)
+					Expect(err).To(MatchError(ContainSubstring(`unable to open ops file "missing.yml"`)))
+				})
+			})
+
+			Context("when the ops file is not valid", func() {
+				It("returns an error", func() {
+					Expect(util.WriteFile(filesystem, "invalid.yml", []byte(`[{type: unknown, path: /name}]`), 0644)).To(Succeed())
+
+					_, err := service.Apply([]string{"invalid.yml"}, metadata)
+					Expect(err).To(MatchError(ContainSubstring(`invalid ops file "invalid.yml": Unknown operation [0] with type 'unknown'`)))
+				})
+			})
+
+			Context("when an operation does not apply to the metadata", func() {
+				It("returns an error naming the ops file", func() {
+					Expect(util.WriteFile(filesystem, "missing-path.yml", []byte(`[{type: replace, path: /missing/key, value: 1}]`), 0644)).To(Succeed())
+
+					_, err := service.Apply([]string{"rename.yml", "missing-path.yml"}, metadata)
+					Expect(err).To(MatchError(ContainSubstring(`unable to apply ops file "missing-path.yml": Expected to find a map key 'missing'`)))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,38 @@
+package baking
+
+type PartialsService struct {
+	logger logger
+	reader directoryReader
+}
+
+func NewPartialsService(logger logger, reader directoryReader) PartialsService {
+	return PartialsService{
+		logger: logger,
+		reader: reader,
+	}
+}
+
+func (ps PartialsService) FromDirectories(directories []string, allowOverrides bool) (map[string]interface{}, error) {
+	if len(directories) == 0 {
+		return nil, nil
+	}
+
+	ps.logger.Println("Reading partial files...")
+
+	partials := newPartSet("partial", allowOverrides, ps.logger)
+	for i, directory := range directories {
+		directoryPartials, err := ps.reader.Read(directory)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, partial := range directoryPartials {
+			err = partials.add(i, partial.Path, partial)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return partials.parts, nil
+}
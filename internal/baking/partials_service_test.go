@@ -0,0 +1,95 @@
+package baking_test
+
+import (
+	"errors"
+
+	"github.com/pivotal-cf/kiln/builder"
+	. "github.com/pivotal-cf/kiln/internal/baking"
+	"github.com/pivotal-cf/kiln/internal/baking/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PartialsService", func() {
+	Describe("FromDirectories", func() {
+		var (
+			logger  *fakes.Logger
+			reader  *fakes.DirectoryReader
+			service PartialsService
+		)
+
+		BeforeEach(func() {
+			logger = &fakes.Logger{}
+			reader = &fakes.DirectoryReader{}
+			reader.ReadReturnsOnCall(1, []builder.Part{
+				{
+					Name: "some-partial",
+					Metadata: builder.Metadata{
+						"key": "value",
+					},
+				},
+			}, nil)
+
+			service = NewPartialsService(logger, reader)
+		})
+
+		It("parses the partials passed in a set of directories", func() {
+			partials, err := service.FromDirectories([]string{"some-partials", "other-partials"}, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(Equal(map[string]interface{}{
+				"some-partial": builder.Part{
+					Name: "some-partial",
+					Metadata: builder.Metadata{
+						"key": "value",
+					},
+				},
+			}))
+
+			Expect(logger.PrintlnCallCount()).To(Equal(1))
+			Expect(logger.PrintlnArgsForCall(0)).To(Equal([]interface{}{"Reading partial files..."}))
+
+			Expect(reader.ReadCallCount()).To(Equal(2))
+			Expect(reader.ReadArgsForCall(0)).To(Equal("some-partials"))
+			Expect(reader.ReadArgsForCall(1)).To(Equal("other-partials"))
+		})
+
+		Context("when a partial name is defined twice in nested directories", func() {
+			It("returns an error naming both files by their path", func() {
+				reader.ReadReturnsOnCall(0, []builder.Part{
+					{File: "some-partial.yml", Path: "some-partials/nested/some-partial.yml", Name: "some-partial"},
+					{File: "some-partial.yml", Path: "some-partials/other-nested/some-partial.yml", Name: "some-partial"},
+				}, nil)
+
+				_, err := service.FromDirectories([]string{"some-partials"}, true)
+				Expect(err).To(MatchError(`partial "some-partial" is defined in both "some-partials/nested/some-partial.yml" and "some-partials/other-nested/some-partial.yml"`))
+			})
+		})
+
+		Context("when the directories argument is empty", func() {
+			It("returns nothing", func() {
+				partials, err := service.FromDirectories(nil, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(partials).To(BeNil())
+
+				partials, err = service.FromDirectories([]string{}, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(partials).To(BeNil())
+
+				Expect(logger.PrintlnCallCount()).To(Equal(0))
+				Expect(reader.ReadCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("failure cases", func() {
+			Context("when the reader fails", func() {
+				It("returns an error", func() {
+					reader.ReadReturns(nil, errors.New("failed to read"))
+
+					_, err := service.FromDirectories([]string{"some-partials"}, false)
+					Expect(err).To(MatchError("failed to read"))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,48 @@
+package baking
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/kiln/builder"
+)
+
+type partSource struct {
+	directory int
+	path      string
+}
+
+// partSet collects parts by name across an ordered list of directories. A
+// name defined twice is an error that names both files, unless overrides are
+// allowed and the later definition comes from a later directory.
+type partSet struct {
+	kind           string
+	allowOverrides bool
+	logger         logger
+	parts          map[string]interface{}
+	sources        map[string]partSource
+}
+
+func newPartSet(kind string, allowOverrides bool, logger logger) *partSet {
+	return &partSet{
+		kind:           kind,
+		allowOverrides: allowOverrides,
+		logger:         logger,
+		parts:          map[string]interface{}{},
+		sources:        map[string]partSource{},
+	}
+}
+
+func (ps *partSet) add(directory int, path string, part builder.Part) error {
+	if previous, ok := ps.sources[part.Name]; ok {
+		if !ps.allowOverrides || previous.directory == directory {
+			return fmt.Errorf("%s %q is defined in both %q and %q", ps.kind, part.Name, previous.path, path)
+		}
+
+		ps.logger.Println(fmt.Sprintf("Overriding %s %q from %q with %q", ps.kind, part.Name, previous.path, path))
+	}
+
+	ps.parts[part.Name] = part
+	ps.sources[part.Name] = partSource{directory: directory, path: path}
+
+	return nil
+}
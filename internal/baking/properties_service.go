@@ -12,24 +12,27 @@ func NewPropertiesService(logger logger, reader directoryReader) PropertiesServi
 	}
 }
 
-func (ps PropertiesService) FromDirectories(directories []string) (map[string]interface{}, error) {
+func (ps PropertiesService) FromDirectories(directories []string, allowOverrides bool) (map[string]interface{}, error) {
 	if len(directories) == 0 {
 		return nil, nil
 	}
 
 	ps.logger.Println("Reading property blueprint files...")
 
-	properties := map[string]interface{}{}
-	for _, directory := range directories {
+	properties := newPartSet("property blueprint", allowOverrides, ps.logger)
+	for i, directory := range directories {
 		directoryProperties, err := ps.reader.Read(directory)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, property := range directoryProperties {
-			properties[property.Name] = property.Metadata
+			err = properties.add(i, property.Path, property)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	return properties, nil
+	return properties.parts, nil
 }
@@ -22,7 +22,7 @@ var _ = Describe("PropertiesService", func() {
 		BeforeEach(func() {
 			logger = &fakes.Logger{}
 			reader = &fakes.DirectoryReader{}
-			reader.ReadReturns([]builder.Part{
+			reader.ReadReturnsOnCall(1, []builder.Part{
 				{
 					Name: "some-property",
 					Metadata: builder.Metadata{
@@ -35,11 +35,14 @@ var _ = Describe("PropertiesService", func() {
 		})
 
 		It("parses the properties passed in a set of directories", func() {
-			properties, err := service.FromDirectories([]string{"some-properties", "other-properties"})
+			properties, err := service.FromDirectories([]string{"some-properties", "other-properties"}, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(properties).To(Equal(map[string]interface{}{
-				"some-property": builder.Metadata{
-					"key": "value",
+				"some-property": builder.Part{
+					Name: "some-property",
+					Metadata: builder.Metadata{
+						"key": "value",
+					},
 				},
 			}))
 
@@ -53,11 +56,11 @@ var _ = Describe("PropertiesService", func() {
 
 		Context("when the directories argument is empty", func() {
 			It("returns nothing", func() {
-				properties, err := service.FromDirectories(nil)
+				properties, err := service.FromDirectories(nil, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(properties).To(BeNil())
 
-				properties, err = service.FromDirectories([]string{})
+				properties, err = service.FromDirectories([]string{}, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(properties).To(BeNil())
 			})
@@ -68,7 +71,7 @@ var _ = Describe("PropertiesService", func() {
 				It("returns an error", func() {
 					reader.ReadReturns(nil, errors.New("failed to read"))
 
-					_, err := service.FromDirectories([]string{"some-properties"})
+					_, err := service.FromDirectories([]string{"some-properties"}, false)
 					Expect(err).To(MatchError("failed to read"))
 				})
 			})
@@ -0,0 +1,260 @@
+package baking
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+// ProvenanceFile is the path of the provenance document in a baked tile.
+const ProvenanceFile = "provenance/provenance.json"
+
+// redacted replaces the value of every variable in the provenance document.
+const redacted = "(redacted)"
+
+// ProvenanceInput describes how a tile is baked.
+type ProvenanceInput struct {
+	Variant             string
+	Arguments           []string
+	Variables           map[string]interface{}
+	Kilnfile            string
+	RepositoryDirectory string
+
+	// Files are the paths of the input files and directories, every file in a
+	// directory is hashed.
+	Files []string
+}
+
+type provenance struct {
+	KilnVersion  string           `json:"kiln_version"`
+	BuiltAt      string           `json:"built_at"`
+	Variant      string           `json:"variant,omitempty"`
+	Git          *provenanceGit   `json:"git,omitempty"`
+	KilnfileLock *provenanceFile  `json:"kilnfile_lock,omitempty"`
+	Arguments    []string         `json:"arguments"`
+	Variables    []string         `json:"variables"`
+	Files        []provenanceFile `json:"files"`
+}
+
+type provenanceGit struct {
+	Commit string `json:"commit"`
+	Dirty  bool   `json:"dirty"`
+}
+
+type provenanceFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+type ProvenanceService struct {
+	filesystem  billy.Filesystem
+	kilnVersion string
+	now         func() time.Time
+}
+
+func NewProvenanceService(fs billy.Filesystem, kilnVersion string) ProvenanceService {
+	return ProvenanceService{
+		filesystem:  fs,
+		kilnVersion: kilnVersion,
+		now:         time.Now,
+	}
+}
+
+// Generate returns a JSON document recording the kiln version, the git commit
+// of the tile repository, a hash of the Kilnfile.lock and of every input
+// file, and the arguments of the bake and the names of its variables. The
+// provenance ships inside the tile, so nothing derived from a variable value,
+// not even a hash, is recorded.
+func (s ProvenanceService) Generate(input ProvenanceInput) ([]byte, error) {
+	document := provenance{
+		KilnVersion: s.kilnVersion,
+		BuiltAt:     s.now().UTC().Format(time.RFC3339),
+		Variant:     input.Variant,
+		Git:         gitState(input.RepositoryDirectory),
+		Arguments:   redactArguments(input.Arguments),
+		Variables:   variableNames(input.Variables),
+		Files:       []provenanceFile{},
+	}
+
+	if input.Kilnfile != "" {
+		lock, err := s.hashFile(input.Kilnfile + ".lock")
+		if err != nil {
+			return nil, err
+		}
+		document.KilnfileLock = &lock
+	}
+
+	seen := map[string]bool{}
+	for _, path := range input.Files {
+		paths, err := s.files(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range paths {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+
+			file, err := s.hashFile(p)
+			if err != nil {
+				return nil, err
+			}
+			document.Files = append(document.Files, file)
+		}
+	}
+
+	sort.Slice(document.Files, func(i, j int) bool {
+		return document.Files[i].Path < document.Files[j].Path
+	})
+
+	contents, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode provenance: %w", err)
+	}
+
+	return append(contents, '\n'), nil
+}
+
+func (s ProvenanceService) files(path string) ([]string, error) {
+	info, err := s.filesystem.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %q: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	infos, err := s.filesystem.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %q: %w", path, err)
+	}
+
+	var paths []string
+	for _, info := range infos {
+		nested, err := s.files(filepath.Join(path, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, nested...)
+	}
+
+	return paths, nil
+}
+
+func (s ProvenanceService) hashFile(path string) (provenanceFile, error) {
+	f, err := s.filesystem.Open(path)
+	if err != nil {
+		return provenanceFile{}, fmt.Errorf("unable to read %q: %w", path, err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return provenanceFile{}, fmt.Errorf("unable to read %q: %w", path, err)
+	}
+
+	return provenanceFile{Path: path, SHA256: fmt.Sprintf("%x", hash.Sum(nil))}, nil
+}
+
+// gitState returns nil when directory is not in a git repository or git is
+// not installed.
+func gitState(directory string) *provenanceGit {
+	if directory == "" {
+		directory = "."
+	}
+
+	commit, err := exec.Command("git", "-C", directory, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return nil
+	}
+
+	status, err := exec.Command("git", "-C", directory, "status", "--porcelain").Output()
+	if err != nil {
+		return nil
+	}
+
+	return &provenanceGit{
+		Commit: strings.TrimSpace(string(commit)),
+		Dirty:  len(strings.TrimSpace(string(status))) > 0,
+	}
+}
+
+// redactArguments replaces the values of --variable and --variable-yaml
+// arguments with a fixed marker.
+func redactArguments(args []string) []string {
+	redactedArgs := make([]string, len(args))
+	copy(redactedArgs, args)
+
+	for i, arg := range redactedArgs {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) == 2 && isVariableFlag(parts[0]) {
+			redactedArgs[i] = parts[0] + "=" + redactPair(parts[1])
+			continue
+		}
+
+		if isVariableFlag(arg) && i+1 < len(redactedArgs) {
+			redactedArgs[i+1] = redactPair(redactedArgs[i+1])
+		}
+	}
+
+	return redactedArgs
+}
+
+func isVariableFlag(arg string) bool {
+	switch arg {
+	case "--variable", "-vr", "--variable-yaml", "-vy":
+		return true
+	default:
+		return false
+	}
+}
+
+func redactPair(pair string) string {
+	parts := strings.SplitN(pair, "=", 2)
+	if len(parts) < 2 {
+		return pair
+	}
+
+	return parts[0] + "=" + redacted
+}
+
+// variableNames returns the sorted dotted name of every leaf value, for
+// example "network.name".
+func variableNames(variables map[string]interface{}) []string {
+	names := []string{}
+	for key, value := range variables {
+		names = addVariableNames(names, key, value)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func addVariableNames(names []string, name string, value interface{}) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			names = addVariableNames(names, name+"."+key, value)
+		}
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			names = addVariableNames(names, fmt.Sprintf("%s.%v", name, key), value)
+		}
+	default:
+		names = append(names, name)
+	}
+
+	return names
+}
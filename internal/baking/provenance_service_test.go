@@ -0,0 +1,169 @@
+package baking_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/pivotal-cf/kiln/internal/baking"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+)
+
+var _ = Describe("ProvenanceService", func() {
+	type provenance struct {
+		KilnVersion string `json:"kiln_version"`
+		BuiltAt     string `json:"built_at"`
+		Variant     string `json:"variant"`
+		Git         *struct {
+			Commit string `json:"commit"`
+			Dirty  bool   `json:"dirty"`
+		} `json:"git"`
+		KilnfileLock *struct {
+			Path   string `json:"path"`
+			SHA256 string `json:"sha256"`
+		} `json:"kilnfile_lock"`
+		Arguments []string `json:"arguments"`
+		Variables []string `json:"variables"`
+		Files     []struct {
+			Path   string `json:"path"`
+			SHA256 string `json:"sha256"`
+		} `json:"files"`
+	}
+
+	var (
+		fs      billy.Filesystem
+		service ProvenanceService
+		tmpdir  string
+	)
+
+	generate := func(input ProvenanceInput) provenance {
+		contents, err := service.Generate(input)
+		Expect(err).NotTo(HaveOccurred())
+
+		var document provenance
+		Expect(json.Unmarshal(contents, &document)).To(Succeed())
+		return document
+	}
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		service = NewProvenanceService(fs, "0.15.0")
+
+		var err error
+		tmpdir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(util.WriteFile(fs, "base.yml", []byte("some-contents"), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "forms/nested/form.yml", []byte("some-form"), 0644)).To(Succeed())
+		Expect(util.WriteFile(fs, "Kilnfile.lock", []byte("some-lock"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpdir)).To(Succeed())
+	})
+
+	It("records the kiln version, Kilnfile.lock and every input file", func() {
+		document := generate(ProvenanceInput{
+			Variant:             "some-variant",
+			Kilnfile:            "Kilnfile",
+			Files:               []string{"base.yml", "forms", "base.yml"},
+			RepositoryDirectory: tmpdir,
+		})
+
+		Expect(document.KilnVersion).To(Equal("0.15.0"))
+		Expect(document.BuiltAt).NotTo(BeEmpty())
+		Expect(document.Variant).To(Equal("some-variant"))
+		Expect(document.Git).To(BeNil())
+		Expect(document.KilnfileLock.Path).To(Equal("Kilnfile.lock"))
+
+		Expect(document.Files).To(HaveLen(2))
+		Expect(document.Files[0].Path).To(Equal("base.yml"))
+		// echo -n some-contents | sha256sum
+		Expect(document.Files[0].SHA256).To(Equal("6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"))
+		Expect(document.Files[1].Path).To(Equal(filepath.Join("forms", "nested", "form.yml")))
+	})
+
+	It("records variables by name without anything derived from their values", func() {
+		contents, err := service.Generate(ProvenanceInput{
+			Arguments: []string{
+				"--metadata", "base.yml",
+				"--variable", "admin_password=hunter2",
+				"--variable", "server_key=some-server-key",
+				"--variable-yaml=cert_pem=some-cert",
+			},
+			Variables: map[string]interface{}{
+				"admin_password": "hunter2",
+				"server_key":     "some-server-key",
+				"github":         map[interface{}]interface{}{"access": "some-github-access"},
+				"instances":      3,
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		secrets := []string{"hunter2", "some-server-key", "some-cert", "some-github-access"}
+		for _, secret := range secrets {
+			Expect(string(contents)).NotTo(ContainSubstring(secret))
+		}
+
+		for _, value := range append(secrets, "3", "3\n") {
+			sum := sha256.Sum256([]byte(value))
+			Expect(string(contents)).NotTo(ContainSubstring(fmt.Sprintf("%x", sum)))
+			Expect(string(contents)).NotTo(ContainSubstring(base64.StdEncoding.EncodeToString(sum[:])))
+		}
+
+		var document provenance
+		Expect(json.Unmarshal(contents, &document)).To(Succeed())
+
+		Expect(document.Arguments).To(Equal([]string{
+			"--metadata", "base.yml",
+			"--variable", "admin_password=(redacted)",
+			"--variable", "server_key=(redacted)",
+			"--variable-yaml=cert_pem=(redacted)",
+		}))
+		Expect(document.Variables).To(Equal([]string{
+			"admin_password",
+			"github.access",
+			"instances",
+			"server_key",
+		}))
+	})
+
+	Context("when the tile repository is a git repository", func() {
+		It("records the commit and whether the working tree is dirty", func() {
+			for _, args := range [][]string{
+				{"init", "--quiet"},
+				{"-c", "user.name=kiln", "-c", "user.email=kiln@example.com", "commit", "--quiet", "--allow-empty", "--message", "initial"},
+			} {
+				command := exec.Command("git", args...)
+				command.Dir = tmpdir
+				Expect(command.Run()).To(Succeed())
+			}
+
+			document := generate(ProvenanceInput{RepositoryDirectory: tmpdir})
+			Expect(document.Git.Commit).To(HaveLen(40))
+			Expect(document.Git.Dirty).To(BeFalse())
+
+			Expect(ioutil.WriteFile(filepath.Join(tmpdir, "base.yml"), []byte("some-contents"), 0644)).To(Succeed())
+
+			document = generate(ProvenanceInput{RepositoryDirectory: tmpdir})
+			Expect(document.Git.Dirty).To(BeTrue())
+		})
+	})
+
+	Context("when an input file does not exist", func() {
+		It("returns an error", func() {
+			_, err := service.Generate(ProvenanceInput{Files: []string{"missing.yml"}})
+			Expect(err).To(MatchError(ContainSubstring(`unable to read "missing.yml"`)))
+		})
+	})
+})
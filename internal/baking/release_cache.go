@@ -0,0 +1,89 @@
+package baking
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/kiln/builder"
+)
+
+// ReleaseCacheFile is the name of the file in each releases directory that
+// caches the manifest, SHA1 and SHA256 of every release tarball in that
+// directory.
+const ReleaseCacheFile = ".kiln-releases-cache.json"
+
+const releaseCacheVersion = 2
+
+type releaseCache struct {
+	Version  int                          `json:"version"`
+	Releases map[string]releaseCacheEntry `json:"releases"`
+}
+
+// releaseCacheEntry is valid for as long as the tarball keeps the same size
+// and modification time.
+type releaseCacheEntry struct {
+	Size     int64                   `json:"size"`
+	ModTime  int64                   `json:"mod_time"`
+	Manifest builder.ReleaseManifest `json:"manifest"`
+}
+
+func releaseCachePath(directory string) string {
+	return filepath.Join(directory, ReleaseCacheFile)
+}
+
+// loadReleaseCache returns an empty cache when the cache file is missing,
+// unreadable or written by a different version of kiln.
+func loadReleaseCache(directory string) releaseCache {
+	empty := releaseCache{Version: releaseCacheVersion, Releases: map[string]releaseCacheEntry{}}
+
+	contents, err := ioutil.ReadFile(releaseCachePath(directory))
+	if err != nil {
+		return empty
+	}
+
+	var cache releaseCache
+	err = json.Unmarshal(contents, &cache)
+	if err != nil || cache.Version != releaseCacheVersion || cache.Releases == nil {
+		return empty
+	}
+
+	return cache
+}
+
+func (c releaseCache) lookup(key string, info os.FileInfo) (builder.ReleaseManifest, bool) {
+	entry, ok := c.Releases[key]
+	if !ok || entry.Size != info.Size() || entry.ModTime != info.ModTime().UnixNano() {
+		return builder.ReleaseManifest{}, false
+	}
+
+	return entry.Manifest, true
+}
+
+func (c releaseCache) write(directory string) error {
+	contents, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err // should never happen
+	}
+
+	file, err := ioutil.TempFile(directory, ReleaseCacheFile)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(contents)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// TempFile creates files readable only by their owner.
+		err = os.Chmod(file.Name(), 0644)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), releaseCachePath(directory))
+}
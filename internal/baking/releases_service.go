@@ -1,10 +1,14 @@
 package baking
 
 import (
-	"github.com/pivotal-cf/kiln/builder"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"sync"
+
+	"github.com/pivotal-cf/kiln/builder"
 )
 
 type ReleasesService struct {
@@ -19,37 +23,44 @@ func NewReleasesService(logger logger, reader partReader) ReleasesService {
 	}
 }
 
-func (s ReleasesService) FromDirectories(directories []string) (map[string]interface{}, error) {
+func (s ReleasesService) FromDirectories(directories []string, allowOverrides bool) (map[string]interface{}, error) {
 	s.logger.Println("Reading release manifests...")
 
-	var releases []builder.Part
-	for _, directory := range directories {
-		newReleases, err := s.ReleasesInDirectory(directory)
+	manifests := newPartSet("release", allowOverrides, s.logger)
+	for i, directory := range directories {
+		releases, err := s.ReleasesInDirectory(directory)
 		if err != nil {
 			return nil, err
 		}
 
-		releases = append(releases, newReleases...)
-	}
-
-	manifests := map[string]interface{}{}
-	for _, rel := range releases {
-		manifests[rel.Name] = rel.Metadata
+		for _, rel := range releases {
+			err = manifests.add(i, rel.File, rel)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	return manifests, nil
+	return manifests.parts, nil
 }
 
+// ReleasesInDirectory reads the release tarballs in a directory concurrently.
+// Tarballs whose size and modification time match the cache file in the
+// directory are not read at all.
 func (s ReleasesService) ReleasesInDirectory(directoryPath string) ([]builder.Part, error) {
-	var tarballPaths []string
+	var (
+		tarballPaths []string
+		tarballInfos []os.FileInfo
+	)
 
-	err := filepath.Walk(directoryPath, func(path string, _ os.FileInfo, err error) error {
+	err := filepath.Walk(directoryPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
 		if match, _ := regexp.MatchString("tgz$|tar.gz$", path); match {
 			tarballPaths = append(tarballPaths, path)
+			tarballInfos = append(tarballInfos, info)
 		}
 
 		return nil
@@ -59,15 +70,84 @@ func (s ReleasesService) ReleasesInDirectory(directoryPath string) ([]builder.Pa
 		return nil, err
 	}
 
-	var releases []builder.Part
-	for _, tarballPath := range tarballPaths {
-		rel, err := s.reader.Read(tarballPath)
+	cache := loadReleaseCache(directoryPath)
+	updatedCache := releaseCache{Version: releaseCacheVersion, Releases: map[string]releaseCacheEntry{}}
+
+	releases := make([]builder.Part, len(tarballPaths))
+	keys := make([]string, len(tarballPaths))
+	var uncached []int
+	for i, tarballPath := range tarballPaths {
+		keys[i], err = filepath.Rel(directoryPath, tarballPath)
 		if err != nil {
-			return nil, err
+			return nil, err // should never happen
+		}
+
+		manifest, ok := cache.lookup(keys[i], tarballInfos[i])
+		if !ok {
+			uncached = append(uncached, i)
+			continue
+		}
+
+		releases[i] = builder.Part{File: tarballPath, Path: tarballPath, Name: manifest.Name, Metadata: manifest}
+		updatedCache.Releases[keys[i]] = cache.Releases[keys[i]]
+	}
+
+	err = s.readTarballs(tarballPaths, uncached, releases)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range uncached {
+		manifest, ok := releases[i].Metadata.(builder.ReleaseManifest)
+		if !ok {
+			continue
 		}
 
-		releases = append(releases, rel)
+		updatedCache.Releases[keys[i]] = releaseCacheEntry{
+			Size:     tarballInfos[i].Size(),
+			ModTime:  tarballInfos[i].ModTime().UnixNano(),
+			Manifest: manifest,
+		}
+	}
+
+	if len(uncached) > 0 || len(updatedCache.Releases) != len(cache.Releases) {
+		err = updatedCache.write(directoryPath)
+		if err != nil {
+			s.logger.Println(fmt.Sprintf("Unable to update the release cache in %q: %s", directoryPath, err))
+		}
+	}
+
+	return releases, nil
+}
+
+// readTarballs reads the tarballs at the given indices into releases using
+// one worker per CPU, and returns the error of the first tarball that fails.
+func (s ReleasesService) readTarballs(tarballPaths []string, indices []int, releases []builder.Part) error {
+	errs := make([]error, len(tarballPaths))
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU() && w < len(indices); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				releases[i], errs[i] = s.reader.Read(tarballPaths[i])
+			}
+		}()
+	}
+
+	for _, i := range indices {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
 	}
 
-	return releases, err
+	return nil
 }
@@ -2,6 +2,7 @@ package baking_test
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -56,37 +57,92 @@ var _ = Describe("ReleasesService", func() {
 		})
 
 		It("parses the releases passed in a set of directories", func() {
-			reader.ReadReturnsOnCall(0, builder.Part{
-				File:     "some-file",
-				Name:     "some-name",
-				Metadata: "some-metadata",
-			}, nil)
-
-			reader.ReadReturnsOnCall(1, builder.Part{
-				File:     "other-file",
-				Name:     "other-name",
-				Metadata: "other-metadata",
-			}, nil)
+			reader.ReadStub = func(path string) (builder.Part, error) {
+				if filepath.Base(path) == "other-release.tgz" {
+					return builder.Part{File: "some-file", Name: "some-name", Metadata: "some-metadata"}, nil
+				}
+				return builder.Part{File: "other-file", Name: "other-name", Metadata: "other-metadata"}, nil
+			}
 
-			releases, err := service.FromDirectories([]string{tempDir})
+			releases, err := service.FromDirectories([]string{tempDir}, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(releases).To(Equal(map[string]interface{}{
-				"some-name":  "some-metadata",
-				"other-name": "other-metadata",
+				"some-name": builder.Part{
+					File:     "some-file",
+					Name:     "some-name",
+					Metadata: "some-metadata",
+				},
+				"other-name": builder.Part{
+					File:     "other-file",
+					Name:     "other-name",
+					Metadata: "other-metadata",
+				},
 			}))
 
 			Expect(logger.PrintlnCallCount()).To(Equal(1))
 			Expect(logger.PrintlnArgsForCall(0)).To(Equal([]interface{}{"Reading release manifests..."}))
 
 			Expect(reader.ReadCallCount()).To(Equal(2))
-			Expect(reader.ReadArgsForCall(0)).To(Equal(filepath.Join(tempDir, "other-release.tgz")))
-			Expect(reader.ReadArgsForCall(1)).To(Equal(filepath.Join(tempDir, "some-release.tar.gz")))
+			Expect([]string{reader.ReadArgsForCall(0), reader.ReadArgsForCall(1)}).To(ConsistOf(
+				filepath.Join(tempDir, "other-release.tgz"),
+				filepath.Join(tempDir, "some-release.tar.gz"),
+			))
+		})
+
+		Context("when a release name appears in more than one directory", func() {
+			var otherDir string
+
+			BeforeEach(func() {
+				var err error
+				otherDir, err = ioutil.TempDir("", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(ioutil.WriteFile(filepath.Join(otherDir, "some-release-2.tgz"), nil, 0644)).To(Succeed())
+
+				reader.ReadStub = func(path string) (builder.Part, error) {
+					return builder.Part{File: path, Name: "some-name", Metadata: path}, nil
+				}
+			})
+
+			AfterEach(func() {
+				Expect(os.RemoveAll(otherDir)).To(Succeed())
+			})
+
+			It("returns an error naming both tarballs", func() {
+				_, err := service.FromDirectories([]string{tempDir, otherDir}, false)
+				Expect(err).To(MatchError(fmt.Sprintf("release %q is defined in both %q and %q",
+					"some-name",
+					filepath.Join(tempDir, "other-release.tgz"),
+					filepath.Join(tempDir, "some-release.tar.gz"),
+				)))
+			})
+
+			Context("when overrides are allowed", func() {
+				It("still rejects duplicates within a directory", func() {
+					_, err := service.FromDirectories([]string{tempDir, otherDir}, true)
+					Expect(err).To(MatchError(ContainSubstring("is defined in both")))
+				})
+
+				It("uses the release from the later directory", func() {
+					Expect(os.Remove(filepath.Join(tempDir, "other-release.tgz"))).To(Succeed())
+
+					releases, err := service.FromDirectories([]string{tempDir, otherDir}, true)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(releases).To(Equal(map[string]interface{}{
+						"some-name": builder.Part{
+							File:     filepath.Join(otherDir, "some-release-2.tgz"),
+							Name:     "some-name",
+							Metadata: filepath.Join(otherDir, "some-release-2.tgz"),
+						},
+					}))
+				})
+			})
 		})
 
 		Context("failure cases", func() {
 			Context("when there is a directory that does not exist", func() {
 				It("returns an error", func() {
-					_, err := service.FromDirectories([]string{"missing-directory"})
+					_, err := service.FromDirectories([]string{"missing-directory"}, false)
 					Expect(err).To(MatchError("lstat missing-directory: no such file or directory"))
 				})
 			})
@@ -95,7 +151,7 @@ var _ = Describe("ReleasesService", func() {
 				It("returns an error", func() {
 					reader.ReadReturns(builder.Part{}, errors.New("failed to read release manifest"))
 
-					_, err := service.FromDirectories([]string{tempDir})
+					_, err := service.FromDirectories([]string{tempDir}, false)
 					Expect(err).To(MatchError("failed to read release manifest"))
 				})
 			})
@@ -152,22 +208,79 @@ var _ = Describe("ReleasesService", func() {
 				Name:     "some-name",
 				Metadata: "some-metadata",
 			}
-			reader.ReadReturnsOnCall(0, release1, nil)
 
 			release2 := builder.Part{
 				File:     "other-file",
 				Name:     "other-name",
 				Metadata: "other-metadata",
 			}
-			reader.ReadReturnsOnCall(1, release2, nil)
+
+			reader.ReadStub = func(path string) (builder.Part, error) {
+				if filepath.Base(path) == "other-release.tgz" {
+					return release1, nil
+				}
+				return release2, nil
+			}
 
 			releases, err := service.ReleasesInDirectory(tempDir)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(releases).To(Equal([]builder.Part{release1, release2}))
 
 			Expect(reader.ReadCallCount()).To(Equal(2))
-			Expect(reader.ReadArgsForCall(0)).To(Equal(filepath.Join(nestedDir, "other-release.tgz")))
-			Expect(reader.ReadArgsForCall(1)).To(Equal(filepath.Join(nestedDir, "some-release.tar.gz")))
+			Expect([]string{reader.ReadArgsForCall(0), reader.ReadArgsForCall(1)}).To(ConsistOf(
+				filepath.Join(nestedDir, "other-release.tgz"),
+				filepath.Join(nestedDir, "some-release.tar.gz"),
+			))
+		})
+
+		Context("when the reader returns release manifests", func() {
+			BeforeEach(func() {
+				reader.ReadStub = func(path string) (builder.Part, error) {
+					manifest := builder.ReleaseManifest{Name: filepath.Base(path), Version: "1.2.3", File: filepath.Base(path), SHA1: "some-sha1", SHA256: "some-sha256"}
+					return builder.Part{File: path, Path: path, Name: manifest.Name, Metadata: manifest}, nil
+				}
+			})
+
+			It("caches them so that unchanged tarballs are not read again", func() {
+				releases, err := service.ReleasesInDirectory(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reader.ReadCallCount()).To(Equal(2))
+				Expect(filepath.Join(tempDir, ReleaseCacheFile)).To(BeAnExistingFile())
+
+				cachedReleases, err := service.ReleasesInDirectory(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cachedReleases).To(Equal(releases))
+				Expect(reader.ReadCallCount()).To(Equal(2))
+			})
+
+			It("writes a cache file that other users can read", func() {
+				_, err := service.ReleasesInDirectory(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err := os.Stat(filepath.Join(tempDir, ReleaseCacheFile))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0644)))
+			})
+
+			It("reads a tarball again when its size or modification time changes", func() {
+				_, err := service.ReleasesInDirectory(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(ioutil.WriteFile(filepath.Join(nestedDir, "some-release.tar.gz"), []byte("changed"), 0644)).To(Succeed())
+
+				_, err = service.ReleasesInDirectory(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reader.ReadCallCount()).To(Equal(3))
+				Expect(reader.ReadArgsForCall(2)).To(Equal(filepath.Join(nestedDir, "some-release.tar.gz")))
+			})
+
+			It("ignores a cache file it cannot parse", func() {
+				Expect(ioutil.WriteFile(filepath.Join(tempDir, ReleaseCacheFile), []byte("not json"), 0644)).To(Succeed())
+
+				_, err := service.ReleasesInDirectory(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reader.ReadCallCount()).To(Equal(2))
+			})
 		})
 
 		Context("failure cases", func() {
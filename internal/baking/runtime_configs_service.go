@@ -12,24 +12,27 @@ func NewRuntimeConfigsService(logger logger, reader directoryReader) RuntimeConf
 	}
 }
 
-func (rcs RuntimeConfigsService) FromDirectories(directories []string) (map[string]interface{}, error) {
+func (rcs RuntimeConfigsService) FromDirectories(directories []string, allowOverrides bool) (map[string]interface{}, error) {
 	if len(directories) == 0 {
 		return nil, nil
 	}
 
 	rcs.logger.Println("Reading runtime config files...")
 
-	runtimeConfigs := map[string]interface{}{}
-	for _, directory := range directories {
+	runtimeConfigs := newPartSet("runtime config", allowOverrides, rcs.logger)
+	for i, directory := range directories {
 		directoryRuntimeConfigs, err := rcs.reader.Read(directory)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, runtimeConfig := range directoryRuntimeConfigs {
-			runtimeConfigs[runtimeConfig.Name] = runtimeConfig.Metadata
+			err = runtimeConfigs.add(i, runtimeConfig.Path, runtimeConfig)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	return runtimeConfigs, nil
+	return runtimeConfigs.parts, nil
 }
@@ -22,7 +22,7 @@ var _ = Describe("RuntimeConfigsService", func() {
 		BeforeEach(func() {
 			logger = &fakes.Logger{}
 			reader = &fakes.DirectoryReader{}
-			reader.ReadReturns([]builder.Part{
+			reader.ReadReturnsOnCall(1, []builder.Part{
 				{
 					Name: "some-runtime-config",
 					Metadata: builder.Metadata{
@@ -35,11 +35,14 @@ var _ = Describe("RuntimeConfigsService", func() {
 		})
 
 		It("parses the runtime configs passed in a set of directories", func() {
-			runtimeConfigs, err := service.FromDirectories([]string{"some-runtime-configs", "other-runtime-configs"})
+			runtimeConfigs, err := service.FromDirectories([]string{"some-runtime-configs", "other-runtime-configs"}, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(runtimeConfigs).To(Equal(map[string]interface{}{
-				"some-runtime-config": builder.Metadata{
-					"key": "value",
+				"some-runtime-config": builder.Part{
+					Name: "some-runtime-config",
+					Metadata: builder.Metadata{
+						"key": "value",
+					},
 				},
 			}))
 
@@ -53,11 +56,11 @@ var _ = Describe("RuntimeConfigsService", func() {
 
 		Context("when the directories argument is empty", func() {
 			It("returns nothing", func() {
-				runtimeConfigs, err := service.FromDirectories(nil)
+				runtimeConfigs, err := service.FromDirectories(nil, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(runtimeConfigs).To(BeNil())
 
-				runtimeConfigs, err = service.FromDirectories([]string{})
+				runtimeConfigs, err = service.FromDirectories([]string{}, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(runtimeConfigs).To(BeNil())
 
@@ -71,7 +74,7 @@ var _ = Describe("RuntimeConfigsService", func() {
 				It("returns an error", func() {
 					reader.ReadReturns(nil, errors.New("failed to read"))
 
-					_, err := service.FromDirectories([]string{"some-runtime-configs"})
+					_, err := service.FromDirectories([]string{"some-runtime-configs"}, false)
 					Expect(err).To(MatchError("failed to read"))
 				})
 			})
@@ -0,0 +1,71 @@
+package baking
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/kiln/internal/signing"
+)
+
+type Signer struct {
+	logger logger
+}
+
+func NewSigner(logger logger) Signer {
+	return Signer{logger: logger}
+}
+
+// Sign writes a detached signature of the file at path, made with the ed25519
+// or PGP private key at keyPath, to a file next to it, for example
+// tile.pivotal.sig. An ed25519 key signs sha256, the hex encoded SHA256
+// digest of the file, without reading it. A PGP signature hashes the file
+// itself, so it is read again.
+func (s Signer) Sign(path, keyPath, sha256 string) error {
+	privateKey, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("could not read signing key: %w", err)
+	}
+
+	s.logger.Println(fmt.Sprintf("Signing %s...", path))
+
+	var signature []byte
+	if signing.IsPGPKey(privateKey) {
+		signature, err = s.signFile(path, privateKey)
+	} else {
+		signature, err = signDigest(sha256, privateKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	signaturePath := path + signing.SignatureExtension
+	err = ioutil.WriteFile(signaturePath, signature, 0644)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Println(fmt.Sprintf("Signature: %s", signaturePath))
+
+	return nil
+}
+
+func (s Signer) signFile(path string, privateKey []byte) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return signing.Sign(privateKey, file)
+}
+
+func signDigest(sha256 string, privateKey []byte) ([]byte, error) {
+	digest, err := hex.DecodeString(sha256)
+	if err != nil || len(digest) != 32 {
+		return nil, fmt.Errorf("%q is not a hex encoded SHA256 digest", sha256)
+	}
+
+	return signing.SignSHA256(privateKey, digest)
+}
@@ -0,0 +1,138 @@
+package baking_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/pivotal-cf/kiln/internal/baking"
+	"github.com/pivotal-cf/kiln/internal/baking/fakes"
+	"github.com/pivotal-cf/kiln/internal/signing"
+	test_helpers "github.com/pivotal-cf/kiln/internal/test-helpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signer", func() {
+	// echo -n some-tile-contents | sha256sum
+	const sha256Digest = "237d5da4ab568ea8ea5a918cebe441626290459e7f14e0ddbbcfcdbed29544c2"
+
+	var (
+		logger     *fakes.Logger
+		signer     Signer
+		tmpdir     string
+		path       string
+		keyPath    string
+		publicKey  []byte
+		privateKey []byte
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		signer = NewSigner(logger)
+
+		var err error
+		tmpdir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(tmpdir, "tile.pivotal")
+		err = ioutil.WriteFile(path, []byte("some-tile-contents"), 0644)
+		Expect(err).NotTo(HaveOccurred())
+
+		privateKey, publicKey, err = test_helpers.GenerateEd25519Keys()
+		Expect(err).NotTo(HaveOccurred())
+
+		keyPath = filepath.Join(tmpdir, "signing-key.pem")
+		err = ioutil.WriteFile(keyPath, privateKey, 0600)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpdir)).To(Succeed())
+	})
+
+	It("writes a detached signature next to the file", func() {
+		err := signer.Sign(path, keyPath, sha256Digest)
+		Expect(err).NotTo(HaveOccurred())
+
+		signature, err := ioutil.ReadFile(path + ".sig")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = signing.Verify(publicKey, strings.NewReader("some-tile-contents"), signature)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(logger.PrintlnCallCount()).To(Equal(2))
+		Expect(logger.PrintlnArgsForCall(0)).To(Equal([]interface{}{"Signing " + path + "..."}))
+		Expect(logger.PrintlnArgsForCall(1)).To(Equal([]interface{}{"Signature: " + path + ".sig"}))
+	})
+
+	It("signs the SHA256 digest of an ed25519 key without reading the file", func() {
+		Expect(os.Remove(path)).To(Succeed())
+
+		err := signer.Sign(path, keyPath, sha256Digest)
+		Expect(err).NotTo(HaveOccurred())
+
+		signature, err := ioutil.ReadFile(path + ".sig")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = signing.Verify(publicKey, strings.NewReader("some-tile-contents"), signature)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("when the key is a PGP key", func() {
+		BeforeEach(func() {
+			var err error
+			privateKey, publicKey, err = test_helpers.GeneratePGPKeys()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ioutil.WriteFile(keyPath, privateKey, 0600)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("signs the contents of the file", func() {
+			err := signer.Sign(path, keyPath, sha256Digest)
+			Expect(err).NotTo(HaveOccurred())
+
+			signature, err := ioutil.ReadFile(path + ".sig")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = signing.Verify(publicKey, strings.NewReader("some-tile-contents"), signature)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the file does not exist", func() {
+			It("returns an error", func() {
+				err := signer.Sign(filepath.Join(tmpdir, "missing.pivotal"), keyPath, sha256Digest)
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+			})
+		})
+	})
+
+	Context("failure cases", func() {
+		Context("when the key cannot be read", func() {
+			It("returns an error", func() {
+				err := signer.Sign(path, filepath.Join(tmpdir, "missing-key.pem"), sha256Digest)
+				Expect(err).To(MatchError(ContainSubstring("could not read signing key")))
+			})
+		})
+
+		Context("when the key is not a supported key", func() {
+			It("returns an error", func() {
+				err := ioutil.WriteFile(keyPath, []byte("not-a-key"), 0600)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = signer.Sign(path, keyPath, sha256Digest)
+				Expect(err).To(MatchError(ContainSubstring("key must be a PEM encoded ed25519 private key")))
+			})
+		})
+
+		Context("when the digest is not a hex encoded SHA256 digest", func() {
+			It("returns an error", func() {
+				err := signer.Sign(path, keyPath, "some-digest")
+				Expect(err).To(MatchError(`"some-digest" is not a hex encoded SHA256 digest`))
+			})
+		})
+	})
+})
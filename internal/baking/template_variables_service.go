@@ -2,9 +2,10 @@ package baking
 
 import (
 	"fmt"
-	"gopkg.in/src-d/go-billy.v4"
+	"sort"
 	"strings"
 
+	"gopkg.in/src-d/go-billy.v4"
 	"gopkg.in/yaml.v2"
 )
 
@@ -12,35 +13,130 @@ type TemplateVariablesService struct {
 	filesystem billy.Filesystem
 }
 
+// TemplateVariableSource records where the final value of a template variable
+// came from. Name is the dotted path to the value, for example "network.name".
+type TemplateVariableSource struct {
+	Name   string
+	Value  interface{}
+	Source string
+}
+
 func NewTemplateVariablesService(fs billy.Filesystem) TemplateVariablesService {
 	return TemplateVariablesService{filesystem: fs}
 }
 
-func (s TemplateVariablesService) FromPathsAndPairs(paths []string, pairs []string) (map[string]interface{}, error) {
+// FromPathsAndPairs deep merges template variables in order of increasing
+// precedence: each variables file in the order given, then the string pairs
+// from --variable, then the YAML pairs from --variable-yaml. Pair keys may be
+// dotted to set a value inside a nested map. It also returns the source of
+// every leaf value, sorted by name.
+func (s TemplateVariablesService) FromPathsAndPairs(paths []string, pairs []string, yamlPairs []string) (map[string]interface{}, []TemplateVariableSource, error) {
 	variables := map[string]interface{}{}
+	sources := map[string]string{}
 
 	for _, path := range paths {
 		file, err := s.filesystem.Open(path)
 		if err != nil {
-			return nil, fmt.Errorf("unable to open file %q: %w", path, err)
+			return nil, nil, fmt.Errorf("unable to open file %q: %w", path, err)
 		}
 
-		err = yaml.NewDecoder(file).Decode(&variables)
+		var fileVariables map[string]interface{}
+		err = yaml.NewDecoder(file).Decode(&fileVariables)
 		file.Close()
 		if err != nil {
-			return nil, fmt.Errorf("unable to YAML parse %q: %w", path, err)
+			return nil, nil, fmt.Errorf("unable to YAML parse %q: %w", path, err)
+		}
+
+		for key, value := range fileVariables {
+			setTemplateVariable(variables, sources, []string{key}, value, path)
 		}
 	}
 
 	for _, pair := range pairs {
-		parts := strings.Split(pair, "=")
+		key, value, err := splitTemplateVariablePair(pair)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		setTemplateVariable(variables, sources, strings.Split(key, "."), value, "--variable")
+	}
+
+	for _, pair := range yamlPairs {
+		key, rawValue, err := splitTemplateVariablePair(pair)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var value interface{}
+		err = yaml.Unmarshal([]byte(rawValue), &value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse YAML value of variable %q: %w", key, err)
+		}
+
+		setTemplateVariable(variables, sources, strings.Split(key, "."), value, "--variable-yaml")
+	}
+
+	var leaves []TemplateVariableSource
+	for name, value := range variables {
+		leaves = appendTemplateVariableLeaves(leaves, sources, name, value)
+	}
+	sort.Slice(leaves, func(i, j int) bool { return leaves[i].Name < leaves[j].Name })
 
-		if len(parts) < 2 {
-			return nil, fmt.Errorf("could not parse variable %q: expected variable in \"key=value\" form", pair)
+	return variables, leaves, nil
+}
+
+func splitTemplateVariablePair(pair string) (string, string, error) {
+	parts := strings.SplitN(pair, "=", 2)
+	if len(parts) < 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("could not parse variable %q: expected variable in \"key=value\" form", pair)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func setTemplateVariable(variables map[string]interface{}, sources map[string]string, path []string, value interface{}, source string) {
+	for i := len(path) - 1; i > 0; i-- {
+		value = map[interface{}]interface{}{path[i]: value}
+	}
+
+	variables[path[0]] = deepMerge(variables[path[0]], value)
+	recordTemplateVariableSources(sources, path[0], value, source)
+}
+
+// deepMerge merges value into existing when both are maps, and otherwise
+// returns value.
+func deepMerge(existing, value interface{}) interface{} {
+	existingMap, existingIsMap := existing.(map[interface{}]interface{})
+	valueMap, valueIsMap := value.(map[interface{}]interface{})
+	if !existingIsMap || !valueIsMap {
+		return value
+	}
+
+	for key, v := range valueMap {
+		existingMap[key] = deepMerge(existingMap[key], v)
+	}
+
+	return existingMap
+}
+
+func recordTemplateVariableSources(sources map[string]string, name string, value interface{}, source string) {
+	if m, ok := value.(map[interface{}]interface{}); ok && len(m) > 0 {
+		for key, v := range m {
+			recordTemplateVariableSources(sources, fmt.Sprintf("%s.%v", name, key), v, source)
 		}
+		return
+	}
 
-		variables[parts[0]] = parts[1]
+	sources[name] = source
+}
+
+func appendTemplateVariableLeaves(leaves []TemplateVariableSource, sources map[string]string, name string, value interface{}) []TemplateVariableSource {
+	if m, ok := value.(map[interface{}]interface{}); ok && len(m) > 0 {
+		for key, v := range m {
+			leaves = appendTemplateVariableLeaves(leaves, sources, fmt.Sprintf("%s.%v", name, key), v)
+		}
+		return leaves
 	}
 
-	return variables, nil
+	return append(leaves, TemplateVariableSource{Name: name, Value: value, Source: sources[name]})
 }
@@ -47,7 +47,7 @@ key-3: value-3
 		})
 
 		It("parses template variables from a collection of files", func() {
-			variables, err := service.FromPathsAndPairs([]string{path}, nil)
+			variables, _, err := service.FromPathsAndPairs([]string{path}, nil, nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(variables).To(Equal(map[string]interface{}{
 				"key-1": map[interface{}]interface{}{
@@ -61,10 +61,10 @@ key-3: value-3
 		})
 
 		It("parses template variables from command-line arguments", func() {
-			variables, err := service.FromPathsAndPairs(nil, []string{
+			variables, _, err := service.FromPathsAndPairs(nil, []string{
 				"key-1=value-1",
 				"key-2=value-2",
-			})
+			}, nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(variables).To(Equal(map[string]interface{}{
 				"key-1": "value-1",
@@ -72,10 +72,85 @@ key-3: value-3
 			}))
 		})
 
+		It("splits command-line arguments on the first equals sign only", func() {
+			variables, _, err := service.FromPathsAndPairs(nil, []string{"key-1=value=with=equals"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variables).To(Equal(map[string]interface{}{
+				"key-1": "value=with=equals",
+			}))
+		})
+
+		It("parses the values of YAML command-line arguments", func() {
+			variables, _, err := service.FromPathsAndPairs(nil, nil, []string{
+				"key-1=[1, 2]",
+				"key-2=true",
+				"key-3={name: value}",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variables).To(Equal(map[string]interface{}{
+				"key-1": []interface{}{1, 2},
+				"key-2": true,
+				"key-3": map[interface{}]interface{}{"name": "value"},
+			}))
+		})
+
+		Context("when there are several sources", func() {
+			var otherPath string
+
+			BeforeEach(func() {
+				file, err := ioutil.TempFile("", "variables")
+				Expect(err).NotTo(HaveOccurred())
+				otherPath = file.Name()
+
+				_, err = file.WriteString(`---
+key-1:
+  key-4: value-4
+key-3: other-value-3
+key-5:
+  key-6: value-6
+  key-7: value-7
+`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(file.Close()).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.Remove(otherPath)).To(Succeed())
+			})
+
+			It("deep merges them with later files and then arguments taking precedence", func() {
+				variables, sources, err := service.FromPathsAndPairs(
+					[]string{path, otherPath},
+					[]string{"key-5.key-6=string-value-6"},
+					[]string{"key-5.key-7=7"},
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(variables).To(Equal(map[string]interface{}{
+					"key-1": map[interface{}]interface{}{
+						"key-2": []interface{}{"value-1", "value-2"},
+						"key-4": "value-4",
+					},
+					"key-3": "other-value-3",
+					"key-5": map[interface{}]interface{}{
+						"key-6": "string-value-6",
+						"key-7": 7,
+					},
+				}))
+
+				Expect(sources).To(Equal([]TemplateVariableSource{
+					{Name: "key-1.key-2", Value: []interface{}{"value-1", "value-2"}, Source: path},
+					{Name: "key-1.key-4", Value: "value-4", Source: otherPath},
+					{Name: "key-3", Value: "other-value-3", Source: otherPath},
+					{Name: "key-5.key-6", Value: "string-value-6", Source: "--variable"},
+					{Name: "key-5.key-7", Value: 7, Source: "--variable-yaml"},
+				}))
+			})
+		})
+
 		Context("failure cases", func() {
 			Context("when the variable file cannot be read", func() {
 				It("returns an error", func() {
-					_, err := service.FromPathsAndPairs([]string{"missing.yml"}, nil)
+					_, _, err := service.FromPathsAndPairs([]string{"missing.yml"}, nil, nil)
 					Expect(err).To(MatchError(ContainSubstring("open missing.yml: no such file or directory")))
 				})
 			})
@@ -85,14 +160,21 @@ key-3: value-3
 					err := ioutil.WriteFile(path, []byte("\t\t\t"), 0644)
 					Expect(err).NotTo(HaveOccurred())
 
-					_, err = service.FromPathsAndPairs([]string{path}, nil)
+					_, _, err = service.FromPathsAndPairs([]string{path}, nil, nil)
 					Expect(err).To(MatchError(ContainSubstring("yaml: found character that cannot start any token")))
 				})
 			})
 
+			Context("when a YAML command-line variable cannot be parsed", func() {
+				It("returns an error", func() {
+					_, _, err := service.FromPathsAndPairs(nil, nil, []string{"key-1=[unterminated"})
+					Expect(err).To(MatchError(ContainSubstring(`could not parse YAML value of variable "key-1"`)))
+				})
+			})
+
 			Context("when the command-line variables are malformed", func() {
 				It("returns an error", func() {
-					_, err := service.FromPathsAndPairs(nil, []string{"garbage"})
+					_, _, err := service.FromPathsAndPairs(nil, []string{"garbage"}, nil)
 					Expect(err).To(MatchError("could not parse variable \"garbage\": expected variable in \"key=value\" form"))
 				})
 			})
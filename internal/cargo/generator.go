@@ -1,9 +1,10 @@
 package cargo
 
 import (
+	"fmt"
+
 	"github.com/pivotal-cf/kiln/internal/cargo/opsman"
 	"github.com/pivotal-cf/kiln/proofing"
-	yaml "gopkg.in/yaml.v2"
 )
 
 type OpsManagerConfig struct {
@@ -11,6 +12,18 @@ type OpsManagerConfig struct {
 	AvailabilityZones []string
 	Stemcells         []opsman.Stemcell
 	ResourceConfigs   []opsman.ResourceConfig
+	Network           string
+
+	// Properties are the values of the property blueprints keyed by their
+	// accessor without the trailing ".value", e.g. ".properties.some-property".
+	// Unset properties take the default of their blueprint.
+	Properties map[string]interface{}
+
+	// Self, Director and OtherProducts hold the values of the $self.*,
+	// $director.* and ..other-product accessors, see opsman.Environment.
+	Self          map[string]interface{}
+	Director      map[string]interface{}
+	OtherProducts map[string]interface{}
 }
 
 type Generator struct{}
@@ -19,11 +32,26 @@ func NewGenerator() Generator {
 	return Generator{}
 }
 
-func (g Generator) Execute(template proofing.ProductTemplate, config OpsManagerConfig) Manifest {
+func (g Generator) Execute(template proofing.ProductTemplate, config OpsManagerConfig) (Manifest, error) {
+	stemcell, err := findStemcell(template.StemcellCriteria, config.Stemcells)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	resourceConfigs := resolveResourceConfigs(template.JobTypes, config.ResourceConfigs)
+
+	evaluator, err := newEvaluator(template, config, resourceConfigs)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	instanceGroups, err := generateInstanceGroups(template.JobTypes, config, resourceConfigs, stemcell.Alias, evaluator)
+	if err != nil {
+		return Manifest{}, err
+	}
+
 	releases := generateReleases(template.Releases)
-	stemcell := findStemcell(template.StemcellCriteria, config.Stemcells)
 	update := generateUpdate(template.Serial)
-	instanceGroups := generateInstanceGroups(template.JobTypes, config.ResourceConfigs, config.AvailabilityZones, stemcell.Alias)
 	variables := generateVariables(template.Variables)
 
 	return Manifest{
@@ -33,7 +61,52 @@ func (g Generator) Execute(template proofing.ProductTemplate, config OpsManagerC
 		Update:         update,
 		Variables:      variables,
 		InstanceGroups: instanceGroups,
+	}, nil
+}
+
+// RuntimeConfigs evaluates the runtime configs of the product template, which
+// Ops Manager applies to the BOSH director alongside the manifest.
+func (g Generator) RuntimeConfigs(template proofing.ProductTemplate, config OpsManagerConfig) ([]RuntimeConfig, error) {
+	resourceConfigs := resolveResourceConfigs(template.JobTypes, config.ResourceConfigs)
+
+	evaluator, err := newEvaluator(template, config, resourceConfigs)
+	if err != nil {
+		return nil, err
+	}
+
+	var runtimeConfigs []RuntimeConfig
+	for _, runtimeConfig := range template.RuntimeConfigs {
+		evaluated, err := evaluator.EvaluateManifest(runtimeConfig.RuntimeConfig, "")
+		if err != nil {
+			return nil, fmt.Errorf("could not evaluate runtime config %q: %w", runtimeConfig.Name, err)
+		}
+
+		runtimeConfigs = append(runtimeConfigs, RuntimeConfig{
+			Name:          runtimeConfig.Name,
+			RuntimeConfig: evaluated,
+		})
 	}
+
+	return runtimeConfigs, nil
+}
+
+func newEvaluator(template proofing.ProductTemplate, config OpsManagerConfig, resourceConfigs map[string]opsman.ResourceConfig) (opsman.Evaluator, error) {
+	env := opsman.Environment{
+		DeploymentName:    config.DeploymentName,
+		Network:           config.Network,
+		AvailabilityZones: config.AvailabilityZones,
+		Instances:         map[string]int{},
+		IPs:               map[string][]string{},
+		Self:              config.Self,
+		Director:          config.Director,
+		OtherProducts:     config.OtherProducts,
+	}
+	for name, resourceConfig := range resourceConfigs {
+		env.Instances[name] = resourceConfig.Instances.Value
+		env.IPs[name] = resourceConfig.StaticIPs
+	}
+
+	return opsman.NewEvaluator(template, config.Properties, env)
 }
 
 func generateReleases(templateReleases []proofing.Release) []Release {
@@ -50,22 +123,30 @@ func generateReleases(templateReleases []proofing.Release) []Release {
 	return releases
 }
 
-func findStemcell(criteria proofing.StemcellCriteria, stemcells []opsman.Stemcell) Stemcell {
-	var stemcell Stemcell
+// findStemcell returns the stemcell matching the stemcell criteria, or the
+// criteria themselves aliased "default" when no stemcells are given.
+func findStemcell(criteria proofing.StemcellCriteria, stemcells []opsman.Stemcell) (Stemcell, error) {
+	if len(stemcells) == 0 {
+		return Stemcell{
+			Alias:   "default",
+			OS:      criteria.OS,
+			Version: criteria.Version,
+		}, nil
+	}
 
 	for _, s := range stemcells {
 		if s.OS == criteria.OS {
 			if s.Version == criteria.Version {
-				stemcell = Stemcell{
+				return Stemcell{
 					Alias:   s.Name,
 					OS:      s.OS,
 					Version: s.Version,
-				}
+				}, nil
 			}
 		}
 	}
 
-	return stemcell
+	return Stemcell{}, fmt.Errorf("no stemcell matches the stemcell criteria %s %s", criteria.OS, criteria.Version)
 }
 
 func generateUpdate(serial bool) Update {
@@ -79,7 +160,34 @@ func generateUpdate(serial bool) Update {
 	}
 }
 
-func generateInstanceGroups(jobTypes []proofing.JobType, resourceConfigs []opsman.ResourceConfig, availabilityZones []string, stemcellAlias string) []InstanceGroup {
+// resolveResourceConfigs returns the resource config of each job type, keyed
+// by name, with automatic instances replaced by the instance definition
+// default.
+func resolveResourceConfigs(jobTypes []proofing.JobType, resourceConfigs []opsman.ResourceConfig) map[string]opsman.ResourceConfig {
+	resolved := map[string]opsman.ResourceConfig{}
+
+	for _, jobType := range jobTypes {
+		resourceConfig := opsman.ResourceConfig{
+			Name:      jobType.Name,
+			Instances: opsman.ResourceConfigInstances{Value: -1},
+		}
+		for _, rc := range resourceConfigs {
+			if rc.Name == jobType.Name {
+				resourceConfig = rc
+			}
+		}
+
+		if resourceConfig.Instances.IsAutomatic() {
+			resourceConfig.Instances.Value = jobType.InstanceDefinition.Default
+		}
+
+		resolved[jobType.Name] = resourceConfig
+	}
+
+	return resolved
+}
+
+func generateInstanceGroups(jobTypes []proofing.JobType, config OpsManagerConfig, resourceConfigs map[string]opsman.ResourceConfig, stemcellAlias string, evaluator opsman.Evaluator) ([]InstanceGroup, error) {
 	var instanceGroups []InstanceGroup
 
 	for _, jobType := range jobTypes {
@@ -88,65 +196,69 @@ func generateInstanceGroups(jobTypes []proofing.JobType, resourceConfigs []opsma
 			lifecycle = "errand"
 		}
 
-		instances := jobType.InstanceDefinition.Default
-		for _, resourceConfig := range resourceConfigs {
-			if resourceConfig.Name == jobType.Name {
-				if !resourceConfig.Instances.IsAutomatic() {
-					instances = resourceConfig.Instances.Value
-				}
-			}
+		resourceConfig := resourceConfigs[jobType.Name]
+
+		var networks []InstanceGroupNetwork
+		if config.Network != "" {
+			networks = []InstanceGroupNetwork{{Name: config.Network, StaticIPs: resourceConfig.StaticIPs}}
+		}
+
+		jobs, err := generateInstanceGroupJobs(jobType, evaluator)
+		if err != nil {
+			return nil, err
 		}
 
-		jobs := generateInstanceGroupJobs(jobType.Templates)
-		properties := evaluateManifestSnippet(jobType.Manifest)
+		properties, err := evaluator.EvaluateManifest(jobType.Manifest, jobType.Name)
+		if err != nil {
+			return nil, fmt.Errorf("could not evaluate the manifest of job type %q: %w", jobType.Name, err)
+		}
 
 		instanceGroups = append(instanceGroups, InstanceGroup{
-			Name:       jobType.Name,
-			AZs:        availabilityZones,
-			Lifecycle:  lifecycle,
-			Stemcell:   stemcellAlias,
-			Instances:  instances,
-			Jobs:       jobs,
-			Properties: properties,
+			Name:           jobType.Name,
+			AZs:            config.AvailabilityZones,
+			Lifecycle:      lifecycle,
+			Stemcell:       stemcellAlias,
+			Instances:      resourceConfig.Instances.Value,
+			VMType:         resourceConfig.VMType,
+			PersistentDisk: resourceConfig.PersistentDisk,
+			Networks:       networks,
+			Jobs:           jobs,
+			Properties:     properties,
 		})
 	}
 
-	return instanceGroups
+	return instanceGroups, nil
 }
 
-func generateInstanceGroupJobs(templates []proofing.Template) []InstanceGroupJob {
+func generateInstanceGroupJobs(jobType proofing.JobType, evaluator opsman.Evaluator) ([]InstanceGroupJob, error) {
 	var jobs []InstanceGroupJob
 
-	for _, template := range templates {
-		provides := evaluateManifestSnippet(template.Provides)
-		consumes := evaluateManifestSnippet(template.Consumes)
-		properties := evaluateManifestSnippet(template.Manifest)
-
-		jobs = append(jobs, InstanceGroupJob{
-			Name:       template.Name,
-			Release:    template.Release,
-			Provides:   provides,
-			Consumes:   consumes,
-			Properties: properties,
-		})
-	}
-
-	return jobs
-}
-
-func evaluateManifestSnippet(snippet string) interface{} {
-	var result interface{}
+	for _, template := range jobType.Templates {
+		job := InstanceGroupJob{
+			Name:    template.Name,
+			Release: template.Release,
+		}
 
-	if snippet == "" {
-		snippet = "{}"
-	}
+		for _, snippet := range []struct {
+			field  string
+			source string
+			target *interface{}
+		}{
+			{"provides", template.Provides, &job.Provides},
+			{"consumes", template.Consumes, &job.Consumes},
+			{"manifest", template.Manifest, &job.Properties},
+		} {
+			evaluated, err := evaluator.EvaluateManifest(snippet.source, jobType.Name)
+			if err != nil {
+				return nil, fmt.Errorf("could not evaluate the %s of template %q of job type %q: %w", snippet.field, template.Name, jobType.Name, err)
+			}
+			*snippet.target = evaluated
+		}
 
-	err := yaml.Unmarshal([]byte(snippet), &result)
-	if err != nil {
-		panic(err)
+		jobs = append(jobs, job)
 	}
 
-	return result
+	return jobs, nil
 }
 
 func generateVariables(templateVariables []proofing.Variable) []Variable {
@@ -3,6 +3,7 @@ package cargo_test
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 
 	. "github.com/pivotal-cf/kiln/internal/cargo"
 	"github.com/pivotal-cf/kiln/internal/cargo/opsman"
@@ -30,7 +31,7 @@ var _ = Describe("Generator", func() {
 			template, err := proofing.Parse(f)
 			Expect(err).NotTo(HaveOccurred())
 
-			manifest := generator.Execute(template, OpsManagerConfig{
+			manifest, err := generator.Execute(template, OpsManagerConfig{
 				DeploymentName: "some-product-name",
 				AvailabilityZones: []string{
 					"some-az-1",
@@ -59,6 +60,7 @@ var _ = Describe("Generator", func() {
 					},
 				},
 			})
+			Expect(err).NotTo(HaveOccurred())
 
 			actualManifest, err := yaml.Marshal(manifest)
 			Expect(err).NotTo(HaveOccurred())
@@ -68,5 +70,153 @@ var _ = Describe("Generator", func() {
 
 			Expect(actualManifest).To(HelpfullyMatchYAML(string(expectedManifest)))
 		})
+
+		Context("when the manifests contain property accessors", func() {
+			var template proofing.ProductTemplate
+
+			BeforeEach(func() {
+				var err error
+				template, err = proofing.Parse(strings.NewReader(`---
+stemcell_criteria:
+  os: some-stemcell-os
+  version: some-stemcell-version
+property_blueprints:
+- name: port
+  type: port
+  default: 8080
+- name: hostname
+  type: string
+job_types:
+- name: some-job-type-name
+  property_blueprints:
+  - name: log_level
+    type: string
+    default: info
+  templates:
+  - name: some-template-name
+    release: some-release-name
+    manifest: |
+      port: (( .properties.port.value ))
+      url: https://(( .properties.hostname.value )):(( .properties.port.value ))
+      log_level: (( .some-job-type-name.log_level.value ))
+      ip: (( .ip ))
+      client_secret: (( $self.uaa_client_secret ))
+`))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("evaluates them with the configured values and the defaults", func() {
+				manifest, err := generator.Execute(template, OpsManagerConfig{
+					Network: "some-network",
+					ResourceConfigs: []opsman.ResourceConfig{
+						{Name: "some-job-type-name", Instances: opsman.ResourceConfigInstances{Value: 1}, VMType: "some-vm-type", StaticIPs: []string{"10.0.0.1"}},
+					},
+					Properties: map[string]interface{}{
+						".properties.hostname": "example.com",
+					},
+					Self: map[string]interface{}{
+						"uaa_client_secret": "some-secret",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(manifest.Stemcells).To(Equal([]Stemcell{{Alias: "default", OS: "some-stemcell-os", Version: "some-stemcell-version"}}))
+				Expect(manifest.InstanceGroups).To(HaveLen(1))
+				Expect(manifest.InstanceGroups[0].VMType).To(Equal("some-vm-type"))
+				Expect(manifest.InstanceGroups[0].Networks).To(Equal([]InstanceGroupNetwork{{Name: "some-network", StaticIPs: []string{"10.0.0.1"}}}))
+				Expect(manifest.InstanceGroups[0].Jobs[0].Properties).To(Equal(map[interface{}]interface{}{
+					"port":          8080,
+					"url":           "https://example.com:8080",
+					"log_level":     "info",
+					"ip":            "10.0.0.1",
+					"client_secret": "some-secret",
+				}))
+			})
+
+			Context("when a required property has no value", func() {
+				It("returns an error", func() {
+					_, err := generator.Execute(template, OpsManagerConfig{
+						ResourceConfigs: []opsman.ResourceConfig{
+							{Name: "some-job-type-name", StaticIPs: []string{"10.0.0.1"}},
+						},
+						Self: map[string]interface{}{
+							"uaa_client_secret": "some-secret",
+						},
+					})
+					Expect(err).To(MatchError(`could not evaluate the manifest of template "some-template-name" of job type "some-job-type-name": property ".properties.hostname" is required but has no value`))
+				})
+			})
+
+			Context("when a configured property has no blueprint", func() {
+				It("returns an error", func() {
+					_, err := generator.Execute(template, OpsManagerConfig{
+						Properties: map[string]interface{}{
+							".properties.hostname": "example.com",
+							".properties.missing":  true,
+						},
+					})
+					Expect(err).To(MatchError("unknown properties: .properties.missing"))
+				})
+			})
+		})
+
+		Context("when a manifest is not valid YAML", func() {
+			It("returns an error", func() {
+				_, err := generator.Execute(proofing.ProductTemplate{
+					JobTypes: []proofing.JobType{
+						{Name: "some-job-type-name", Manifest: "%%%"},
+					},
+				}, OpsManagerConfig{})
+				Expect(err).To(MatchError(ContainSubstring(`could not evaluate the manifest of job type "some-job-type-name"`)))
+			})
+		})
+
+		Context("when no stemcell matches the stemcell criteria", func() {
+			It("returns an error", func() {
+				_, err := generator.Execute(proofing.ProductTemplate{
+					StemcellCriteria: proofing.StemcellCriteria{OS: "some-stemcell-os", Version: "some-stemcell-version"},
+				}, OpsManagerConfig{
+					Stemcells: []opsman.Stemcell{{Name: "other-stemcell-name", OS: "other-stemcell-os", Version: "other-stemcell-version"}},
+				})
+				Expect(err).To(MatchError("no stemcell matches the stemcell criteria some-stemcell-os some-stemcell-version"))
+			})
+		})
+	})
+
+	Describe("RuntimeConfigs", func() {
+		It("evaluates the runtime configs", func() {
+			template, err := proofing.Parse(strings.NewReader(`---
+property_blueprints:
+- name: hostname
+  type: string
+runtime_configs:
+- name: some-runtime-config
+  runtime_config: |
+    addons:
+    - name: some-addon
+      properties:
+        hostname: (( .properties.hostname.value ))
+`))
+			Expect(err).NotTo(HaveOccurred())
+
+			runtimeConfigs, err := generator.RuntimeConfigs(template, OpsManagerConfig{
+				Properties: map[string]interface{}{".properties.hostname": "example.com"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(runtimeConfigs).To(Equal([]RuntimeConfig{
+				{
+					Name: "some-runtime-config",
+					RuntimeConfig: map[interface{}]interface{}{
+						"addons": []interface{}{
+							map[interface{}]interface{}{
+								"name":       "some-addon",
+								"properties": map[interface{}]interface{}{"hostname": "example.com"},
+							},
+						},
+					},
+				},
+			}))
+		})
 	})
 })
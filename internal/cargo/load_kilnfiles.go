@@ -28,7 +28,7 @@ type KilnfileLoader struct {
 
 func (k KilnfileLoader) LoadKilnfiles(fs billy.Filesystem, kilnfilePath string, variablesFiles, variables []string) (Kilnfile, KilnfileLock, error) {
 	templateVariablesService := baking.NewTemplateVariablesService(fs)
-	templateVariables, err := templateVariablesService.FromPathsAndPairs(variablesFiles, variables)
+	templateVariables, _, err := templateVariablesService.FromPathsAndPairs(variablesFiles, variables, nil)
 	if err != nil {
 		return Kilnfile{}, KilnfileLock{}, fmt.Errorf("error processing --variable or --variables-file arguments - are you logged into lpass? (error: %w)", err)
 	}
@@ -9,6 +9,11 @@ type Manifest struct {
 	InstanceGroups []InstanceGroup `yaml:"instance_groups"`
 }
 
+type RuntimeConfig struct {
+	Name          string      `yaml:"name"`
+	RuntimeConfig interface{} `yaml:"runtime_config"`
+}
+
 type ReleaseManifest struct {
 	CompiledPackages []CompiledPackage `yaml:"compiled_packages"`
 	Name             string            `yaml:"name"`
@@ -48,13 +53,21 @@ type Variable struct {
 }
 
 type InstanceGroup struct {
-	Name       string             `yaml:"name"`
-	AZs        []string           `yaml:"azs"`
-	Lifecycle  string             `yaml:"lifecycle"`
-	Stemcell   string             `yaml:"stemcell"`
-	Instances  int                `yaml:"instances"`
-	Jobs       []InstanceGroupJob `yaml:"jobs"`
-	Properties interface{}        `yaml:"properties"`
+	Name           string                 `yaml:"name"`
+	AZs            []string               `yaml:"azs"`
+	Lifecycle      string                 `yaml:"lifecycle"`
+	Stemcell       string                 `yaml:"stemcell"`
+	Instances      int                    `yaml:"instances"`
+	VMType         string                 `yaml:"vm_type,omitempty"`
+	PersistentDisk int                    `yaml:"persistent_disk,omitempty"`
+	Networks       []InstanceGroupNetwork `yaml:"networks,omitempty"`
+	Jobs           []InstanceGroupJob     `yaml:"jobs"`
+	Properties     interface{}            `yaml:"properties"`
+}
+
+type InstanceGroupNetwork struct {
+	Name      string   `yaml:"name"`
+	StaticIPs []string `yaml:"static_ips,omitempty"`
 }
 
 type InstanceGroupJob struct {
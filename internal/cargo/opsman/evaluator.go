@@ -0,0 +1,419 @@
+package opsman
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/kiln/proofing"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	accessorExpressionPattern = regexp.MustCompile(`\(\(\s*(.*?)\s*\)\)`)
+	wholeAccessorPattern      = regexp.MustCompile(`^\(\(\s*(.*?)\s*\)\)$`)
+	parsedManifestPattern     = regexp.MustCompile(`^parsed_manifest\(\s*([\w-]+)\s*\)$`)
+)
+
+// Environment holds the values Ops Manager knows about a deployment beyond
+// its property values.
+type Environment struct {
+	DeploymentName    string
+	Network           string
+	AvailabilityZones []string
+
+	// Instances and IPs are keyed by job type name.
+	Instances map[string]int
+	IPs       map[string][]string
+
+	// Self and Director hold the $self.* and $director.* accessors, e.g.
+	// "uaa_client_secret" or "hostname". $self.deployment_name and
+	// $self.service_network default to DeploymentName and Network.
+	Self     map[string]interface{}
+	Director map[string]interface{}
+
+	// OtherProducts holds the values of accessors into other products keyed
+	// by the whole accessor, e.g. "..cf.properties.system_domain.value".
+	OtherProducts map[string]interface{}
+}
+
+// Evaluator evaluates the (( )) accessor expressions of Ops Manager in the
+// manifests of a product template.
+type Evaluator struct {
+	env        Environment
+	values     map[string]interface{}
+	required   map[string]bool
+	blueprints map[string]proofing.PropertyBlueprint
+	jobTypes   map[string]bool
+}
+
+// scope is where an expression is evaluated: in the manifests of a job type
+// and, inside the named manifest of a collection, for one of its records.
+type scope struct {
+	jobType string
+	record  map[interface{}]interface{}
+}
+
+// NewEvaluator returns an Evaluator for the product template. The values are
+// keyed by accessor without the trailing ".value", e.g.
+// ".properties.some-property" or ".some-job.some-property"; properties without
+// a value take the default of their blueprint.
+func NewEvaluator(template proofing.ProductTemplate, values map[string]interface{}, env Environment) (Evaluator, error) {
+	e := Evaluator{
+		env:        env,
+		values:     map[string]interface{}{},
+		required:   map[string]bool{},
+		blueprints: map[string]proofing.PropertyBlueprint{},
+		jobTypes:   map[string]bool{},
+	}
+
+	for _, pb := range template.AllPropertyBlueprints() {
+		e.values[pb.Property] = pb.Default
+		e.required[pb.Property] = pb.Required
+	}
+
+	for _, pb := range template.PropertyBlueprints {
+		e.addBlueprint(".properties", pb)
+	}
+
+	for _, jobType := range template.JobTypes {
+		e.jobTypes[jobType.Name] = true
+		for _, pb := range jobType.PropertyBlueprints {
+			e.addBlueprint("."+jobType.Name, pb)
+		}
+	}
+
+	var unknown []string
+	for property, value := range values {
+		if _, ok := e.values[property]; !ok {
+			unknown = append(unknown, property)
+			continue
+		}
+		e.values[property] = value
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return Evaluator{}, fmt.Errorf("unknown properties: %s", strings.Join(unknown, ", "))
+	}
+
+	return e, nil
+}
+
+func (e Evaluator) addBlueprint(prefix string, pb proofing.PropertyBlueprint) {
+	switch blueprint := pb.(type) {
+	case proofing.SelectorPropertyBlueprint:
+		e.blueprints[prefix+"."+blueprint.Name] = blueprint
+	case proofing.CollectionPropertyBlueprint:
+		e.blueprints[prefix+"."+blueprint.Name] = blueprint
+	}
+}
+
+// EvaluateManifest parses a manifest snippet and evaluates its accessors as
+// part of the job type, which may be empty for manifests outside job types.
+func (e Evaluator) EvaluateManifest(manifest, jobType string) (interface{}, error) {
+	var node interface{}
+
+	if manifest == "" {
+		manifest = "{}"
+	}
+
+	err := yaml.Unmarshal([]byte(manifest), &node)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Evaluate(node, jobType)
+}
+
+// Evaluate replaces the accessors in the strings of an unmarshalled manifest.
+// A string that is a single accessor is replaced by the value itself, keeping
+// its type, while accessors embedded in a longer string are replaced by their
+// string form, or removed when they have no value like an optional property.
+func (e Evaluator) Evaluate(node interface{}, jobType string) (interface{}, error) {
+	return e.evaluateNode(node, scope{jobType: jobType})
+}
+
+func (e Evaluator) evaluateNode(node interface{}, s scope) (interface{}, error) {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		result := map[interface{}]interface{}{}
+		for key, value := range n {
+			evaluated, err := e.evaluateNode(value, s)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = evaluated
+		}
+		return result, nil
+	case []interface{}:
+		var result []interface{}
+		for _, value := range n {
+			evaluated, err := e.evaluateNode(value, s)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, evaluated)
+		}
+		return result, nil
+	case string:
+		if match := wholeAccessorPattern.FindStringSubmatch(n); match != nil {
+			return e.evaluateExpression(match[1], s)
+		}
+
+		var evaluationErr error
+		result := accessorExpressionPattern.ReplaceAllStringFunc(n, func(expression string) string {
+			if evaluationErr != nil {
+				return ""
+			}
+
+			accessor := accessorExpressionPattern.FindStringSubmatch(expression)[1]
+			value, err := e.evaluateExpression(accessor, s)
+			if err != nil {
+				evaluationErr = err
+				return ""
+			}
+
+			switch value.(type) {
+			case nil:
+				return ""
+			case string, bool, int, int64, uint64, float64:
+				return fmt.Sprint(value)
+			default:
+				evaluationErr = fmt.Errorf("accessor %q in %q is not a string, number or boolean", accessor, n)
+				return ""
+			}
+		})
+		if evaluationErr != nil {
+			return nil, evaluationErr
+		}
+		return result, nil
+	default:
+		return node, nil
+	}
+}
+
+func (e Evaluator) evaluateExpression(accessor string, s scope) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(accessor, "$"):
+		return e.evaluateVariable(accessor)
+	case strings.HasPrefix(accessor, ".."):
+		value, ok := e.env.OtherProducts[accessor]
+		if !ok {
+			return nil, fmt.Errorf("no value for %q from another product", accessor)
+		}
+		return value, nil
+	case strings.HasPrefix(accessor, "current_record."):
+		if s.record == nil {
+			return nil, fmt.Errorf("%q is only available in the named manifests of a collection", accessor)
+		}
+		field := strings.TrimPrefix(accessor, "current_record.")
+		value, ok := s.record[field]
+		if !ok {
+			return nil, fmt.Errorf("the collection record has no field %q", field)
+		}
+		return value, nil
+	case accessor == ".ip":
+		ips, err := e.ips(s.jobType)
+		if err != nil {
+			return nil, err
+		}
+		return ips[0], nil
+	case accessor == ".network.name":
+		if e.env.Network == "" {
+			return nil, fmt.Errorf("no network for %q", accessor)
+		}
+		return e.env.Network, nil
+	case strings.HasPrefix(accessor, "."):
+		return e.evaluatePath(accessor, s)
+	default:
+		return nil, fmt.Errorf("unsupported accessor %q", accessor)
+	}
+}
+
+func (e Evaluator) evaluateVariable(accessor string) (interface{}, error) {
+	segments := strings.SplitN(accessor, ".", 2)
+	if len(segments) != 2 {
+		return nil, fmt.Errorf("unsupported accessor %q", accessor)
+	}
+
+	var variables map[string]interface{}
+	switch segments[0] {
+	case "$self":
+		variables = map[string]interface{}{
+			"deployment_name": e.env.DeploymentName,
+			"service_network": e.env.Network,
+		}
+		for key, value := range e.env.Self {
+			variables[key] = value
+		}
+	case "$director":
+		variables = e.env.Director
+	default:
+		return nil, fmt.Errorf("unsupported accessor %q", accessor)
+	}
+
+	value, ok := variables[segments[1]]
+	if !ok || value == "" {
+		return nil, fmt.Errorf("no value for %q", accessor)
+	}
+
+	return value, nil
+}
+
+// evaluatePath evaluates an accessor into a property, e.g.
+// ".properties.some-selector.selected_option.parsed_manifest(some-manifest)",
+// or into a job type, e.g. ".some-job.first_ip".
+func (e Evaluator) evaluatePath(accessor string, s scope) (interface{}, error) {
+	segments := strings.Split(strings.TrimPrefix(accessor, "."), ".")
+
+	for i := len(segments) - 1; i >= 2; i-- {
+		property := "." + strings.Join(segments[:i], ".")
+		if _, ok := e.values[property]; ok {
+			return e.evaluateProperty(property, segments[i:], s)
+		}
+	}
+
+	if len(segments) == 2 && e.jobTypes[segments[0]] {
+		return e.evaluateJobAccessor(segments[0], segments[1], accessor)
+	}
+
+	return nil, fmt.Errorf("unknown property in accessor %q", accessor)
+}
+
+func (e Evaluator) evaluateProperty(property string, attributes []string, s scope) (interface{}, error) {
+	value := e.values[property]
+	if value == nil && e.required[property] {
+		return nil, fmt.Errorf("property %q is required but has no value", property)
+	}
+
+	accessor := property + "." + strings.Join(attributes, ".")
+
+	switch blueprint := e.blueprints[property].(type) {
+	case proofing.SelectorPropertyBlueprint:
+		if attributes[0] == "selected_option" && len(attributes) == 2 {
+			option, err := selectedOption(blueprint, property, value)
+			if err != nil {
+				return nil, err
+			}
+
+			if attributes[1] == "name" {
+				return option.Name, nil
+			}
+
+			return e.evaluateNamedManifest(option.NamedManifests, attributes[1], accessor, s)
+		}
+	case proofing.CollectionPropertyBlueprint:
+		if len(attributes) == 1 && parsedManifestPattern.MatchString(attributes[0]) {
+			records, ok := value.([]interface{})
+			if !ok && value != nil {
+				return nil, fmt.Errorf("property %q is not a list of records", property)
+			}
+
+			var results []interface{}
+			for _, record := range records {
+				fields, ok := record.(map[interface{}]interface{})
+				if !ok {
+					return nil, fmt.Errorf("property %q is not a list of records", property)
+				}
+
+				result, err := e.evaluateNamedManifest(blueprint.NamedManifests, attributes[0], accessor, scope{jobType: s.jobType, record: fields})
+				if err != nil {
+					return nil, err
+				}
+				results = append(results, result)
+			}
+
+			return results, nil
+		}
+	}
+
+	if len(attributes) != 1 {
+		return nil, fmt.Errorf("unsupported accessor %q", accessor)
+	}
+
+	// Secrets are configured as {secret: ...} and read as .value, while
+	// credentials are read by their fields, e.g. .identity or .password.
+	fields, _ := value.(map[interface{}]interface{})
+	if attributes[0] == "value" {
+		if secret, ok := fields["secret"]; ok && len(fields) == 1 {
+			return secret, nil
+		}
+		return value, nil
+	}
+
+	field, ok := fields[attributes[0]]
+	if !ok {
+		return nil, fmt.Errorf("property %q has no field %q", property, attributes[0])
+	}
+
+	return field, nil
+}
+
+func (e Evaluator) evaluateNamedManifest(namedManifests []proofing.NamedManifest, call, accessor string, s scope) (interface{}, error) {
+	match := parsedManifestPattern.FindStringSubmatch(call)
+	if match == nil {
+		return nil, fmt.Errorf("unsupported accessor %q", accessor)
+	}
+
+	for _, namedManifest := range namedManifests {
+		if namedManifest.Name != match[1] {
+			continue
+		}
+
+		var node interface{}
+		err := yaml.Unmarshal([]byte(namedManifest.Manifest), &node)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse named manifest %q for %q: %w", match[1], accessor, err)
+		}
+
+		return e.evaluateNode(node, s)
+	}
+
+	return nil, fmt.Errorf("no named manifest %q for %q", match[1], accessor)
+}
+
+func (e Evaluator) evaluateJobAccessor(jobType, attribute, accessor string) (interface{}, error) {
+	switch attribute {
+	case "name":
+		return jobType, nil
+	case "instances":
+		return e.env.Instances[jobType], nil
+	case "availability_zones":
+		return e.env.AvailabilityZones, nil
+	case "ips":
+		return e.ips(jobType)
+	case "first_ip":
+		ips, err := e.ips(jobType)
+		if err != nil {
+			return nil, err
+		}
+		return ips[0], nil
+	default:
+		return nil, fmt.Errorf("unknown property in accessor %q", accessor)
+	}
+}
+
+func (e Evaluator) ips(jobType string) ([]string, error) {
+	if jobType == "" {
+		return nil, fmt.Errorf("IPs are only available in the manifests of a job type")
+	}
+
+	ips := e.env.IPs[jobType]
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no IPs for job type %q", jobType)
+	}
+
+	return ips, nil
+}
+
+func selectedOption(blueprint proofing.SelectorPropertyBlueprint, property string, value interface{}) (proofing.SelectorPropertyOptionTemplate, error) {
+	for _, option := range blueprint.OptionTemplates {
+		if value == option.SelectValue || value == option.Name {
+			return option, nil
+		}
+	}
+
+	return proofing.SelectorPropertyOptionTemplate{}, fmt.Errorf("property %q has no option %v", property, value)
+}
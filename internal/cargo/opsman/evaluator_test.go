@@ -0,0 +1,254 @@
+package opsman_test
+
+import (
+	"strings"
+
+	"github.com/pivotal-cf/kiln/proofing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/internal/cargo/opsman"
+)
+
+var _ = Describe("Evaluator", func() {
+	const metadata = `---
+property_blueprints:
+- name: hostname
+  type: string
+- name: port
+  type: port
+  default: 8080
+- name: optional
+  type: string
+  optional: true
+- name: admin
+  type: simple_credentials
+- name: token
+  type: secret
+- name: tls
+  type: selector
+  default: Enabled
+  option_templates:
+  - name: enabled
+    select_value: Enabled
+    property_blueprints:
+    - name: ciphers
+      type: string
+      default: strong
+    named_manifests:
+    - name: tls
+      manifest: |
+        enabled: true
+        ciphers: (( .properties.tls.enabled.ciphers.value ))
+  - name: disabled
+    select_value: Disabled
+    named_manifests:
+    - name: tls
+      manifest: |
+        enabled: false
+- name: users
+  type: collection
+  property_blueprints:
+  - name: name
+    type: string
+  named_manifests:
+  - name: user
+    manifest: |
+      name: (( current_record.name ))
+      domain: (( .properties.hostname.value ))
+job_types:
+- name: some-job
+  property_blueprints:
+  - name: log_level
+    type: string
+    default: info
+`
+
+	var (
+		template proofing.ProductTemplate
+		values   map[string]interface{}
+		env      Environment
+	)
+
+	BeforeEach(func() {
+		var err error
+		template, err = proofing.Parse(strings.NewReader(metadata))
+		Expect(err).NotTo(HaveOccurred())
+
+		values = map[string]interface{}{
+			".properties.hostname": "example.com",
+			".properties.admin":    map[interface{}]interface{}{"identity": "admin", "password": "some-password"},
+			".properties.token":    map[interface{}]interface{}{"secret": "some-token"},
+			".properties.users": []interface{}{
+				map[interface{}]interface{}{"name": "alice"},
+				map[interface{}]interface{}{"name": "bob"},
+			},
+		}
+
+		env = Environment{
+			DeploymentName:    "some-deployment",
+			Network:           "some-network",
+			AvailabilityZones: []string{"z1"},
+			Instances:         map[string]int{"some-job": 2},
+			IPs:               map[string][]string{"some-job": {"10.0.0.1", "10.0.0.2"}},
+			Self:              map[string]interface{}{"uaa_client_secret": "some-secret"},
+			Director:          map[string]interface{}{"hostname": "director.example.com"},
+			OtherProducts:     map[string]interface{}{"..cf.properties.system_domain.value": "sys.example.com"},
+		}
+	})
+
+	evaluate := func(manifest, jobType string) (interface{}, error) {
+		evaluator, err := NewEvaluator(template, values, env)
+		Expect(err).NotTo(HaveOccurred())
+
+		return evaluator.EvaluateManifest(manifest, jobType)
+	}
+
+	It("evaluates property values and defaults, keeping their types", func() {
+		result, err := evaluate(`
+hostname: (( .properties.hostname.value ))
+port: (( .properties.port.value ))
+url: https://(( .properties.hostname.value )):(( .properties.port.value ))/(( .properties.optional.value ))
+log_level: (( .some-job.log_level.value ))
+`, "some-job")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[interface{}]interface{}{
+			"hostname":  "example.com",
+			"port":      8080,
+			"url":       "https://example.com:8080/",
+			"log_level": "info",
+		}))
+	})
+
+	It("evaluates an optional property without a value to nil, or to an empty string inside a longer string", func() {
+		result, err := evaluate(`
+optional: (( .properties.optional.value ))
+embedded: some-(( .properties.optional.value ))-suffix
+`, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[interface{}]interface{}{
+			"optional": nil,
+			"embedded": "some--suffix",
+		}))
+	})
+
+	It("evaluates credentials and secrets", func() {
+		result, err := evaluate(`
+user: (( .properties.admin.identity ))
+password: (( .properties.admin.password ))
+token: (( .properties.token.value ))
+`, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[interface{}]interface{}{
+			"user":     "admin",
+			"password": "some-password",
+			"token":    "some-token",
+		}))
+	})
+
+	It("evaluates the named manifests of the selected option", func() {
+		result, err := evaluate(`
+tls: (( .properties.tls.selected_option.parsed_manifest(tls) ))
+option: (( .properties.tls.selected_option.name ))
+`, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[interface{}]interface{}{
+			"tls":    map[interface{}]interface{}{"enabled": true, "ciphers": "strong"},
+			"option": "enabled",
+		}))
+
+		values[".properties.tls"] = "Disabled"
+		result, err = evaluate(`tls: (( .properties.tls.selected_option.parsed_manifest(tls) ))`, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[interface{}]interface{}{
+			"tls": map[interface{}]interface{}{"enabled": false},
+		}))
+	})
+
+	It("evaluates the named manifest of a collection for each record", func() {
+		result, err := evaluate(`users: (( .properties.users.parsed_manifest(user) ))`, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[interface{}]interface{}{
+			"users": []interface{}{
+				map[interface{}]interface{}{"name": "alice", "domain": "example.com"},
+				map[interface{}]interface{}{"name": "bob", "domain": "example.com"},
+			},
+		}))
+	})
+
+	It("evaluates job, network, $self, $director and other product accessors", func() {
+		result, err := evaluate(`
+ip: (( .ip ))
+ips: (( .some-job.ips ))
+first_ip: (( .some-job.first_ip ))
+instances: (( .some-job.instances ))
+network: (( .network.name ))
+deployment: (( $self.deployment_name ))
+client_secret: (( $self.uaa_client_secret ))
+director: (( $director.hostname ))
+system_domain: (( ..cf.properties.system_domain.value ))
+`, "some-job")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[interface{}]interface{}{
+			"ip":            "10.0.0.1",
+			"ips":           []string{"10.0.0.1", "10.0.0.2"},
+			"first_ip":      "10.0.0.1",
+			"instances":     2,
+			"network":       "some-network",
+			"deployment":    "some-deployment",
+			"client_secret": "some-secret",
+			"director":      "director.example.com",
+			"system_domain": "sys.example.com",
+		}))
+	})
+
+	Context("when a value is configured for an unknown property", func() {
+		It("returns an error", func() {
+			values[".properties.missing"] = true
+
+			_, err := NewEvaluator(template, values, env)
+			Expect(err).To(MatchError("unknown properties: .properties.missing"))
+		})
+	})
+
+	DescribeTable("returns an error when an accessor cannot be evaluated",
+		func(manifest, jobType string, configured map[string]interface{}, message string) {
+			delete(values, ".properties.hostname")
+			for property, value := range configured {
+				values[property] = value
+			}
+
+			_, err := evaluate(manifest, jobType)
+			Expect(err).To(MatchError(message))
+		},
+		Entry("for an unknown property", "key: (( .properties.missing.value ))", "", nil, `unknown property in accessor ".properties.missing.value"`),
+		Entry("for a required property without a value", "key: (( .properties.hostname.value ))", "", nil, `property ".properties.hostname" is required but has no value`),
+		Entry("for an unsupported property accessor", "key: (( .properties.port.value.extra ))", "", nil, `unsupported accessor ".properties.port.value.extra"`),
+		Entry("for an unsupported variable", "key: (( $product.name ))", "", nil, `unsupported accessor "$product.name"`),
+		Entry("for an unsupported expression", "key: (( some-function() ))", "", nil, `unsupported accessor "some-function()"`),
+		Entry("for a missing credential field", "key: (( .properties.admin.cert_pem ))", "", nil, `property ".properties.admin" has no field "cert_pem"`),
+		Entry("for a selector value that matches no option",
+			"key: (( .properties.tls.selected_option.name ))", "",
+			map[string]interface{}{".properties.tls": "Maybe"},
+			`property ".properties.tls" has no option Maybe`),
+		Entry("for a missing named manifest", "key: (( .properties.tls.selected_option.parsed_manifest(missing) ))", "", nil, `no named manifest "missing" for ".properties.tls.selected_option.parsed_manifest(missing)"`),
+		Entry("for a collection value that is not a list",
+			"key: (( .properties.users.parsed_manifest(user) ))", "",
+			map[string]interface{}{".properties.users": "alice"},
+			`property ".properties.users" is not a list of records`),
+		Entry("for a collection value that is not a list of records",
+			"key: (( .properties.users.parsed_manifest(user) ))", "",
+			map[string]interface{}{".properties.users": []interface{}{"alice"}},
+			`property ".properties.users" is not a list of records`),
+		Entry("for IPs outside a job type", "key: (( .ip ))", "", nil, "IPs are only available in the manifests of a job type"),
+		Entry("for a missing $self value", "key: (( $self.missing ))", "", nil, `no value for "$self.missing"`),
+		Entry("for a missing $director value", "key: (( $director.ca_public_key ))", "", nil, `no value for "$director.ca_public_key"`),
+		Entry("for a missing other product value", "key: (( ..p-mysql.properties.port.value ))", "", nil, `no value for "..p-mysql.properties.port.value" from another product`),
+		Entry("for a record outside a collection", "key: (( current_record.name ))", "", nil, `"current_record.name" is only available in the named manifests of a collection`),
+		Entry("for a list embedded in a string", "key: ips=(( .some-job.ips ))", "some-job", nil, `accessor ".some-job.ips" in "ips=(( .some-job.ips ))" is not a string, number or boolean`),
+		Entry("for a named manifest embedded in a string",
+			"key: tls=(( .properties.tls.selected_option.parsed_manifest(tls) ))", "", nil,
+			`accessor ".properties.tls.selected_option.parsed_manifest(tls)" in "tls=(( .properties.tls.selected_option.parsed_manifest(tls) ))" is not a string, number or boolean`),
+	)
+})
@@ -0,0 +1,15 @@
+package opsman_test
+
+import (
+	"testing"
+
+	"github.com/matt-royal/biloba"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestOpsman(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithCustomReporters(t, "internal/cargo/opsman", biloba.DefaultReporters())
+}
@@ -1,8 +1,11 @@
 package opsman
 
 type ResourceConfig struct {
-	Name      string
-	Instances ResourceConfigInstances
+	Name           string
+	Instances      ResourceConfigInstances
+	VMType         string
+	PersistentDisk int
+	StaticIPs      []string
 }
 
 type ResourceConfigInstances struct {
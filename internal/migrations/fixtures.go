@@ -0,0 +1,160 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+const (
+	FixtureInput  = "input.json"
+	FixtureOutput = "output.json"
+)
+
+// Fixture is an installation before the migrations run and the installation
+// expected after they run.
+type Fixture struct {
+	Name   string
+	Input  []byte
+	Output []byte
+}
+
+// LoadFixtures reads every subdirectory of directory holding an input.json
+// and an output.json. It returns no fixtures when directory does not exist.
+func LoadFixtures(fs billy.Filesystem, directory string) ([]Fixture, error) {
+	_, err := fs.Stat(directory)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	infos, err := fs.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("could not read fixtures directory %q: %w", directory, err)
+	}
+
+	var fixtures []Fixture
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(directory, info.Name())
+
+		input, err := readFile(fs, filepath.Join(path, FixtureInput))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("could not read fixture %q: %w", info.Name(), err)
+		}
+
+		output, err := readFile(fs, filepath.Join(path, FixtureOutput))
+		if err != nil {
+			return nil, fmt.Errorf("could not read fixture %q: %w", info.Name(), err)
+		}
+
+		fixtures = append(fixtures, Fixture{Name: info.Name(), Input: input, Output: output})
+	}
+
+	sort.Slice(fixtures, func(i, j int) bool {
+		return fixtures[i].Name < fixtures[j].Name
+	})
+
+	return fixtures, nil
+}
+
+// Diff returns the differences between the expected and actual JSON, one per
+// JSON pointer at which they differ.
+func Diff(expected, actual []byte) ([]string, error) {
+	var e, a interface{}
+
+	err := json.Unmarshal(expected, &e)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the expected JSON: %w", err)
+	}
+
+	err = json.Unmarshal(actual, &a)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the actual JSON: %w", err)
+	}
+
+	return diff("", e, a), nil
+}
+
+func diff(pointer string, expected, actual interface{}) []string {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		var keys []string
+		for key := range e {
+			keys = append(keys, key)
+		}
+		for key := range a {
+			if _, ok := e[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		var differences []string
+		for _, key := range keys {
+			path := pointer + "/" + escape(key)
+
+			ev, inExpected := e[key]
+			av, inActual := a[key]
+			switch {
+			case !inActual:
+				differences = append(differences, fmt.Sprintf("%s: expected %s, got nothing", path, encode(ev)))
+			case !inExpected:
+				differences = append(differences, fmt.Sprintf("%s: unexpected %s", path, encode(av)))
+			default:
+				differences = append(differences, diff(path, ev, av)...)
+			}
+		}
+		return differences
+
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			break
+		}
+
+		var differences []string
+		for i := range e {
+			differences = append(differences, diff(fmt.Sprintf("%s/%d", pointer, i), e[i], a[i])...)
+		}
+		return differences
+	}
+
+	if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+
+	if pointer == "" {
+		pointer = "/"
+	}
+
+	return []string{fmt.Sprintf("%s: expected %s, got %s", pointer, encode(expected), encode(actual))}
+}
+
+func escape(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
+
+func encode(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,83 @@
+package migrations_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	"github.com/pivotal-cf/kiln/internal/migrations"
+)
+
+var _ = Describe("Fixtures", func() {
+	Describe("LoadFixtures", func() {
+		var fs billy.Filesystem
+
+		BeforeEach(func() {
+			fs = memfs.New()
+
+			Expect(util.WriteFile(fs, "tests/b-fixture/input.json", []byte(`{"b": 1}`), 0644)).To(Succeed())
+			Expect(util.WriteFile(fs, "tests/b-fixture/output.json", []byte(`{"b": 2}`), 0644)).To(Succeed())
+			Expect(util.WriteFile(fs, "tests/a-fixture/input.json", []byte(`{"a": 1}`), 0644)).To(Succeed())
+			Expect(util.WriteFile(fs, "tests/a-fixture/output.json", []byte(`{"a": 2}`), 0644)).To(Succeed())
+			Expect(util.WriteFile(fs, "tests/helpers/helper.js", []byte(``), 0644)).To(Succeed())
+			Expect(util.WriteFile(fs, "tests/migration_test.js", []byte(``), 0644)).To(Succeed())
+		})
+
+		It("returns the fixtures sorted by name", func() {
+			fixtures, err := migrations.LoadFixtures(fs, "tests")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fixtures).To(Equal([]migrations.Fixture{
+				{Name: "a-fixture", Input: []byte(`{"a": 1}`), Output: []byte(`{"a": 2}`)},
+				{Name: "b-fixture", Input: []byte(`{"b": 1}`), Output: []byte(`{"b": 2}`)},
+			}))
+		})
+
+		Context("when the directory does not exist", func() {
+			It("returns no fixtures", func() {
+				fixtures, err := migrations.LoadFixtures(fs, "missing")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fixtures).To(BeEmpty())
+			})
+		})
+
+		Context("when a fixture does not have an output.json", func() {
+			It("returns an error", func() {
+				Expect(util.WriteFile(fs, "tests/c-fixture/input.json", []byte(`{}`), 0644)).To(Succeed())
+
+				_, err := migrations.LoadFixtures(fs, "tests")
+				Expect(err).To(MatchError(ContainSubstring(`could not read fixture "c-fixture"`)))
+			})
+		})
+	})
+
+	Describe("Diff", func() {
+		It("returns the JSON pointer of each difference", func() {
+			differences, err := migrations.Diff(
+				[]byte(`{"properties": {".properties.a": {"value": 1}, ".properties.b/c": {"value": "x"}}, "list": [1, 2]}`),
+				[]byte(`{"properties": {".properties.a": {"value": 2}, ".properties.d": {"value": true}}, "list": [1, 3]}`),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(differences).To(Equal([]string{
+				`/list/1: expected 2, got 3`,
+				`/properties/.properties.a/value: expected 1, got 2`,
+				`/properties/.properties.b~1c: expected {"value":"x"}, got nothing`,
+				`/properties/.properties.d: unexpected {"value":true}`,
+			}))
+		})
+
+		It("returns no differences for equal JSON", func() {
+			differences, err := migrations.Diff([]byte(`{"a": [1, {"b": null}]}`), []byte(`{ "a" : [1, {"b": null}] }`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(differences).To(BeEmpty())
+		})
+
+		Context("when the actual JSON is not valid", func() {
+			It("returns an error", func() {
+				_, err := migrations.Diff([]byte(`{}`), []byte(`{`))
+				Expect(err).To(MatchError(ContainSubstring("could not parse the actual JSON")))
+			})
+		})
+	})
+})
@@ -0,0 +1,14 @@
+package migrations_test
+
+import (
+	"github.com/matt-royal/biloba"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMigrations(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithCustomReporters(t, "internal/migrations", biloba.DefaultReporters())
+}
@@ -0,0 +1,252 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robertkrimen/otto"
+	"github.com/robertkrimen/otto/ast"
+	"github.com/robertkrimen/otto/parser"
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+// TestsDirectory is the directory of a migrations directory that holds the
+// migration fixtures. It is not packaged in the tile.
+const TestsDirectory = "tests"
+
+var errTimeout = errors.New("timed out")
+
+type Migration struct {
+	Name   string
+	Path   string
+	Source string
+}
+
+// Load reads the JavaScript migrations in the directories, skipping
+// node_modules and tests, and returns them in file-name order, the order in
+// which Ops Manager applies the migrations of a tile.
+func Load(fs billy.Filesystem, directories []string) ([]Migration, error) {
+	var migrations []Migration
+	for _, directory := range directories {
+		paths, err := migrationPaths(fs, directory)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			source, err := readFile(fs, path)
+			if err != nil {
+				return nil, fmt.Errorf("could not read migration %q: %w", path, err)
+			}
+
+			migrations = append(migrations, Migration{
+				Name:   filepath.Base(path),
+				Path:   path,
+				Source: string(source),
+			})
+		}
+	}
+
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].Name < migrations[j].Name
+	})
+
+	return migrations, nil
+}
+
+func migrationPaths(fs billy.Filesystem, directory string) ([]string, error) {
+	_, err := fs.Stat(directory)
+	if err != nil {
+		return nil, fmt.Errorf("could not read migrations directory %q: %w", directory, err)
+	}
+
+	infos, err := fs.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("could not read migrations directory %q: %w", directory, err)
+	}
+
+	var paths []string
+	for _, info := range infos {
+		path := filepath.Join(directory, info.Name())
+
+		if info.IsDir() {
+			if info.Name() == "node_modules" || info.Name() == TestsDirectory {
+				continue
+			}
+
+			nested, err := migrationPaths(fs, path)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, nested...)
+			continue
+		}
+
+		if strings.HasSuffix(info.Name(), ".js") {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths, nil
+}
+
+type Runner struct {
+	timeout time.Duration
+}
+
+// NewRunner returns a Runner that stops a migration that runs longer than
+// timeout.
+func NewRunner(timeout time.Duration) Runner {
+	return Runner{timeout: timeout}
+}
+
+// Run applies the migrations in order to the installation JSON and returns
+// the migrated installation JSON. Each migration runs in its own JavaScript
+// VM and must export a migrate(input) function returning the installation.
+func (r Runner) Run(migrations []Migration, installation []byte) ([]byte, error) {
+	state := string(installation)
+	for _, migration := range migrations {
+		var err error
+		state, err = r.run(migration, state)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s failed: %w", migration.Name, err)
+		}
+	}
+
+	return []byte(state), nil
+}
+
+type result struct {
+	output string
+	err    error
+}
+
+// run stops the migration with the interrupt of its VM when it times out and
+// waits for the VM to stop. A migration that finishes before the VM handles
+// the interrupt returns its own result rather than a timeout.
+func (r Runner) run(migration Migration, input string) (string, error) {
+	vm := otto.New()
+	vm.Interrupt = make(chan func(), 1)
+
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err, ok := recovered.(error)
+				if !ok {
+					err = fmt.Errorf("%v", recovered)
+				}
+				done <- result{err: err}
+			}
+		}()
+
+		output, err := evaluate(vm, migration, input)
+		done <- result{output: output, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.output, res.err
+	case <-time.After(r.timeout):
+		vm.Interrupt <- func() {
+			panic(errTimeout)
+		}
+		res := <-done
+		if !errors.Is(res.err, errTimeout) {
+			return res.output, res.err
+		}
+
+		return "", fmt.Errorf("%w after %s", errTimeout, r.timeout)
+	}
+}
+
+// interruptibleLoops gives loops with an empty body an empty statement. Otto
+// only checks the interrupt of a VM before each statement and drops empty
+// blocks, so a loop like for (;;) {} could not otherwise be stopped.
+type interruptibleLoops struct{}
+
+func (v interruptibleLoops) Enter(node ast.Node) ast.Visitor {
+	switch loop := node.(type) {
+	case *ast.ForStatement:
+		addEmptyStatement(loop.Body)
+	case *ast.ForInStatement:
+		addEmptyStatement(loop.Body)
+	case *ast.WhileStatement:
+		addEmptyStatement(loop.Body)
+	case *ast.DoWhileStatement:
+		addEmptyStatement(loop.Body)
+	}
+
+	return v
+}
+
+func (interruptibleLoops) Exit(ast.Node) {}
+
+func addEmptyStatement(body ast.Statement) {
+	if block, ok := body.(*ast.BlockStatement); ok && len(block.List) == 0 {
+		block.List = []ast.Statement{&ast.EmptyStatement{Semicolon: block.RightBrace}}
+	}
+}
+
+func evaluate(vm *otto.Otto, migration Migration, input string) (string, error) {
+	_, err := vm.Run(`var exports = {}; var module = {exports: exports};`)
+	if err != nil {
+		return "", err
+	}
+
+	program, err := parser.ParseFile(nil, "", migration.Source, 0)
+	if err != nil {
+		return "", err
+	}
+	ast.Walk(interruptibleLoops{}, program)
+
+	_, err = vm.Run(program)
+	if err != nil {
+		return "", err
+	}
+
+	migrate, err := vm.Run(`module.exports.migrate`)
+	if err != nil {
+		return "", err
+	}
+
+	if !migrate.IsFunction() {
+		return "", errors.New("it does not export a migrate function")
+	}
+
+	parsed, err := vm.Call(`JSON.parse`, nil, input)
+	if err != nil {
+		return "", fmt.Errorf("could not parse the installation: %w", err)
+	}
+
+	output, err := migrate.Call(otto.UndefinedValue(), parsed)
+	if err != nil {
+		return "", err
+	}
+
+	if !output.IsObject() {
+		return "", errors.New("migrate did not return the installation")
+	}
+
+	encoded, err := vm.Call(`JSON.stringify`, nil, output)
+	if err != nil {
+		return "", err
+	}
+
+	return encoded.String(), nil
+}
+
+func readFile(fs billy.Filesystem, path string) ([]byte, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+}
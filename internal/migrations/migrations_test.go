@@ -0,0 +1,125 @@
+package migrations_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	"github.com/pivotal-cf/kiln/internal/migrations"
+)
+
+var _ = Describe("Migrations", func() {
+	Describe("Load", func() {
+		var fs billy.Filesystem
+
+		BeforeEach(func() {
+			fs = memfs.New()
+
+			for _, path := range []string{
+				"migrations/201711131111_second.js",
+				"migrations/nested/201701010000_first.js",
+				"migrations/node_modules/some-module.js",
+				"migrations/tests/migration_test.js",
+				"migrations/README.md",
+				"extra-migrations/201801010000_third.js",
+			} {
+				Expect(util.WriteFile(fs, path, []byte("// "+path), 0644)).To(Succeed())
+			}
+		})
+
+		It("returns the migrations of every directory in file-name order", func() {
+			loaded, err := migrations.Load(fs, []string{"extra-migrations", "migrations"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(loaded).To(Equal([]migrations.Migration{
+				{Name: "201701010000_first.js", Path: "migrations/nested/201701010000_first.js", Source: "// migrations/nested/201701010000_first.js"},
+				{Name: "201711131111_second.js", Path: "migrations/201711131111_second.js", Source: "// migrations/201711131111_second.js"},
+				{Name: "201801010000_third.js", Path: "extra-migrations/201801010000_third.js", Source: "// extra-migrations/201801010000_third.js"},
+			}))
+		})
+
+		Context("when a directory does not exist", func() {
+			It("returns an error", func() {
+				_, err := migrations.Load(fs, []string{"missing"})
+				Expect(err).To(MatchError(ContainSubstring(`could not read migrations directory "missing"`)))
+			})
+		})
+	})
+
+	Describe("Runner", func() {
+		var runner migrations.Runner
+
+		BeforeEach(func() {
+			runner = migrations.NewRunner(time.Second)
+		})
+
+		It("applies each migration to the output of the previous one", func() {
+			output, err := runner.Run([]migrations.Migration{
+				{Name: "first.js", Source: `exports.migrate = function(input) {
+  input.properties[".properties.renamed"] = input.properties[".properties.original"];
+  delete input.properties[".properties.original"];
+  return input;
+};`},
+				{Name: "second.js", Source: `module.exports = {
+  migrate: function(input) {
+    input.properties[".properties.renamed"].value += 1;
+    return input;
+  }
+};`},
+			}, []byte(`{"properties": {".properties.original": {"type": "integer", "value": 1}}}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchJSON(`{"properties": {".properties.renamed": {"type": "integer", "value": 2}}}`))
+		})
+
+		Context("when a migration throws", func() {
+			It("returns an error naming the migration", func() {
+				_, err := runner.Run([]migrations.Migration{
+					{Name: "broken.js", Source: `exports.migrate = function(input) { return input.missing.value; };`},
+				}, []byte(`{}`))
+				Expect(err).To(MatchError(ContainSubstring("migration broken.js failed: TypeError")))
+			})
+		})
+
+		Context("when a migration does not export migrate", func() {
+			It("returns an error", func() {
+				_, err := runner.Run([]migrations.Migration{{Name: "empty.js", Source: `var x = 1;`}}, []byte(`{}`))
+				Expect(err).To(MatchError("migration empty.js failed: it does not export a migrate function"))
+			})
+		})
+
+		Context("when a migration does not return the installation", func() {
+			It("returns an error", func() {
+				_, err := runner.Run([]migrations.Migration{
+					{Name: "forgetful.js", Source: `exports.migrate = function(input) { input.properties = {}; };`},
+				}, []byte(`{}`))
+				Expect(err).To(MatchError("migration forgetful.js failed: migrate did not return the installation"))
+			})
+		})
+
+		Context("when a migration runs longer than the timeout", func() {
+			It("stops it and returns an error", func() {
+				runner = migrations.NewRunner(50 * time.Millisecond)
+
+				_, err := runner.Run([]migrations.Migration{
+					{Name: "forever.js", Source: `exports.migrate = function(input) { for (;;) {} };`},
+				}, []byte(`{}`))
+				Expect(err).To(MatchError("migration forever.js failed: timed out after 50ms"))
+			})
+
+			It("stops loops with an empty body of any kind", func() {
+				runner = migrations.NewRunner(50 * time.Millisecond)
+
+				for _, loop := range []string{`while (true) {}`, `do {} while (true);`, `for (;;) { if (false) {} }`} {
+					_, err := runner.Run([]migrations.Migration{
+						{Name: "forever.js", Source: `exports.migrate = function(input) { ` + loop + ` };`},
+					}, []byte(`{}`))
+					Expect(err).To(MatchError("migration forever.js failed: timed out after 50ms"))
+				}
+			})
+		})
+	})
+})
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/pivotal-cf/kiln/proofing"
+)
+
+var renameMigration = template.Must(template.New("migration").Parse(`// Moves the values of renamed property blueprints to their new names.
+{{- range .}}
+// {{.From}} ({{.Type}}) -> {{.To}}
+{{- end}}
+exports.migrate = function(input) {
+  var properties = input.properties;
+{{range .}}
+  rename(properties, "{{.From}}", "{{.To}}");
+{{- end}}
+
+  return input;
+};
+
+// rename moves a property, and the properties nested under it such as the
+// properties of selector options, to a new name.
+function rename(properties, from, to) {
+  Object.keys(properties).forEach(function(key) {
+    if (key === from || key.indexOf(from + ".") === 0) {
+      properties[to + key.slice(from.length)] = properties[key];
+      delete properties[key];
+    }
+  });
+}
+`))
+
+// RenameMigration returns the source of a migration that moves the value of
+// each renamed property blueprint to its new name.
+func RenameMigration(renames []proofing.PropertyRename) []byte {
+	var b bytes.Buffer
+	err := renameMigration.Execute(&b, renames)
+	if err != nil {
+		panic(err) // NOTE: this cannot happen, the template only reads strings
+	}
+
+	return b.Bytes()
+}
+
+// NextName returns a migration file name for description with the timestamp
+// of now, or of a minute after the latest existing migration when now would
+// not sort after it.
+func NextName(existing []Migration, now time.Time, description string) string {
+	next := now.UTC().Truncate(time.Minute)
+
+	for _, migration := range existing {
+		match := migrationName.FindStringSubmatch(migration.Name)
+		if match == nil {
+			continue
+		}
+
+		timestamp, err := time.Parse(timestampLayout, match[1])
+		if err != nil {
+			continue
+		}
+
+		if !next.After(timestamp) {
+			next = timestamp.Add(time.Minute)
+		}
+	}
+
+	return fmt.Sprintf("%s_%s.js", next.Format(timestampLayout), description)
+}
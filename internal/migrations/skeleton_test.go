@@ -0,0 +1,71 @@
+package migrations_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/kiln/internal/migrations"
+	"github.com/pivotal-cf/kiln/proofing"
+)
+
+var _ = Describe("Skeleton", func() {
+	Describe("RenameMigration", func() {
+		It("returns a migration that moves each renamed property and its nested properties", func() {
+			source := migrations.RenameMigration([]proofing.PropertyRename{
+				{From: ".properties.old-name", To: ".properties.new-name", Type: "string"},
+				{From: ".properties.old-selector", To: ".properties.new-selector", Type: "selector"},
+			})
+			Expect(string(source)).To(HavePrefix(`// Moves the values of renamed property blueprints to their new names.
+// .properties.old-name (string) -> .properties.new-name
+// .properties.old-selector (selector) -> .properties.new-selector
+exports.migrate = function(input) {
+  var properties = input.properties;
+
+  rename(properties, ".properties.old-name", ".properties.new-name");
+  rename(properties, ".properties.old-selector", ".properties.new-selector");
+
+  return input;
+};
+`))
+
+			output, err := migrations.NewRunner(time.Second).Run([]migrations.Migration{{Name: "rename.js", Source: string(source)}}, []byte(`{
+  "properties": {
+    ".properties.old-name": {"type": "string", "value": "some-value"},
+    ".properties.old-name-suffix": {"type": "string", "value": "other-value"},
+    ".properties.old-selector": {"type": "selector", "value": "some-option"},
+    ".properties.old-selector.some-option.nested": {"type": "integer", "value": 1}
+  }
+}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchJSON(`{
+  "properties": {
+    ".properties.new-name": {"type": "string", "value": "some-value"},
+    ".properties.old-name-suffix": {"type": "string", "value": "other-value"},
+    ".properties.new-selector": {"type": "selector", "value": "some-option"},
+    ".properties.new-selector.some-option.nested": {"type": "integer", "value": 1}
+  }
+}`))
+		})
+	})
+
+	Describe("NextName", func() {
+		now := time.Date(2020, 3, 4, 15, 39, 27, 0, time.UTC)
+
+		It("uses the current time as the timestamp", func() {
+			name := migrations.NextName([]migrations.Migration{{Name: "201711131111_first.js"}}, now, "rename_properties")
+			Expect(name).To(Equal("202003041539_rename_properties.js"))
+		})
+
+		Context("when an existing migration does not sort before the current time", func() {
+			It("uses the minute after the latest migration", func() {
+				name := migrations.NextName([]migrations.Migration{
+					{Name: "202003041539_same_minute.js"},
+					{Name: "some_migration.js"},
+				}, now, "rename_properties")
+				Expect(name).To(Equal("202003041540_rename_properties.js"))
+			})
+		})
+	})
+})
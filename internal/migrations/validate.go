@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TileDirectory is the directory of a tile that holds its migrations.
+const TileDirectory = "migrations/v1"
+
+const timestampLayout = "200601021504"
+
+var migrationName = regexp.MustCompile(`^(\d{12})_.+\.js$`)
+
+// Validate checks that the migrations are packaged under unique names, that
+// each name starts with a YYYYMMDDHHMM timestamp and an underscore, that no
+// two migrations share a timestamp, and that every migration not in shipped,
+// the names of the migrations of a previous tile, sorts after all of them,
+// since Ops Manager applies migrations in name order.
+func Validate(migrations []Migration, shipped []string) error {
+	var problems []string
+
+	paths := map[string]string{}
+	timestamps := map[string]string{}
+	for _, migration := range migrations {
+		if other, ok := paths[migration.Name]; ok {
+			problems = append(problems, fmt.Sprintf("%s and %s are both packaged as %s", other, migration.Path, path.Join(TileDirectory, migration.Name)))
+			continue
+		}
+		paths[migration.Name] = migration.Path
+
+		match := migrationName.FindStringSubmatch(migration.Name)
+		if match == nil {
+			problems = append(problems, fmt.Sprintf("%s does not start with a YYYYMMDDHHMM timestamp and an underscore", migration.Path))
+			continue
+		}
+
+		if _, err := time.Parse(timestampLayout, match[1]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s does not start with a valid timestamp: %s", migration.Path, match[1]))
+			continue
+		}
+
+		if other, ok := timestamps[match[1]]; ok {
+			problems = append(problems, fmt.Sprintf("%s and %s have the same timestamp %s", other, migration.Path, match[1]))
+			continue
+		}
+		timestamps[match[1]] = migration.Path
+	}
+
+	if len(shipped) > 0 {
+		sorted := append([]string{}, shipped...)
+		sort.Strings(sorted)
+		latest := sorted[len(sorted)-1]
+
+		isShipped := map[string]bool{}
+		for _, name := range shipped {
+			isShipped[name] = true
+		}
+
+		for _, migration := range migrations {
+			if !isShipped[migration.Name] && migration.Name < latest {
+				problems = append(problems, fmt.Sprintf("%s sorts before %s, which shipped in the previous tile", migration.Path, latest))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package migrations_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/kiln/internal/migrations"
+)
+
+var _ = Describe("Validate", func() {
+	migration := func(path string) migrations.Migration {
+		return migrations.Migration{Name: path[len("migrations/"):], Path: path}
+	}
+
+	It("accepts uniquely named migrations with timestamps", func() {
+		err := migrations.Validate([]migrations.Migration{
+			migration("migrations/201711131111_first.js"),
+			migration("migrations/201801010000_second.js"),
+		}, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports every problem", func() {
+		err := migrations.Validate([]migrations.Migration{
+			migration("migrations/201302300000_invalid-day.js"),
+			migration("migrations/201313010000_invalid-month.js"),
+			migration("migrations/201711131111_first.js"),
+			{Name: "201711131111_first.js", Path: "other-migrations/201711131111_first.js"},
+			migration("migrations/201711131111_same-timestamp.js"),
+			migration("migrations/some_migration.js"),
+		}, nil)
+		Expect(err).To(MatchError(`migrations/201302300000_invalid-day.js does not start with a valid timestamp: 201302300000
+migrations/201313010000_invalid-month.js does not start with a valid timestamp: 201313010000
+migrations/201711131111_first.js and other-migrations/201711131111_first.js are both packaged as migrations/v1/201711131111_first.js
+migrations/201711131111_first.js and migrations/201711131111_same-timestamp.js have the same timestamp 201711131111
+migrations/some_migration.js does not start with a YYYYMMDDHHMM timestamp and an underscore`))
+	})
+
+	Context("when migrations shipped in a previous tile", func() {
+		It("reports new migrations that sort before the shipped ones", func() {
+			err := migrations.Validate([]migrations.Migration{
+				migration("migrations/201701010000_new-but-early.js"),
+				migration("migrations/201711131111_shipped.js"),
+				migration("migrations/201801010000_shipped.js"),
+				migration("migrations/201901010000_new.js"),
+			}, []string{"201801010000_shipped.js", "201711131111_shipped.js"})
+			Expect(err).To(MatchError("migrations/201701010000_new-but-early.js sorts before 201801010000_shipped.js, which shipped in the previous tile"))
+		})
+	})
+})
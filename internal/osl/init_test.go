@@ -0,0 +1,14 @@
+package osl_test
+
+import (
+	"github.com/matt-royal/biloba"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestOSL(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithCustomReporters(t, "internal/osl", biloba.DefaultReporters())
+}
@@ -0,0 +1,138 @@
+package osl
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+// LicenseArchive is the name of the archive of licenses in a BOSH release
+// tarball.
+const LicenseArchive = "license.tgz"
+
+const separator = "================================================================================"
+
+// Release holds the license files of a BOSH release.
+type Release struct {
+	Name    string
+	Version string
+	Files   []File
+}
+
+type File struct {
+	Name     string
+	Contents []byte
+}
+
+// ReadLicenses returns the regular files of the license archive in a release
+// tarball sorted by name. It returns no files when the release does not have
+// a license archive.
+func ReadLicenses(fs billy.Filesystem, releaseTarball string) ([]File, error) {
+	file, err := fs.Open(releaseTarball)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %q: %w", releaseTarball, err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read %q: %w", releaseTarball, err)
+		}
+
+		if filepath.Base(header.Name) != LicenseArchive {
+			continue
+		}
+
+		files, err := readArchive(tr)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s in %q: %w", LicenseArchive, releaseTarball, err)
+		}
+
+		return files, nil
+	}
+}
+
+func readArchive(r io.Reader) ([]File, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	var files []File
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		contents, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, File{Name: path.Clean(header.Name), Contents: contents})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	return files, nil
+}
+
+// Document returns an open source license document for a version of a
+// product with a section for each release, sorted by release name and
+// version.
+func Document(product, version string, releases []Release) []byte {
+	sorted := make([]Release, len(releases))
+	copy(sorted, releases)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Open Source License for %s %s\n\n", product, version)
+	fmt.Fprintf(&b, "This document contains the licenses of the open source software in the BOSH releases of %s %s.\n", product, version)
+
+	for _, release := range sorted {
+		fmt.Fprintf(&b, "\n%s\n%s %s\n%s\n", separator, release.Name, release.Version, separator)
+
+		for _, file := range release.Files {
+			fmt.Fprintf(&b, "\n--- %s ---\n\n%s\n", file.Name, strings.TrimRight(string(file.Contents), "\n"))
+		}
+	}
+
+	return b.Bytes()
+}
@@ -0,0 +1,119 @@
+package osl_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	"github.com/pivotal-cf/kiln/internal/osl"
+)
+
+var _ = Describe("OSL", func() {
+	tgz := func(files map[string][]byte) []byte {
+		var b bytes.Buffer
+		gw := gzip.NewWriter(&b)
+		tw := tar.NewWriter(gw)
+		for name, contents := range files {
+			Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := tw.Write(contents)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+		Expect(gw.Close()).To(Succeed())
+		return b.Bytes()
+	}
+
+	Describe("ReadLicenses", func() {
+		var fs billy.Filesystem
+
+		BeforeEach(func() {
+			fs = memfs.New()
+		})
+
+		It("returns the files in the license archive of the release", func() {
+			license := tgz(map[string][]byte{
+				"./NOTICE":  []byte("some-notice"),
+				"./LICENSE": []byte("some-license"),
+			})
+			release := tgz(map[string][]byte{
+				"./release.MF":  []byte("name: some-release"),
+				"./license.tgz": license,
+			})
+			Expect(util.WriteFile(fs, "some-release.tgz", release, 0644)).To(Succeed())
+
+			files, err := osl.ReadLicenses(fs, "some-release.tgz")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal([]osl.File{
+				{Name: "LICENSE", Contents: []byte("some-license")},
+				{Name: "NOTICE", Contents: []byte("some-notice")},
+			}))
+		})
+
+		Context("when the release does not have a license archive", func() {
+			It("returns no files", func() {
+				release := tgz(map[string][]byte{"./release.MF": []byte("name: some-release")})
+				Expect(util.WriteFile(fs, "some-release.tgz", release, 0644)).To(Succeed())
+
+				files, err := osl.ReadLicenses(fs, "some-release.tgz")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(BeEmpty())
+			})
+		})
+
+		Context("when the license archive is not gzipped", func() {
+			It("returns an error", func() {
+				release := tgz(map[string][]byte{"./license.tgz": []byte("not-gzipped")})
+				Expect(util.WriteFile(fs, "some-release.tgz", release, 0644)).To(Succeed())
+
+				_, err := osl.ReadLicenses(fs, "some-release.tgz")
+				Expect(err).To(MatchError(ContainSubstring(`could not read license.tgz in "some-release.tgz"`)))
+			})
+		})
+	})
+
+	Describe("Document", func() {
+		It("groups the licenses by release and version", func() {
+			document := osl.Document("some-product", "1.2", []osl.Release{
+				{Name: "some-release", Version: "2.0.0", Files: []osl.File{{Name: "LICENSE", Contents: []byte("license-2\n")}}},
+				{Name: "other-release", Version: "1.0.0", Files: []osl.File{{Name: "LICENSE", Contents: []byte("other-license")}}},
+				{Name: "some-release", Version: "1.0.0", Files: []osl.File{{Name: "LICENSE", Contents: []byte("license-1")}}},
+			})
+
+			separator := "================================================================================"
+			Expect(string(document)).To(Equal(`Open Source License for some-product 1.2
+
+This document contains the licenses of the open source software in the BOSH releases of some-product 1.2.
+
+` + separator + `
+other-release 1.0.0
+` + separator + `
+
+--- LICENSE ---
+
+other-license
+
+` + separator + `
+some-release 1.0.0
+` + separator + `
+
+--- LICENSE ---
+
+license-1
+
+` + separator + `
+some-release 2.0.0
+` + separator + `
+
+--- LICENSE ---
+
+license-2
+`))
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package sbom
+
+import "time"
+
+const cycloneDXSpecVersion = "1.4"
+
+type cycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber,omitempty"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp,omitempty"`
+	Tools     []cycloneDXTool    `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func newCycloneDXDocument(p product, input Input) cycloneDXDocument {
+	document := cycloneDXDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: cycloneDXSpecVersion,
+		Version:     1,
+		Metadata: cycloneDXMetadata{
+			Tools: []cycloneDXTool{{Name: "kiln", Version: input.KilnVersion}},
+			Component: cycloneDXComponent{
+				Type:    "application",
+				BOMRef:  "product",
+				Name:    p.Name,
+				Version: p.Version,
+			},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	if input.SerialNumber != "" {
+		document.SerialNumber = "urn:uuid:" + input.SerialNumber
+	}
+
+	if !input.Created.IsZero() {
+		document.Metadata.Timestamp = input.Created.UTC().Format(time.RFC3339)
+	}
+
+	for _, r := range p.Releases {
+		component := cycloneDXComponent{
+			Type:    "library",
+			BOMRef:  "release/" + r.Name,
+			Name:    r.Name,
+			Version: r.Version,
+			PURL:    r.purl(),
+		}
+
+		if r.SHA1 != "" {
+			component.Hashes = append(component.Hashes, cycloneDXHash{Algorithm: "SHA-1", Content: r.SHA1})
+		}
+		if r.SHA256 != "" {
+			component.Hashes = append(component.Hashes, cycloneDXHash{Algorithm: "SHA-256", Content: r.SHA256})
+		}
+
+		for _, property := range []cycloneDXProperty{
+			{Name: "kiln:file", Value: r.File},
+			{Name: "kiln:release_source", Value: r.ReleaseSource},
+			{Name: "kiln:remote_path", Value: r.RemotePath},
+		} {
+			if property.Value != "" {
+				component.Properties = append(component.Properties, property)
+			}
+		}
+
+		document.Components = append(document.Components, component)
+	}
+
+	if p.Stemcell.OS != "" {
+		document.Components = append(document.Components, cycloneDXComponent{
+			Type:    "operating-system",
+			BOMRef:  "stemcell",
+			Name:    p.Stemcell.OS,
+			Version: p.Stemcell.Version,
+			Properties: []cycloneDXProperty{
+				{Name: "kiln:stemcell_criteria", Value: "true"},
+			},
+		})
+	}
+
+	return document
+}
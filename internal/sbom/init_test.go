@@ -0,0 +1,14 @@
+package sbom_test
+
+import (
+	"github.com/matt-royal/biloba"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSBOM(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithCustomReporters(t, "internal/sbom", biloba.DefaultReporters())
+}
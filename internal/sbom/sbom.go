@@ -0,0 +1,147 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pivotal-cf/kiln/internal/cargo"
+	"github.com/pivotal-cf/kiln/proofing"
+)
+
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+)
+
+// Input is everything that goes into the software bill of materials of a
+// tile. The KilnfileLock and ReleaseSHA256s are optional, without them the
+// releases are listed without their release source, remote path and SHA256.
+type Input struct {
+	Metadata       []byte
+	KilnfileLock   cargo.KilnfileLock
+	ReleaseSHA256s map[string]string // keyed by release file name
+	KilnVersion    string
+	Created        time.Time
+	SerialNumber   string // a UUID that identifies this document
+}
+
+type product struct {
+	Name     string
+	Version  string
+	Stemcell proofing.StemcellCriteria
+	Releases []release
+}
+
+type release struct {
+	Name          string
+	Version       string
+	File          string
+	SHA1          string
+	SHA256        string
+	ReleaseSource string
+	RemotePath    string
+}
+
+// ValidateFormat returns an error unless format is one of the supported SBOM
+// formats.
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatCycloneDX, FormatSPDX:
+		return nil
+	default:
+		return fmt.Errorf("unsupported SBOM format %q, expected %s or %s", format, FormatCycloneDX, FormatSPDX)
+	}
+}
+
+// FileName returns the conventional file name of an SBOM in the given format.
+func FileName(format string) string {
+	if format == FormatSPDX {
+		return "sbom.spdx.json"
+	}
+
+	return "sbom.cdx.json"
+}
+
+// Generate returns the software bill of materials of the baked metadata in
+// input as a CycloneDX or SPDX JSON document.
+func Generate(format string, input Input) ([]byte, error) {
+	err := ValidateFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := newProduct(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if format == FormatSPDX {
+		document = newSPDXDocument(p, input)
+	} else {
+		document = newCycloneDXDocument(p, input)
+	}
+
+	var output bytes.Buffer
+	encoder := json.NewEncoder(&output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	err = encoder.Encode(document)
+	if err != nil {
+		return nil, err // NOTE: this cannot happen, the documents only contain strings
+	}
+
+	return output.Bytes(), nil
+}
+
+func newProduct(input Input) (product, error) {
+	productTemplate, err := proofing.Parse(bytes.NewReader(input.Metadata))
+	if err != nil {
+		return product{}, fmt.Errorf("could not parse metadata: %w", err)
+	}
+
+	p := product{
+		Name:     productTemplate.Name,
+		Version:  productTemplate.ProductVersion,
+		Stemcell: productTemplate.StemcellCriteria,
+	}
+
+	if p.Stemcell.OS == "" {
+		p.Stemcell.OS = input.KilnfileLock.Stemcell.OS
+		p.Stemcell.Version = input.KilnfileLock.Stemcell.Version
+	}
+
+	locks := map[string]cargo.ReleaseLock{}
+	for _, lock := range input.KilnfileLock.Releases {
+		locks[lock.Name] = lock
+	}
+
+	for _, r := range productTemplate.Releases {
+		rel := release{
+			Name:    r.Name,
+			Version: r.Version,
+			File:    r.File,
+			SHA1:    r.SHA1,
+			SHA256:  input.ReleaseSHA256s[r.File],
+		}
+
+		if lock, ok := locks[r.Name]; ok && lock.Version == r.Version {
+			rel.ReleaseSource = lock.RemoteSource
+			rel.RemotePath = lock.RemotePath
+			if rel.SHA1 == "" {
+				rel.SHA1 = lock.SHA1
+			}
+		}
+
+		p.Releases = append(p.Releases, rel)
+	}
+
+	return p, nil
+}
+
+func (r release) purl() string {
+	return fmt.Sprintf("pkg:generic/%s@%s", r.Name, r.Version)
+}
@@ -0,0 +1,197 @@
+package sbom_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/kiln/internal/cargo"
+	"github.com/pivotal-cf/kiln/internal/sbom"
+)
+
+var _ = Describe("Generate", func() {
+	const metadata = `---
+name: some-product
+product_version: 1.2.3
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "621.0"
+releases:
+- name: some_release
+  version: 1.0.0
+  file: some_release-1.0.0.tgz
+  sha1: some-sha1
+- name: other-release
+  version: 2.0.0
+  file: other-release-2.0.0.tgz
+  sha1: other-sha1
+`
+
+	var input sbom.Input
+
+	BeforeEach(func() {
+		input = sbom.Input{
+			Metadata: []byte(metadata),
+			KilnfileLock: cargo.KilnfileLock{
+				Releases: []cargo.ReleaseLock{
+					{Name: "some_release", Version: "1.0.0", SHA1: "some-sha1", RemoteSource: "some-bucket", RemotePath: "some_release-1.0.0.tgz"},
+					{Name: "other-release", Version: "1.9.0", SHA1: "old-sha1", RemoteSource: "some-bucket", RemotePath: "other-release-1.9.0.tgz"},
+				},
+			},
+			ReleaseSHA256s: map[string]string{"some_release-1.0.0.tgz": "some-sha256"},
+			KilnVersion:    "0.15.0",
+			Created:        time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			SerialNumber:   "4f0b0ad2-2b34-4d39-9a4b-5fbd47d05d2a",
+		}
+	})
+
+	Context("when the format is cyclonedx", func() {
+		It("lists the product, each release and the stemcell", func() {
+			document, err := sbom.Generate(sbom.FormatCycloneDX, input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(document).To(MatchJSON(`{
+				"bomFormat": "CycloneDX",
+				"specVersion": "1.4",
+				"serialNumber": "urn:uuid:4f0b0ad2-2b34-4d39-9a4b-5fbd47d05d2a",
+				"version": 1,
+				"metadata": {
+					"timestamp": "2020-01-02T03:04:05Z",
+					"tools": [{"name": "kiln", "version": "0.15.0"}],
+					"component": {"type": "application", "bom-ref": "product", "name": "some-product", "version": "1.2.3"}
+				},
+				"components": [
+					{
+						"type": "library",
+						"bom-ref": "release/some_release",
+						"name": "some_release",
+						"version": "1.0.0",
+						"purl": "pkg:generic/some_release@1.0.0",
+						"hashes": [
+							{"alg": "SHA-1", "content": "some-sha1"},
+							{"alg": "SHA-256", "content": "some-sha256"}
+						],
+						"properties": [
+							{"name": "kiln:file", "value": "some_release-1.0.0.tgz"},
+							{"name": "kiln:release_source", "value": "some-bucket"},
+							{"name": "kiln:remote_path", "value": "some_release-1.0.0.tgz"}
+						]
+					},
+					{
+						"type": "library",
+						"bom-ref": "release/other-release",
+						"name": "other-release",
+						"version": "2.0.0",
+						"purl": "pkg:generic/other-release@2.0.0",
+						"hashes": [{"alg": "SHA-1", "content": "other-sha1"}],
+						"properties": [{"name": "kiln:file", "value": "other-release-2.0.0.tgz"}]
+					},
+					{
+						"type": "operating-system",
+						"bom-ref": "stemcell",
+						"name": "ubuntu-xenial",
+						"version": "621.0",
+						"properties": [{"name": "kiln:stemcell_criteria", "value": "true"}]
+					}
+				]
+			}`))
+		})
+	})
+
+	Context("when the format is spdx", func() {
+		It("lists the product, each release and the stemcell", func() {
+			document, err := sbom.Generate(sbom.FormatSPDX, input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(document).To(MatchJSON(`{
+				"spdxVersion": "SPDX-2.3",
+				"dataLicense": "CC0-1.0",
+				"SPDXID": "SPDXRef-DOCUMENT",
+				"name": "some-product-1.2.3",
+				"documentNamespace": "https://network.pivotal.io/spdx/some-product-1.2.3-4f0b0ad2-2b34-4d39-9a4b-5fbd47d05d2a",
+				"creationInfo": {
+					"created": "2020-01-02T03:04:05Z",
+					"creators": ["Tool: kiln-0.15.0"]
+				},
+				"packages": [
+					{
+						"SPDXID": "SPDXRef-Product-some-product",
+						"name": "some-product",
+						"versionInfo": "1.2.3",
+						"downloadLocation": "NOASSERTION",
+						"filesAnalyzed": false,
+						"primaryPackagePurpose": "APPLICATION"
+					},
+					{
+						"SPDXID": "SPDXRef-Release-some-release",
+						"name": "some_release",
+						"versionInfo": "1.0.0",
+						"packageFileName": "some_release-1.0.0.tgz",
+						"downloadLocation": "NOASSERTION",
+						"filesAnalyzed": false,
+						"checksums": [
+							{"algorithm": "SHA1", "checksumValue": "some-sha1"},
+							{"algorithm": "SHA256", "checksumValue": "some-sha256"}
+						],
+						"sourceInfo": "release source some-bucket, remote path some_release-1.0.0.tgz",
+						"primaryPackagePurpose": "LIBRARY",
+						"externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:generic/some_release@1.0.0"}]
+					},
+					{
+						"SPDXID": "SPDXRef-Release-other-release",
+						"name": "other-release",
+						"versionInfo": "2.0.0",
+						"packageFileName": "other-release-2.0.0.tgz",
+						"downloadLocation": "NOASSERTION",
+						"filesAnalyzed": false,
+						"checksums": [{"algorithm": "SHA1", "checksumValue": "other-sha1"}],
+						"primaryPackagePurpose": "LIBRARY",
+						"externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:generic/other-release@2.0.0"}]
+					},
+					{
+						"SPDXID": "SPDXRef-Stemcell-ubuntu-xenial",
+						"name": "ubuntu-xenial",
+						"versionInfo": "621.0",
+						"downloadLocation": "NOASSERTION",
+						"filesAnalyzed": false,
+						"primaryPackagePurpose": "OPERATING-SYSTEM"
+					}
+				],
+				"relationships": [
+					{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-Product-some-product"},
+					{"spdxElementId": "SPDXRef-Product-some-product", "relationshipType": "CONTAINS", "relatedSpdxElement": "SPDXRef-Release-some-release"},
+					{"spdxElementId": "SPDXRef-Product-some-product", "relationshipType": "CONTAINS", "relatedSpdxElement": "SPDXRef-Release-other-release"},
+					{"spdxElementId": "SPDXRef-Product-some-product", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-Stemcell-ubuntu-xenial"}
+				]
+			}`))
+		})
+	})
+
+	Context("when the metadata does not have stemcell criteria", func() {
+		It("uses the stemcell criteria in the Kilnfile.lock", func() {
+			input.Metadata = []byte("name: some-product\n")
+			input.KilnfileLock.Stemcell = cargo.Stemcell{OS: "ubuntu-bionic", Version: "1.0"}
+
+			document, err := sbom.Generate(sbom.FormatCycloneDX, input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(document)).To(ContainSubstring(`"name": "ubuntu-bionic"`))
+		})
+	})
+
+	Context("when the format is not supported", func() {
+		It("returns an error", func() {
+			_, err := sbom.Generate("swid", input)
+			Expect(err).To(MatchError(`unsupported SBOM format "swid", expected cyclonedx or spdx`))
+		})
+	})
+
+	Context("when the metadata is not valid YAML", func() {
+		It("returns an error", func() {
+			input.Metadata = []byte("%%%")
+
+			_, err := sbom.Generate(sbom.FormatSPDX, input)
+			Expect(err).To(MatchError(ContainSubstring("could not parse metadata")))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package sbom
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/yaml.v2"
+
+	"github.com/pivotal-cf/kiln/builder"
+	"github.com/pivotal-cf/kiln/internal/cargo"
+	"github.com/pivotal-cf/kiln/internal/tile"
+)
+
+// Service gathers the Kilnfile.lock and release tarball SHA256s that go into
+// the software bill of materials of a tile.
+type Service struct {
+	fs          billy.Filesystem
+	kilnVersion string
+	now         func() time.Time
+}
+
+func NewService(fs billy.Filesystem, kilnVersion string) Service {
+	return Service{
+		fs:          fs,
+		kilnVersion: kilnVersion,
+		now:         time.Now,
+	}
+}
+
+// FromMetadata returns the SBOM of baked metadata. The SHA256 of each release
+// is taken from the release parts, as read by baking.ReleasesService, so
+// tarballs are not hashed again. The Kilnfile.lock next to kilnfile is read
+// unless kilnfile is empty.
+func (s Service) FromMetadata(format string, metadata []byte, kilnfile string, releases []builder.Part) ([]byte, error) {
+	lock, err := s.readKilnfileLock(kilnfile)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.generate(format, metadata, lock, releaseSHA256s(releases))
+}
+
+// FromTile returns the SBOM of the .pivotal file at tilePath, using the
+// SHA256 of each release tarball in the tile.
+func (s Service) FromTile(format, tilePath, kilnfile string) ([]byte, error) {
+	lock, err := s.readKilnfileLock(kilnfile)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := tile.Read(s.fs, tilePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read tile: %w", err)
+	}
+
+	sha256s := map[string]string{}
+	for _, f := range t.Files {
+		dir, file := path.Split(f.Name)
+		if dir == "releases/" {
+			sha256s[file] = f.SHA256
+		}
+	}
+
+	return s.generate(format, t.Metadata, lock, sha256s)
+}
+
+func (s Service) generate(format string, metadata []byte, lock cargo.KilnfileLock, sha256s map[string]string) ([]byte, error) {
+	return Generate(format, Input{
+		Metadata:       metadata,
+		KilnfileLock:   lock,
+		ReleaseSHA256s: sha256s,
+		KilnVersion:    s.kilnVersion,
+		Created:        s.now(),
+		SerialNumber:   uuid.New().String(),
+	})
+}
+
+func (s Service) readKilnfileLock(kilnfile string) (cargo.KilnfileLock, error) {
+	var lock cargo.KilnfileLock
+	if kilnfile == "" {
+		return lock, nil
+	}
+
+	lockPath := kilnfile + ".lock"
+
+	f, err := s.fs.Open(lockPath)
+	if err != nil {
+		return lock, fmt.Errorf("could not read %s: %w", lockPath, err)
+	}
+	defer f.Close()
+
+	contents, err := ioutil.ReadAll(f)
+	if err != nil {
+		return lock, fmt.Errorf("could not read %s: %w", lockPath, err)
+	}
+
+	err = yaml.Unmarshal(contents, &lock)
+	if err != nil {
+		return lock, fmt.Errorf("could not parse %s: %w", lockPath, err)
+	}
+
+	return lock, nil
+}
+
+// releaseSHA256s returns the SHA256 of each release tarball keyed by its
+// file name. Later releases win, matching how bake picks the tarballs it adds
+// to the tile.
+func releaseSHA256s(releases []builder.Part) map[string]string {
+	sha256s := map[string]string{}
+	for _, release := range releases {
+		manifest, ok := release.Metadata.(builder.ReleaseManifest)
+		if !ok || manifest.SHA256 == "" {
+			continue
+		}
+
+		sha256s[manifest.File] = manifest.SHA256
+	}
+
+	return sha256s
+}
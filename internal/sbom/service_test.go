@@ -0,0 +1,134 @@
+package sbom_test
+
+import (
+	"archive/zip"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+
+	"github.com/pivotal-cf/kiln/builder"
+	"github.com/pivotal-cf/kiln/internal/sbom"
+)
+
+var _ = Describe("Service", func() {
+	const (
+		metadata = `---
+name: some-product
+product_version: 1.2.3
+releases:
+- name: some-release
+  version: 1.0.0
+  file: some-release-1.0.0.tgz
+  sha1: some-sha1
+`
+		kilnfileLock = `---
+releases:
+- name: some-release
+  version: 1.0.0
+  sha1: some-sha1
+  remote_source: some-bucket
+  remote_path: some-release-1.0.0.tgz
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "621.0"
+`
+		// echo -n some-release-contents | sha256sum
+		releaseSHA256 = "751100c831f688f5a2b578a4801945c2807a813cca3bee77094c8212275c7b0d"
+	)
+
+	var (
+		fs      billy.Filesystem
+		service sbom.Service
+	)
+
+	type component struct {
+		Name       string `json:"name"`
+		Hashes     []struct{ Alg, Content string }
+		Properties []struct{ Name, Value string }
+	}
+
+	parse := func(document []byte) []component {
+		var bom struct {
+			Metadata struct {
+				Tools []struct{ Name, Version string }
+			}
+			Components []component
+		}
+		Expect(json.Unmarshal(document, &bom)).To(Succeed())
+		Expect(bom.Metadata.Tools[0].Version).To(Equal("0.15.0"))
+		return bom.Components
+	}
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		service = sbom.NewService(fs, "0.15.0")
+
+		Expect(util.WriteFile(fs, "Kilnfile.lock", []byte(kilnfileLock), 0644)).To(Succeed())
+	})
+
+	Describe("FromMetadata", func() {
+		It("reads the Kilnfile.lock and uses the SHA256 of the release parts", func() {
+			releases := []builder.Part{
+				{
+					File: "releases/nested/some-release-1.0.0.tgz",
+					Name: "some-release",
+					Metadata: builder.ReleaseManifest{
+						Name:    "some-release",
+						Version: "1.0.0",
+						File:    "some-release-1.0.0.tgz",
+						SHA256:  releaseSHA256,
+					},
+				},
+			}
+
+			document, err := service.FromMetadata(sbom.FormatCycloneDX, []byte(metadata), "Kilnfile", releases)
+			Expect(err).NotTo(HaveOccurred())
+
+			components := parse(document)
+			Expect(components).To(HaveLen(2))
+			Expect(components[0].Name).To(Equal("some-release"))
+			Expect(components[0].Hashes[1].Content).To(Equal(releaseSHA256))
+			Expect(components[0].Properties[1].Value).To(Equal("some-bucket"))
+			Expect(components[1].Name).To(Equal("ubuntu-xenial"))
+		})
+
+		Context("when the Kilnfile.lock does not exist", func() {
+			It("returns an error", func() {
+				_, err := service.FromMetadata(sbom.FormatCycloneDX, []byte(metadata), "missing/Kilnfile", nil)
+				Expect(err).To(MatchError(ContainSubstring("could not read missing/Kilnfile.lock")))
+			})
+		})
+	})
+
+	Describe("FromTile", func() {
+		It("uses the release tarballs in the tile", func() {
+			f, err := fs.Create("some-tile.pivotal")
+			Expect(err).NotTo(HaveOccurred())
+
+			zw := zip.NewWriter(f)
+			for name, contents := range map[string]string{
+				"metadata/metadata.yml":           metadata,
+				"releases/some-release-1.0.0.tgz": "some-release-contents",
+			} {
+				w, err := zw.Create(name)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = w.Write([]byte(contents))
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(zw.Close()).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+
+			document, err := service.FromTile(sbom.FormatCycloneDX, "some-tile.pivotal", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			components := parse(document)
+			Expect(components).To(HaveLen(1))
+			Expect(components[0].Hashes[1].Content).To(Equal(releaseSHA256))
+			Expect(components[0].Properties).To(HaveLen(1))
+		})
+	})
+})
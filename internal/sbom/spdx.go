@@ -0,0 +1,149 @@
+package sbom
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const spdxVersion = "SPDX-2.3"
+
+const spdxNoAssertion = "NOASSERTION"
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID                string            `json:"SPDXID"`
+	Name                  string            `json:"name"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	PackageFileName       string            `json:"packageFileName,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	SourceInfo            string            `json:"sourceInfo,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	Element        string `json:"spdxElementId"`
+	Type           string `json:"relationshipType"`
+	RelatedElement string `json:"relatedSpdxElement"`
+}
+
+var invalidSPDXIDCharacters = regexp.MustCompile(`[^a-zA-Z0-9.-]`)
+
+func spdxID(kind, name string) string {
+	return fmt.Sprintf("SPDXRef-%s-%s", kind, invalidSPDXIDCharacters.ReplaceAllString(name, "-"))
+}
+
+func newSPDXDocument(p product, input Input) spdxDocument {
+	created := input.Created
+	if created.IsZero() {
+		created = time.Unix(0, 0)
+	}
+
+	productID := spdxID("Product", p.Name)
+
+	document := spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              fmt.Sprintf("%s-%s", p.Name, p.Version),
+		DocumentNamespace: fmt.Sprintf("https://network.pivotal.io/spdx/%s-%s-%s", p.Name, p.Version, input.SerialNumber),
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: kiln-" + input.KilnVersion},
+		},
+		Packages: []spdxPackage{{
+			SPDXID:                productID,
+			Name:                  p.Name,
+			VersionInfo:           p.Version,
+			DownloadLocation:      spdxNoAssertion,
+			PrimaryPackagePurpose: "APPLICATION",
+		}},
+		Relationships: []spdxRelationship{{
+			Element:        "SPDXRef-DOCUMENT",
+			Type:           "DESCRIBES",
+			RelatedElement: productID,
+		}},
+	}
+
+	for _, r := range p.Releases {
+		pkg := spdxPackage{
+			SPDXID:                spdxID("Release", r.Name),
+			Name:                  r.Name,
+			VersionInfo:           r.Version,
+			PackageFileName:       r.File,
+			DownloadLocation:      spdxNoAssertion,
+			PrimaryPackagePurpose: "LIBRARY",
+			ExternalRefs: []spdxExternalRef{{
+				Category: "PACKAGE-MANAGER",
+				Type:     "purl",
+				Locator:  r.purl(),
+			}},
+		}
+
+		if r.SHA1 != "" {
+			pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: "SHA1", Value: r.SHA1})
+		}
+		if r.SHA256 != "" {
+			pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: "SHA256", Value: r.SHA256})
+		}
+
+		if r.ReleaseSource != "" {
+			pkg.SourceInfo = fmt.Sprintf("release source %s, remote path %s", r.ReleaseSource, r.RemotePath)
+		}
+
+		document.Packages = append(document.Packages, pkg)
+		document.Relationships = append(document.Relationships, spdxRelationship{
+			Element:        productID,
+			Type:           "CONTAINS",
+			RelatedElement: pkg.SPDXID,
+		})
+	}
+
+	if p.Stemcell.OS != "" {
+		stemcellID := spdxID("Stemcell", p.Stemcell.OS)
+
+		document.Packages = append(document.Packages, spdxPackage{
+			SPDXID:                stemcellID,
+			Name:                  p.Stemcell.OS,
+			VersionInfo:           p.Stemcell.Version,
+			DownloadLocation:      spdxNoAssertion,
+			PrimaryPackagePurpose: "OPERATING-SYSTEM",
+		})
+		document.Relationships = append(document.Relationships, spdxRelationship{
+			Element:        productID,
+			Type:           "DEPENDS_ON",
+			RelatedElement: stemcellID,
+		})
+	}
+
+	return document
+}
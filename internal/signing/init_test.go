@@ -0,0 +1,14 @@
+package signing_test
+
+import (
+	"github.com/matt-royal/biloba"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSigning(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithCustomReporters(t, "internal/signing", biloba.DefaultReporters())
+}
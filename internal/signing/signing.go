@@ -0,0 +1,190 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// SignatureExtension is appended to the path of a tile to name its detached
+// signature, for example tile.pivotal.sig.
+const SignatureExtension = ".sig"
+
+const (
+	pemPrivateKeyType = "PRIVATE KEY"
+	pemPublicKeyType  = "PUBLIC KEY"
+)
+
+// Sign returns a detached signature of the tile read from r.
+//
+// An ed25519 key, PEM encoded in PKCS #8 as written by
+// `openssl genpkey -algorithm ed25519`, makes a plain Ed25519 signature (not
+// Ed25519ph) of the 32 byte SHA256 digest of the tile, and the signature is
+// base64 encoded. An armored PGP private key produces an armored PGP
+// signature that `gpg --verify` can check.
+func Sign(privateKey []byte, r io.Reader) ([]byte, error) {
+	if IsPGPKey(privateKey) {
+		return signPGP(privateKey, r)
+	}
+
+	digest, err := sha256Digest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return SignSHA256(privateKey, digest)
+}
+
+// SignSHA256 returns the same ed25519 signature as Sign from the SHA256
+// digest of the tile, so a tile whose digest is already known is not read
+// again. PGP signatures hash the tile itself, so PGP keys are not supported.
+func SignSHA256(privateKey, digest []byte) ([]byte, error) {
+	if IsPGPKey(privateKey) {
+		return nil, errors.New("PGP keys sign the tile itself rather than its digest")
+	}
+
+	key, err := parseEd25519PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := ed25519.Sign(key, digest)
+
+	return []byte(base64.StdEncoding.EncodeToString(signature) + "\n"), nil
+}
+
+// Verify checks that signature is a detached signature of the tile read from
+// r made with the private key matching publicKey.
+func Verify(publicKey []byte, r io.Reader, signature []byte) error {
+	if IsPGPKey(publicKey) {
+		return verifyPGP(publicKey, r, signature)
+	}
+
+	key, err := parseEd25519PublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("signature is not base64 encoded: %w", err)
+	}
+
+	digest, err := sha256Digest(r)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(key, digest, decoded) {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
+
+func signPGP(privateKey []byte, r io.Reader) ([]byte, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse PGP private key: %w", err)
+	}
+
+	signer := entities[0]
+	if signer.PrivateKey == nil {
+		return nil, errors.New("PGP key does not contain a private key")
+	}
+
+	if signer.PrivateKey.Encrypted {
+		return nil, errors.New("PGP private key is protected by a passphrase, which is not supported")
+	}
+
+	var signature bytes.Buffer
+	err = openpgp.ArmoredDetachSign(&signature, signer, r, nil)
+	if err != nil {
+		return nil, err
+	}
+	signature.WriteString("\n")
+
+	return signature.Bytes(), nil
+}
+
+func verifyPGP(publicKey []byte, r io.Reader, signature []byte) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(publicKey))
+	if err != nil {
+		return fmt.Errorf("could not parse PGP public key: %w", err)
+	}
+
+	_, err = openpgp.CheckArmoredDetachedSignature(keyring, r, bytes.NewReader(signature))
+	if err != nil {
+		return fmt.Errorf("signature does not match: %w", err)
+	}
+
+	return nil
+}
+
+func parseEd25519PrivateKey(contents []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(contents)
+	if block == nil || block.Type != pemPrivateKeyType {
+		return nil, errors.New("key must be a PEM encoded ed25519 private key or an armored PGP private key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private key: %w", err)
+	}
+
+	ed25519Key, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is a %T, only ed25519 keys are supported", key)
+	}
+
+	return ed25519Key, nil
+}
+
+func parseEd25519PublicKey(contents []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(contents)
+	if block == nil || block.Type != pemPublicKeyType {
+		return nil, errors.New("key must be a PEM encoded ed25519 public key or an armored PGP public key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse public key: %w", err)
+	}
+
+	ed25519Key, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is a %T, only ed25519 keys are supported", key)
+	}
+
+	return ed25519Key, nil
+}
+
+// IsPGPKey reports whether contents is an armored PGP private or public key.
+func IsPGPKey(contents []byte) bool {
+	block, err := armor.Decode(bytes.NewReader(contents))
+	if err != nil {
+		return false
+	}
+
+	return block.Type == openpgp.PrivateKeyType || block.Type == openpgp.PublicKeyType
+}
+
+func sha256Digest(r io.Reader) ([]byte, error) {
+	hash := sha256.New()
+	_, err := io.Copy(hash, r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read tile: %w", err)
+	}
+
+	return hash.Sum(nil), nil
+}
@@ -0,0 +1,106 @@
+package signing_test
+
+import (
+	"crypto/sha256"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/kiln/internal/signing"
+	test_helpers "github.com/pivotal-cf/kiln/internal/test-helpers"
+)
+
+var _ = Describe("signing", func() {
+	const tile = "some-tile-contents"
+
+	for _, keyType := range []string{"ed25519", "PGP"} {
+		keyType := keyType
+
+		Context("with "+keyType+" keys", func() {
+			var privateKey, publicKey []byte
+
+			BeforeEach(func() {
+				var err error
+				if keyType == "ed25519" {
+					privateKey, publicKey, err = test_helpers.GenerateEd25519Keys()
+				} else {
+					privateKey, publicKey, err = test_helpers.GeneratePGPKeys()
+				}
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("verifies the signature of the signed tile", func() {
+				signature, err := signing.Sign(privateKey, strings.NewReader(tile))
+				Expect(err).NotTo(HaveOccurred())
+
+				err = signing.Verify(publicKey, strings.NewReader(tile), signature)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("rejects the signature of a different tile", func() {
+				signature, err := signing.Sign(privateKey, strings.NewReader(tile))
+				Expect(err).NotTo(HaveOccurred())
+
+				err = signing.Verify(publicKey, strings.NewReader("some-other-tile-contents"), signature)
+				Expect(err).To(MatchError(ContainSubstring("signature does not match")))
+			})
+
+			It("rejects a signature made with a different key", func() {
+				var otherPrivateKey []byte
+				var err error
+				if keyType == "ed25519" {
+					otherPrivateKey, _, err = test_helpers.GenerateEd25519Keys()
+				} else {
+					otherPrivateKey, _, err = test_helpers.GeneratePGPKeys()
+				}
+				Expect(err).NotTo(HaveOccurred())
+
+				signature, err := signing.Sign(otherPrivateKey, strings.NewReader(tile))
+				Expect(err).NotTo(HaveOccurred())
+
+				err = signing.Verify(publicKey, strings.NewReader(tile), signature)
+				Expect(err).To(MatchError(ContainSubstring("signature does not match")))
+			})
+		})
+	}
+
+	Describe("SignSHA256", func() {
+		It("makes the signature Sign makes of the tile with that digest", func() {
+			privateKey, publicKey, err := test_helpers.GenerateEd25519Keys()
+			Expect(err).NotTo(HaveOccurred())
+
+			digest := sha256.Sum256([]byte(tile))
+			signature, err := signing.SignSHA256(privateKey, digest[:])
+			Expect(err).NotTo(HaveOccurred())
+
+			err = signing.Verify(publicKey, strings.NewReader(tile), signature)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the private key is a PGP key", func() {
+			It("returns an error", func() {
+				privateKey, _, err := test_helpers.GeneratePGPKeys()
+				Expect(err).NotTo(HaveOccurred())
+
+				digest := sha256.Sum256([]byte(tile))
+				_, err = signing.SignSHA256(privateKey, digest[:])
+				Expect(err).To(MatchError("PGP keys sign the tile itself rather than its digest"))
+			})
+		})
+	})
+
+	Context("when the private key is not a supported key", func() {
+		It("returns an error", func() {
+			_, err := signing.Sign([]byte("not-a-key"), strings.NewReader(tile))
+			Expect(err).To(MatchError("key must be a PEM encoded ed25519 private key or an armored PGP private key"))
+		})
+	})
+
+	Context("when the public key is not a supported key", func() {
+		It("returns an error", func() {
+			err := signing.Verify([]byte("not-a-key"), strings.NewReader(tile), []byte("some-signature"))
+			Expect(err).To(MatchError("key must be a PEM encoded ed25519 public key or an armored PGP public key"))
+		})
+	})
+})
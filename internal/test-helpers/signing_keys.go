@@ -0,0 +1,66 @@
+package test_helpers
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// GenerateEd25519Keys returns a new PEM encoded ed25519 private and public key.
+func GenerateEd25519Keys() ([]byte, []byte, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}),
+		nil
+}
+
+// GeneratePGPKeys returns a new armored PGP private and public key.
+func GeneratePGPKeys() ([]byte, []byte, error) {
+	entity, err := openpgp.NewEntity("kiln", "test", "kiln@example.com", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var private bytes.Buffer
+	w, err := armor.Encode(&private, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = entity.SerializePrivate(w, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.Close()
+
+	var public bytes.Buffer
+	w, err = armor.Encode(&public, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = entity.Serialize(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.Close()
+
+	return private.Bytes(), public.Bytes(), nil
+}
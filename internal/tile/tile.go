@@ -0,0 +1,191 @@
+package tile
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+type Tile struct {
+	Metadata []byte
+	Files    []File
+}
+
+type File struct {
+	Name   string
+	Size   int64
+	SHA1   string
+	SHA256 string
+}
+
+// Read returns the metadata of the .pivotal file at tilePath along with the
+// size, SHA1 and SHA256 of every file it contains.
+func Read(fs billy.Filesystem, tilePath string) (Tile, error) {
+	var t Tile
+
+	err := walk(fs, tilePath, func(f *zip.File) error {
+		if f.FileInfo().IsDir() {
+			return nil
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("could not open %q in %q: %w", f.Name, tilePath, err)
+		}
+		defer r.Close()
+
+		hash := sha1.New()
+		hash256 := sha256.New()
+		var w io.Writer = io.MultiWriter(hash, hash256)
+
+		var metadata *bytes.Buffer
+		if t.Metadata == nil && isMetadata(f.Name) {
+			metadata = new(bytes.Buffer)
+			w = io.MultiWriter(hash, hash256, metadata)
+		}
+
+		size, err := io.Copy(w, r)
+		if err != nil {
+			return fmt.Errorf("could not read %q in %q: %w", f.Name, tilePath, err)
+		}
+
+		if metadata != nil {
+			t.Metadata = metadata.Bytes()
+		}
+
+		t.Files = append(t.Files, File{
+			Name:   f.Name,
+			Size:   size,
+			SHA1:   fmt.Sprintf("%x", hash.Sum(nil)),
+			SHA256: fmt.Sprintf("%x", hash256.Sum(nil)),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return Tile{}, err
+	}
+
+	if t.Metadata == nil {
+		return Tile{}, fmt.Errorf("could not find metadata in %q", tilePath)
+	}
+
+	return t, nil
+}
+
+// ReadMetadata returns the contents of the metadata file in the .pivotal file
+// at tilePath without reading any of the other files.
+func ReadMetadata(fs billy.Filesystem, tilePath string) ([]byte, error) {
+	var metadata []byte
+
+	err := walk(fs, tilePath, func(f *zip.File) error {
+		if metadata != nil || !isMetadata(f.Name) {
+			return nil
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("could not open %q in %q: %w", f.Name, tilePath, err)
+		}
+		defer r.Close()
+
+		metadata, err = ioutil.ReadAll(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata == nil {
+		return nil, fmt.Errorf("could not find metadata in %q", tilePath)
+	}
+
+	return metadata, nil
+}
+
+// ReadFiles returns the contents of the files in the .pivotal file at
+// tilePath whose names match, keyed by name.
+func ReadFiles(fs billy.Filesystem, tilePath string, match func(name string) bool) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	err := walk(fs, tilePath, func(f *zip.File) error {
+		if f.FileInfo().IsDir() || !match(f.Name) {
+			return nil
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("could not open %q in %q: %w", f.Name, tilePath, err)
+		}
+		defer r.Close()
+
+		files[f.Name], err = ioutil.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("could not read %q in %q: %w", f.Name, tilePath, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// FileNames returns the names of the files in the .pivotal file at tilePath
+// without reading their contents.
+func FileNames(fs billy.Filesystem, tilePath string) ([]string, error) {
+	var names []string
+
+	err := walk(fs, tilePath, func(f *zip.File) error {
+		if !f.FileInfo().IsDir() {
+			names = append(names, f.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func walk(fs billy.Filesystem, tilePath string, fn func(f *zip.File) error) error {
+	info, err := fs.Stat(tilePath)
+	if err != nil {
+		return err
+	}
+
+	file, err := fs.Open(tilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zr, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return fmt.Errorf("could not read %q as a zip file: %w", tilePath, err)
+	}
+
+	for _, f := range zr.File {
+		err = fn(f)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isMetadata(name string) bool {
+	dir, file := path.Split(name)
+	return dir == "metadata/" && (path.Ext(file) == ".yml" || path.Ext(file) == ".yaml")
+}
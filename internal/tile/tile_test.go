@@ -0,0 +1,131 @@
+package tile_test
+
+import (
+	"archive/zip"
+	"strings"
+
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/internal/tile"
+)
+
+var _ = Describe("tile", func() {
+	var fs billy.Filesystem
+
+	writeTile := func(path string, files map[string]string) {
+		f, err := fs.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+
+		zw := zip.NewWriter(f)
+		for _, name := range []string{"metadata/metadata.yml", "migrations/v1/", "releases/some-release.tgz"} {
+			contents, ok := files[name]
+			if !ok {
+				continue
+			}
+			w, err := zw.Create(name)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte(contents))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(zw.Close()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		fs = memfs.New()
+		writeTile("some-tile.pivotal", map[string]string{
+			"metadata/metadata.yml":     "name: some-name\n",
+			"migrations/v1/":            "",
+			"releases/some-release.tgz": "some-release-contents",
+		})
+	})
+
+	Describe("Read", func() {
+		It("returns the metadata and every file in the tile", func() {
+			t, err := Read(fs, "some-tile.pivotal")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(t.Metadata)).To(Equal("name: some-name\n"))
+			Expect(t.Files).To(Equal([]File{
+				{
+					Name:   "metadata/metadata.yml",
+					Size:   16,
+					SHA1:   "3db70227744ba6d6aabdce4a19ed4d8ca3a6fcc1",
+					SHA256: "5f166304689e1e655b759d816787473fb43c825215ed0b560f5c7b919a985efd",
+				},
+				{
+					Name:   "releases/some-release.tgz",
+					Size:   21,
+					SHA1:   "62798a2ca629640fe2c494e53bf265367cd8c72d",
+					SHA256: "751100c831f688f5a2b578a4801945c2807a813cca3bee77094c8212275c7b0d",
+				},
+			}))
+		})
+
+		Context("when the tile does not contain metadata", func() {
+			It("returns an error", func() {
+				writeTile("no-metadata.pivotal", map[string]string{"releases/some-release.tgz": ""})
+
+				_, err := Read(fs, "no-metadata.pivotal")
+				Expect(err).To(MatchError(`could not find metadata in "no-metadata.pivotal"`))
+			})
+		})
+
+		Context("when the file is not a zip", func() {
+			It("returns an error", func() {
+				f, err := fs.Create("not-a-tile.pivotal")
+				Expect(err).NotTo(HaveOccurred())
+				_, _ = f.Write([]byte("not a zip"))
+				Expect(f.Close()).To(Succeed())
+
+				_, err = Read(fs, "not-a-tile.pivotal")
+				Expect(err).To(MatchError(ContainSubstring(`could not read "not-a-tile.pivotal" as a zip file`)))
+			})
+		})
+
+		Context("when the file does not exist", func() {
+			It("returns an error", func() {
+				_, err := Read(fs, "missing.pivotal")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("ReadMetadata", func() {
+		It("returns the metadata", func() {
+			metadata, err := ReadMetadata(fs, "some-tile.pivotal")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(metadata)).To(Equal("name: some-name\n"))
+		})
+
+		Context("when the tile does not contain metadata", func() {
+			It("returns an error", func() {
+				writeTile("no-metadata.pivotal", map[string]string{"releases/some-release.tgz": ""})
+
+				_, err := ReadMetadata(fs, "no-metadata.pivotal")
+				Expect(err).To(MatchError(`could not find metadata in "no-metadata.pivotal"`))
+			})
+		})
+	})
+
+	Describe("ReadFiles", func() {
+		It("returns the contents of the matching files", func() {
+			files, err := ReadFiles(fs, "some-tile.pivotal", func(name string) bool {
+				return strings.HasPrefix(name, "releases/")
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"releases/some-release.tgz": []byte("some-release-contents")}))
+		})
+	})
+
+	Describe("FileNames", func() {
+		It("returns the name of every file in the tile", func() {
+			names, err := FileNames(fs, "some-tile.pivotal")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(Equal([]string{"metadata/metadata.yml", "releases/some-release.tgz"}))
+		})
+	})
+})
@@ -12,6 +12,7 @@ import (
 	"github.com/pivotal-cf/kiln/helper"
 	"github.com/pivotal-cf/kiln/internal/baking"
 	"github.com/pivotal-cf/kiln/internal/cargo"
+	"github.com/pivotal-cf/kiln/internal/sbom"
 
 	"github.com/pivotal-cf/jhanda"
 	"gopkg.in/src-d/go-billy.v4/osfs"
@@ -88,6 +89,49 @@ func main() {
 	}
 	commandSet["sync-with-local"] = commands.NewSyncWithLocal(kilnfileLoader, fs, localReleaseDirectory, rpFinder, outLogger)
 	commandSet["publish"] = commands.NewPublish(outLogger, errLogger, osfs.New(""))
+	commandSet["check-upgrade"] = commands.CheckUpgrade{
+		FS:     fs,
+		Logger: outLogger,
+	}
+	commandSet["diff"] = commands.Diff{
+		FS:     fs,
+		Logger: outLogger,
+	}
+	commandSet["inspect"] = commands.Inspect{
+		FS:     fs,
+		Logger: outLogger,
+	}
+	commandSet["lint"] = commands.Lint{
+		FS:     fs,
+		Logger: outLogger,
+	}
+	commandSet["generate-migration"] = commands.GenerateMigration{
+		FS:     fs,
+		Logger: outLogger,
+	}
+	commandSet["manifest"] = commands.Manifest{
+		FS:     fs,
+		Logger: outLogger,
+	}
+	commandSet["generate-osl"] = commands.GenerateOSL{
+		FS:              fs,
+		Logger:          outLogger,
+		ReleasesService: releasesService,
+	}
+	commandSet["sbom"] = commands.SBOM{
+		FS:              fs,
+		Logger:          outLogger,
+		KilnVersion:     version,
+		ReleasesService: releasesService,
+	}
+	commandSet["test-migrations"] = commands.TestMigrations{
+		FS:     fs,
+		Logger: outLogger,
+	}
+	commandSet["verify-signature"] = commands.VerifySignature{
+		FS:     fs,
+		Logger: outLogger,
+	}
 
 	commandSet["update-stemcell"] = commands.UpdateStemcell{
 		KilnfileLoader:             kilnfileLoader,
@@ -137,10 +181,19 @@ func bakeCommand(fs billy.Filesystem, releasesService baking.ReleasesService, ou
 	runtimeConfigsDirectoryReader := builder.NewMetadataPartsDirectoryReader()
 	runtimeConfigsService := baking.NewRuntimeConfigsService(errLogger, runtimeConfigsDirectoryReader)
 
+	partialsDirectoryReader := builder.NewMetadataPartsDirectoryReader()
+	partialsService := baking.NewPartialsService(errLogger, partialsDirectoryReader)
+
 	iconService := baking.NewIconService(errLogger)
 
 	metadataService := baking.NewMetadataService()
+	bakeConfigService := baking.NewBakeConfigService(fs)
+	opsFilesService := baking.NewOpsFilesService(fs)
 	checksummer := baking.NewChecksummer(errLogger)
+	signer := baking.NewSigner(errLogger)
+	sbomService := sbom.NewService(fs, version)
+	provenanceService := baking.NewProvenanceService(fs, version)
+	migrationsService := baking.NewMigrationsService(fs)
 
 	return commands.NewBake(
 		interpolator,
@@ -155,8 +208,15 @@ func bakeCommand(fs billy.Filesystem, releasesService baking.ReleasesService, ou
 		jobsService,
 		propertiesService,
 		runtimeConfigsService,
+		partialsService,
 		iconService,
 		metadataService,
+		bakeConfigService,
+		opsFilesService,
 		checksummer,
+		signer,
+		sbomService,
+		provenanceService,
+		migrationsService,
 	)
 }
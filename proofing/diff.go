@@ -0,0 +1,342 @@
+package proofing
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+type Change struct {
+	Type  ChangeType  `json:"type"`
+	Kind  string      `json:"kind"`
+	Name  string      `json:"name"`
+	Field string      `json:"field,omitempty"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	subject := c.Kind
+	if c.Name != "" {
+		subject = fmt.Sprintf("%s %s", c.Kind, c.Name)
+	}
+
+	switch {
+	case c.Type == ChangeAdded:
+		return fmt.Sprintf("+ %s", subject)
+	case c.Type == ChangeRemoved:
+		return fmt.Sprintf("- %s", subject)
+	case c.Old == nil:
+		return fmt.Sprintf("~ %s: %s added %v", subject, c.Field, c.New)
+	case c.New == nil:
+		return fmt.Sprintf("~ %s: %s removed %v", subject, c.Field, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %s %v -> %v", subject, c.Field, c.Old, c.New)
+	}
+}
+
+// Diff returns the changes between two product templates, grouped by kind
+// in the order: stemcell criteria, additional stemcell criteria, releases,
+// property blueprints, forms, job types and errands.
+func Diff(previous, current ProductTemplate) []Change {
+	var changes []Change
+
+	changes = append(changes, diffStemcellCriteria(previous.StemcellCriteria, current.StemcellCriteria)...)
+	changes = append(changes, diffAdditionalStemcells(previous.AdditionalStemcells, current.AdditionalStemcells)...)
+	changes = append(changes, diffReleases(previous.Releases, current.Releases)...)
+	changes = append(changes, diffPropertyBlueprints(previous.AllPropertyBlueprints(), current.AllPropertyBlueprints())...)
+	changes = append(changes, diffFormTypes(previous.FormTypes, current.FormTypes)...)
+	changes = append(changes, diffJobTypes(previous.JobTypes, current.JobTypes)...)
+	changes = append(changes, diffErrands("post_deploy_errand", previous.PostDeployErrands, current.PostDeployErrands)...)
+	changes = append(changes, diffErrands("pre_delete_errand", previous.PreDeleteErrands, current.PreDeleteErrands)...)
+
+	return changes
+}
+
+func diffStemcellCriteria(previous, current StemcellCriteria) []Change {
+	var changes []Change
+	changes = appendFieldChange(changes, "stemcell_criteria", "", "os", previous.OS, current.OS)
+	changes = appendFieldChange(changes, "stemcell_criteria", "", "version", previous.Version, current.Version)
+	return changes
+}
+
+// diffAdditionalStemcells compares the additional stemcell criteria by
+// operating system, as a tile can only have one stemcell of each.
+func diffAdditionalStemcells(previous, current []StemcellCriteria) []Change {
+	previousByOS := map[string]StemcellCriteria{}
+	for _, sc := range previous {
+		previousByOS[sc.OS] = sc
+	}
+
+	currentByOS := map[string]StemcellCriteria{}
+	for _, sc := range current {
+		currentByOS[sc.OS] = sc
+	}
+
+	return diffNames("additional_stemcell_criteria", keys(previousByOS), keys(currentByOS), func(name string) []Change {
+		var changes []Change
+		p, c := previousByOS[name], currentByOS[name]
+		changes = appendFieldChange(changes, "additional_stemcell_criteria", name, "version", p.Version, c.Version)
+		return changes
+	})
+}
+
+func diffReleases(previous, current []Release) []Change {
+	previousByName := map[string]Release{}
+	for _, r := range previous {
+		previousByName[r.Name] = r
+	}
+
+	currentByName := map[string]Release{}
+	for _, r := range current {
+		currentByName[r.Name] = r
+	}
+
+	return diffNames("release", keys(previousByName), keys(currentByName), func(name string) []Change {
+		var changes []Change
+		p, c := previousByName[name], currentByName[name]
+		changes = appendFieldChange(changes, "release", name, "version", p.Version, c.Version)
+		if p.Version == c.Version {
+			changes = appendFieldChange(changes, "release", name, "sha1", p.SHA1, c.SHA1)
+		}
+		return changes
+	})
+}
+
+func diffPropertyBlueprints(previous, current []NormalizedPropertyBlueprint) []Change {
+	previousByName := map[string]NormalizedPropertyBlueprint{}
+	for _, pb := range previous {
+		previousByName[pb.Property] = pb
+	}
+
+	currentByName := map[string]NormalizedPropertyBlueprint{}
+	for _, pb := range current {
+		currentByName[pb.Property] = pb
+	}
+
+	return diffNames("property_blueprint", keys(previousByName), keys(currentByName), func(name string) []Change {
+		var changes []Change
+		p, c := previousByName[name], currentByName[name]
+		changes = appendFieldChange(changes, "property_blueprint", name, "type", p.Type, c.Type)
+		changes = appendFieldChange(changes, "property_blueprint", name, "default", p.Default, c.Default)
+		changes = appendFieldChange(changes, "property_blueprint", name, "configurable", p.Configurable, c.Configurable)
+		changes = appendFieldChange(changes, "property_blueprint", name, "required", p.Required, c.Required)
+
+		previousOptions := map[string]bool{}
+		for _, option := range p.Options {
+			previousOptions[option] = true
+		}
+
+		currentOptions := map[string]bool{}
+		for _, option := range c.Options {
+			currentOptions[option] = true
+		}
+
+		for _, option := range keys(previousOptions) {
+			if !currentOptions[option] {
+				changes = append(changes, Change{Type: ChangeChanged, Kind: "property_blueprint", Name: name, Field: "options", Old: option})
+			}
+		}
+		for _, option := range keys(currentOptions) {
+			if !previousOptions[option] {
+				changes = append(changes, Change{Type: ChangeChanged, Kind: "property_blueprint", Name: name, Field: "options", New: option})
+			}
+		}
+
+		return changes
+	})
+}
+
+func diffFormTypes(previous, current []FormType) []Change {
+	previousByName := map[string]FormType{}
+	for _, ft := range previous {
+		previousByName[ft.Name] = ft
+	}
+
+	currentByName := map[string]FormType{}
+	for _, ft := range current {
+		currentByName[ft.Name] = ft
+	}
+
+	return diffNames("form_type", keys(previousByName), keys(currentByName), func(name string) []Change {
+		var changes []Change
+		p, c := previousByName[name], currentByName[name]
+		changes = appendFieldChange(changes, "form_type", name, "label", p.Label, c.Label)
+		changes = appendFieldChange(changes, "form_type", name, "description", p.Description, c.Description)
+
+		previousReferences := map[string]bool{}
+		for _, reference := range p.references() {
+			previousReferences[reference] = true
+		}
+
+		currentReferences := map[string]bool{}
+		for _, reference := range c.references() {
+			currentReferences[reference] = true
+		}
+
+		for _, reference := range keys(previousReferences) {
+			if !currentReferences[reference] {
+				changes = append(changes, Change{Type: ChangeChanged, Kind: "form_type", Name: name, Field: "property_inputs", Old: reference})
+			}
+		}
+		for _, reference := range keys(currentReferences) {
+			if !previousReferences[reference] {
+				changes = append(changes, Change{Type: ChangeChanged, Kind: "form_type", Name: name, Field: "property_inputs", New: reference})
+			}
+		}
+
+		return changes
+	})
+}
+
+func diffJobTypes(previous, current []JobType) []Change {
+	previousByName := map[string]JobType{}
+	for _, jt := range previous {
+		previousByName[jt.Name] = jt
+	}
+
+	currentByName := map[string]JobType{}
+	for _, jt := range current {
+		currentByName[jt.Name] = jt
+	}
+
+	return diffNames("job_type", keys(previousByName), keys(currentByName), func(name string) []Change {
+		var changes []Change
+		p, c := previousByName[name], currentByName[name]
+		changes = appendFieldChange(changes, "job_type", name, "errand", p.Errand, c.Errand)
+		changes = appendFieldChange(changes, "job_type", name, "instance_definition.default", p.InstanceDefinition.Default, c.InstanceDefinition.Default)
+		changes = appendFieldChange(changes, "job_type", name, "instance_definition.configurable", p.InstanceDefinition.Configurable, c.InstanceDefinition.Configurable)
+
+		previousTemplates := map[string]bool{}
+		for _, t := range p.Templates {
+			previousTemplates[t.Name] = true
+		}
+
+		currentTemplates := map[string]bool{}
+		for _, t := range c.Templates {
+			currentTemplates[t.Name] = true
+		}
+
+		for _, templateName := range keys(previousTemplates) {
+			if !currentTemplates[templateName] {
+				changes = append(changes, Change{Type: ChangeChanged, Kind: "job_type", Name: name, Field: "templates", Old: templateName})
+			}
+		}
+		for _, templateName := range keys(currentTemplates) {
+			if !previousTemplates[templateName] {
+				changes = append(changes, Change{Type: ChangeChanged, Kind: "job_type", Name: name, Field: "templates", New: templateName})
+			}
+		}
+
+		previousResources := map[string]ResourceDefinition{}
+		for _, rd := range p.ResourceDefinitions {
+			previousResources[rd.Name] = rd
+		}
+
+		currentResources := map[string]ResourceDefinition{}
+		for _, rd := range c.ResourceDefinitions {
+			currentResources[rd.Name] = rd
+		}
+
+		resourceChanges := diffNames("resource_definition", keys(previousResources), keys(currentResources), func(resource string) []Change {
+			var changes []Change
+			p, c := previousResources[resource], currentResources[resource]
+			changes = appendFieldChange(changes, "resource_definition", resource, "default", p.Default, c.Default)
+			changes = appendFieldChange(changes, "resource_definition", resource, "configurable", p.Configurable, c.Configurable)
+			return changes
+		})
+		for _, change := range resourceChanges {
+			change.Name = fmt.Sprintf("%s.%s", name, change.Name)
+			changes = append(changes, change)
+		}
+
+		return changes
+	})
+}
+
+func diffErrands(kind string, previous, current []ErrandTemplate) []Change {
+	previousByName := map[string]ErrandTemplate{}
+	for _, e := range previous {
+		previousByName[e.Name] = e
+	}
+
+	currentByName := map[string]ErrandTemplate{}
+	for _, e := range current {
+		currentByName[e.Name] = e
+	}
+
+	return diffNames(kind, keys(previousByName), keys(currentByName), func(name string) []Change {
+		var changes []Change
+		p, c := previousByName[name], currentByName[name]
+		changes = appendFieldChange(changes, kind, name, "run_default", p.RunDefault, c.RunDefault)
+		changes = appendFieldChange(changes, kind, name, "colocated", p.Colocated, c.Colocated)
+		return changes
+	})
+}
+
+// diffNames reports names only present in previous as removed and names only
+// present in current as added. For names present in both it calls changed,
+// when given, to compare their fields.
+func diffNames(kind string, previous, current []string, changed func(name string) []Change) []Change {
+	inPrevious := map[string]bool{}
+	for _, name := range previous {
+		inPrevious[name] = true
+	}
+
+	inCurrent := map[string]bool{}
+	for _, name := range current {
+		inCurrent[name] = true
+	}
+
+	var changes []Change
+	for _, name := range previous {
+		if !inCurrent[name] {
+			changes = append(changes, Change{Type: ChangeRemoved, Kind: kind, Name: name})
+		}
+	}
+
+	for _, name := range current {
+		if !inPrevious[name] {
+			changes = append(changes, Change{Type: ChangeAdded, Kind: kind, Name: name})
+			continue
+		}
+
+		if changed != nil {
+			changes = append(changes, changed(name)...)
+		}
+	}
+
+	return changes
+}
+
+func appendFieldChange(changes []Change, kind, name, field string, previous, current interface{}) []Change {
+	if reflect.DeepEqual(previous, current) {
+		return changes
+	}
+
+	return append(changes, Change{
+		Type:  ChangeChanged,
+		Kind:  kind,
+		Name:  name,
+		Field: field,
+		Old:   previous,
+		New:   current,
+	})
+}
+
+func keys(m interface{}) []string {
+	var names []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+	return names
+}
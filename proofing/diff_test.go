@@ -0,0 +1,129 @@
+package proofing_test
+
+import (
+	"os"
+
+	. "github.com/pivotal-cf/kiln/proofing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	var previous, current ProductTemplate
+
+	parse := func(path string) ProductTemplate {
+		f, err := os.Open(path)
+		defer f.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		productTemplate, err := Parse(f)
+		Expect(err).NotTo(HaveOccurred())
+
+		return productTemplate
+	}
+
+	BeforeEach(func() {
+		previous = parse("fixtures/diff/previous.yml")
+		current = parse("fixtures/diff/current.yml")
+	})
+
+	It("returns the changes between two product templates", func() {
+		var changes []string
+		for _, change := range Diff(previous, current) {
+			changes = append(changes, change.String())
+		}
+
+		Expect(changes).To(Equal([]string{
+			"~ stemcell_criteria: version 621 -> 621.1",
+			"- additional_stemcell_criteria ubuntu-trusty",
+			"~ additional_stemcell_criteria windows2019: version 2019.10 -> 2019.12",
+			"- release removed-release",
+			"+ release added-release",
+			"~ release some-release: version 1.0.0 -> 1.1.0",
+			"- property_blueprint .properties.removed-property",
+			"+ property_blueprint .properties.added-property",
+			"~ property_blueprint .properties.some-dropdown: options removed large",
+			"~ property_blueprint .properties.some-dropdown: options added medium",
+			"~ property_blueprint .properties.some-property: type string -> integer",
+			"~ property_blueprint .properties.some-property: default some-default -> 1",
+			"~ property_blueprint .properties.some-property: configurable true -> false",
+			"- form_type removed-form",
+			"+ form_type added-form",
+			"~ form_type some-form: label some-label -> other-label",
+			"~ form_type some-form: property_inputs removed .properties.removed-property",
+			"~ form_type some-form: property_inputs added .properties.added-property",
+			"- job_type removed-job",
+			"+ job_type added-job",
+			"~ job_type some-job: instance_definition.default 1 -> 3",
+			"~ job_type some-job: templates removed removed-template",
+			"~ job_type some-job: templates added added-template",
+			"- resource_definition some-job.removed-resource",
+			"+ resource_definition some-job.added-resource",
+			"~ resource_definition some-job.ram: default 1024 -> 2048",
+			"~ post_deploy_errand some-errand: run_default true -> false",
+			"- pre_delete_errand removed-errand",
+		}))
+	})
+
+	It("describes each change", func() {
+		changes := Diff(previous, current)
+
+		Expect(changes).To(ContainElement(Change{
+			Type:  ChangeChanged,
+			Kind:  "release",
+			Name:  "some-release",
+			Field: "version",
+			Old:   "1.0.0",
+			New:   "1.1.0",
+		}))
+		Expect(changes).To(ContainElement(Change{
+			Type: ChangeAdded,
+			Kind: "job_type",
+			Name: "added-job",
+		}))
+	})
+
+	It("reports the options added to and removed from a property blueprint", func() {
+		changes := Diff(previous, current)
+
+		Expect(changes).To(ContainElement(Change{
+			Type:  ChangeChanged,
+			Kind:  "property_blueprint",
+			Name:  ".properties.some-dropdown",
+			Field: "options",
+			Old:   "large",
+		}))
+		Expect(changes).To(ContainElement(Change{
+			Type:  ChangeChanged,
+			Kind:  "property_blueprint",
+			Name:  ".properties.some-dropdown",
+			Field: "options",
+			New:   "medium",
+		}))
+	})
+
+	It("reports changes to the additional stemcell criteria by operating system", func() {
+		changes := Diff(previous, current)
+
+		Expect(changes).To(ContainElement(Change{
+			Type:  ChangeChanged,
+			Kind:  "additional_stemcell_criteria",
+			Name:  "windows2019",
+			Field: "version",
+			Old:   "2019.10",
+			New:   "2019.12",
+		}))
+		Expect(changes).To(ContainElement(Change{
+			Type: ChangeRemoved,
+			Kind: "additional_stemcell_criteria",
+			Name: "ubuntu-trusty",
+		}))
+	})
+
+	Context("when the product templates are the same", func() {
+		It("returns no changes", func() {
+			Expect(Diff(previous, previous)).To(BeEmpty())
+		})
+	})
+})
@@ -11,3 +11,26 @@ type FormType struct {
 
 	// TODO: validations: https://github.com/pivotal-cf/installation/blob/039a2ef3f751ef5915c425da8150a29af4b764dd/web/app/models/persistence/metadata/form_type.rb#L13-L24
 }
+
+func (ft FormType) references() []string {
+	var references []string
+
+	for _, pi := range ft.PropertyInputs {
+		switch input := pi.(type) {
+		case SelectorPropertyInput:
+			references = append(references, input.Reference)
+			for _, option := range input.SelectorPropertyInputs {
+				references = append(references, option.Reference)
+				for _, nested := range option.PropertyInputs {
+					references = append(references, nested.Reference)
+				}
+			}
+		case CollectionPropertyInput:
+			references = append(references, input.Reference)
+		case SimplePropertyInput:
+			references = append(references, input.Reference)
+		}
+	}
+
+	return references
+}
@@ -25,6 +25,7 @@ type ProductTemplate struct {
 	Variables               []Variable              `yaml:"variables"` // TODO: schema?
 	Releases                []Release               `yaml:"releases"`
 	StemcellCriteria        StemcellCriteria        `yaml:"stemcell_criteria"`
+	AdditionalStemcells     []StemcellCriteria      `yaml:"additional_stemcells_criteria"`
 	PropertyBlueprints      PropertyBlueprints      `yaml:"property_blueprints"`
 	FormTypes               []FormType              `yaml:"form_types"`
 	JobTypes                []JobType               `yaml:"job_types"`
@@ -14,6 +14,10 @@ type NormalizedPropertyBlueprint struct {
 	Default      interface{}
 	Required     bool
 	Type         string
+
+	// Options are the names of the options of a dropdown or the option
+	// templates of a selector.
+	Options []string
 }
 
 // TODO: Less ugly.
@@ -0,0 +1,184 @@
+package proofing
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	accessorExpressionPattern = regexp.MustCompile(`\(\(\s*(.*?)\s*\)\)`)
+	accessorPathPattern       = regexp.MustCompile(`(^|[^.\w$-])(\.[\w-]+(?:\.[\w-]+)*)`)
+)
+
+// jobAccessors are the non-property values Ops Manager exposes on a job
+// type, e.g. (( .my-job.ips )).
+var jobAccessors = map[string]bool{
+	"ips":                true,
+	"first_ip":           true,
+	"name":               true,
+	"release":            true,
+	"instances":          true,
+	"availability_zones": true,
+}
+
+type PropertyReference struct {
+	Accessor string
+	Source   string
+}
+
+type PropertyReferenceReport struct {
+	UndefinedReferences             []PropertyReference
+	UnreferencedBlueprints          []string
+	ConfigurableBlueprintsNotInForm []string
+}
+
+func (r PropertyReferenceReport) Empty() bool {
+	return len(r.UndefinedReferences) == 0 &&
+		len(r.UnreferencedBlueprints) == 0 &&
+		len(r.ConfigurableBlueprintsNotInForm) == 0
+}
+
+// PropertyReferences returns every property accessor found in the job type,
+// template, runtime config and named manifests of the product template.
+func (pt ProductTemplate) PropertyReferences() []PropertyReference {
+	var references []PropertyReference
+
+	for _, jobType := range pt.JobTypes {
+		references = append(references, findAccessors(jobType.Manifest, fmt.Sprintf("job_types[%s].manifest", jobType.Name))...)
+
+		for _, template := range jobType.Templates {
+			source := fmt.Sprintf("job_types[%s].templates[%s].manifest", jobType.Name, template.Name)
+			references = append(references, findAccessors(template.Manifest, source)...)
+		}
+
+		for _, pb := range jobType.PropertyBlueprints {
+			references = append(references, namedManifestAccessors(pb, fmt.Sprintf("job_types[%s].property_blueprints", jobType.Name))...)
+		}
+	}
+
+	for _, runtimeConfig := range pt.RuntimeConfigs {
+		source := fmt.Sprintf("runtime_configs[%s].runtime_config", runtimeConfig.Name)
+		references = append(references, findAccessors(runtimeConfig.RuntimeConfig, source)...)
+	}
+
+	for _, pb := range pt.PropertyBlueprints {
+		references = append(references, namedManifestAccessors(pb, "property_blueprints")...)
+	}
+
+	return references
+}
+
+// CheckPropertyReferences compares the property accessors in the product
+// template against its normalized property blueprints. It reports accessors
+// that do not resolve to a blueprint, blueprints that are never referenced,
+// and configurable blueprints that no form exposes.
+func (pt ProductTemplate) CheckPropertyReferences() PropertyReferenceReport {
+	blueprints := pt.AllPropertyBlueprints()
+
+	defined := map[string]bool{}
+	for _, pb := range blueprints {
+		defined[pb.Property] = true
+	}
+
+	jobTypes := map[string]bool{}
+	for _, jobType := range pt.JobTypes {
+		jobTypes[jobType.Name] = true
+	}
+
+	var report PropertyReferenceReport
+
+	referenced := map[string]bool{}
+	for _, reference := range pt.PropertyReferences() {
+		segments := strings.Split(strings.TrimPrefix(reference.Accessor, "."), ".")
+		if len(segments) < 2 {
+			continue
+		}
+
+		scope := segments[0]
+		if scope != "properties" {
+			if !jobTypes[scope] {
+				if segments[len(segments)-1] == "value" {
+					report.UndefinedReferences = append(report.UndefinedReferences, reference)
+				}
+				continue
+			}
+
+			if jobAccessors[segments[1]] {
+				continue
+			}
+		}
+
+		var found bool
+		for i := 2; i <= len(segments); i++ {
+			property := "." + strings.Join(segments[:i], ".")
+			if defined[property] {
+				referenced[property] = true
+				found = true
+			}
+		}
+
+		if !found {
+			report.UndefinedReferences = append(report.UndefinedReferences, reference)
+		}
+	}
+
+	inForm := map[string]bool{}
+	for _, formType := range pt.FormTypes {
+		for _, reference := range formType.references() {
+			inForm[reference] = true
+		}
+	}
+
+	for _, pb := range blueprints {
+		if !referenced[pb.Property] {
+			report.UnreferencedBlueprints = append(report.UnreferencedBlueprints, pb.Property)
+		}
+
+		if pb.Configurable && !inForm[pb.Property] {
+			report.ConfigurableBlueprintsNotInForm = append(report.ConfigurableBlueprintsNotInForm, pb.Property)
+		}
+	}
+
+	sort.Strings(report.UnreferencedBlueprints)
+	sort.Strings(report.ConfigurableBlueprintsNotInForm)
+
+	return report
+}
+
+func namedManifestAccessors(pb PropertyBlueprint, source string) []PropertyReference {
+	var references []PropertyReference
+
+	switch blueprint := pb.(type) {
+	case SelectorPropertyBlueprint:
+		for _, optionTemplate := range blueprint.OptionTemplates {
+			for _, namedManifest := range optionTemplate.NamedManifests {
+				s := fmt.Sprintf("%s[%s].option_templates[%s].named_manifests[%s]", source, blueprint.Name, optionTemplate.Name, namedManifest.Name)
+				references = append(references, findAccessors(namedManifest.Manifest, s)...)
+			}
+		}
+	case CollectionPropertyBlueprint:
+		for _, namedManifest := range blueprint.NamedManifests {
+			s := fmt.Sprintf("%s[%s].named_manifests[%s]", source, blueprint.Name, namedManifest.Name)
+			references = append(references, findAccessors(namedManifest.Manifest, s)...)
+		}
+	}
+
+	return references
+}
+
+func findAccessors(manifest, source string) []PropertyReference {
+	var references []PropertyReference
+
+	for _, expression := range accessorExpressionPattern.FindAllStringSubmatch(manifest, -1) {
+		for _, match := range accessorPathPattern.FindAllStringSubmatch(expression[1], -1) {
+			references = append(references, PropertyReference{
+				Accessor: match[2],
+				Source:   source,
+			})
+		}
+	}
+
+	return references
+}
@@ -0,0 +1,76 @@
+package proofing_test
+
+import (
+	"os"
+
+	. "github.com/pivotal-cf/kiln/proofing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PropertyReferences", func() {
+	var productTemplate ProductTemplate
+
+	BeforeEach(func() {
+		f, err := os.Open("fixtures/property_references.yml")
+		defer f.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		productTemplate, err = Parse(f)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("finds the accessors in every manifest", func() {
+		Expect(productTemplate.PropertyReferences()).To(ConsistOf(
+			PropertyReference{Accessor: ".properties.some-simple-name.value", Source: "job_types[some-job-type-name].manifest"},
+			PropertyReference{Accessor: ".some-job-type-name.some-job-property-name.value", Source: "job_types[some-job-type-name].manifest"},
+			PropertyReference{Accessor: ".some-job-type-name.ips", Source: "job_types[some-job-type-name].manifest"},
+			PropertyReference{Accessor: ".properties.some-missing-name.value", Source: "job_types[some-job-type-name].manifest"},
+			PropertyReference{Accessor: ".properties.some-selector-name.selected_option.parsed_manifest", Source: "job_types[some-job-type-name].templates[some-template-name].manifest"},
+			PropertyReference{Accessor: ".some-missing-job.some-name.value", Source: "job_types[some-job-type-name].templates[some-template-name].manifest"},
+			PropertyReference{Accessor: ".properties.some-hidden-name.value", Source: "runtime_configs[some-runtime-config].runtime_config"},
+			PropertyReference{
+				Accessor: ".properties.some-selector-name.some-option-template-name.some-nested-simple-name.value",
+				Source:   "property_blueprints[some-selector-name].option_templates[some-option-template-name].named_manifests[some-named-manifest]",
+			},
+		))
+	})
+
+	Describe("CheckPropertyReferences", func() {
+		var report PropertyReferenceReport
+
+		BeforeEach(func() {
+			report = productTemplate.CheckPropertyReferences()
+		})
+
+		It("reports accessors that do not match a property blueprint", func() {
+			Expect(report.UndefinedReferences).To(ConsistOf(
+				PropertyReference{Accessor: ".properties.some-missing-name.value", Source: "job_types[some-job-type-name].manifest"},
+				PropertyReference{Accessor: ".some-missing-job.some-name.value", Source: "job_types[some-job-type-name].templates[some-template-name].manifest"},
+			))
+		})
+
+		It("reports property blueprints that are never referenced", func() {
+			Expect(report.UnreferencedBlueprints).To(Equal([]string{
+				".properties.some-unused-name",
+			}))
+		})
+
+		It("reports configurable property blueprints that do not appear in a form", func() {
+			Expect(report.ConfigurableBlueprintsNotInForm).To(Equal([]string{
+				".properties.some-hidden-name",
+			}))
+		})
+
+		It("is not empty", func() {
+			Expect(report.Empty()).To(BeFalse())
+		})
+
+		Context("when every reference resolves", func() {
+			It("returns an empty report", func() {
+				Expect(ProductTemplate{}.CheckPropertyReferences().Empty()).To(BeTrue())
+			})
+		})
+	})
+})
@@ -14,6 +14,11 @@ type SelectorPropertyBlueprint struct {
 func (sp SelectorPropertyBlueprint) Normalize(prefix string) []NormalizedPropertyBlueprint {
 	propertyBlueprints := sp.SimplePropertyBlueprint.Normalize(prefix)
 
+	propertyBlueprints[0].Options = nil
+	for _, optionTemplate := range sp.OptionTemplates {
+		propertyBlueprints[0].Options = append(propertyBlueprints[0].Options, optionTemplate.Name)
+	}
+
 	for _, optionTemplate := range sp.OptionTemplates {
 		for _, otpb := range optionTemplate.PropertyBlueprints {
 			propertyName := fmt.Sprintf("%s.%s.%s", prefix, sp.Name, optionTemplate.Name)
@@ -49,6 +49,7 @@ var _ = Describe("SelectorPropertyBlueprint", func() {
 					Default:      "some-default",
 					Required:     false,
 					Type:         "selector",
+					Options:      []string{"some-option-template-name"},
 				},
 				{
 					Property:     "some-prefix.some-selector-name.some-option-template-name.some-nested-simple-name",
@@ -56,6 +57,7 @@ var _ = Describe("SelectorPropertyBlueprint", func() {
 					Default:      1,
 					Required:     false,
 					Type:         "some-type",
+					Options:      []string{"some-name"},
 				},
 			}))
 		})
@@ -20,6 +20,11 @@ type SimplePropertyBlueprint struct {
 }
 
 func (sp SimplePropertyBlueprint) Normalize(prefix string) []NormalizedPropertyBlueprint {
+	var options []string
+	for _, option := range sp.Options {
+		options = append(options, option.Name)
+	}
+
 	return []NormalizedPropertyBlueprint{
 		{
 			Property:     fmt.Sprintf("%s.%s", prefix, sp.Name),
@@ -27,6 +32,7 @@ func (sp SimplePropertyBlueprint) Normalize(prefix string) []NormalizedPropertyB
 			Default:      sp.Default,
 			Required:     !sp.Optional,
 			Type:         sp.Type,
+			Options:      options,
 		},
 	}
 }
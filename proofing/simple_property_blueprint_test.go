@@ -49,6 +49,7 @@ var _ = Describe("SimplePropertyBlueprint", func() {
 					Default:      "some-default",
 					Required:     false,
 					Type:         "some-type",
+					Options:      []string{"some-name"},
 				},
 			}))
 		})
@@ -0,0 +1,252 @@
+package proofing
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+const (
+	HazardRemovedPropertyBlueprint      = "removed_property_blueprint"
+	HazardPropertyBlueprintType         = "property_blueprint_type"
+	HazardPropertyBlueprintConfigurable = "property_blueprint_configurable"
+	HazardRemovedJobType                = "removed_job_type"
+	HazardMinimumVersionForUpgrade      = "minimum_version_for_upgrade"
+	HazardStemcellOS                    = "stemcell_os"
+)
+
+type UpgradeHazard struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+}
+
+func (h UpgradeHazard) String() string {
+	return h.Message
+}
+
+type PropertyRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// CheckUpgrade returns the hazards of upgrading an installation of previous
+// to current: property blueprints that were removed or renamed without a
+// migration referencing them, property blueprint type changes, configurable
+// property blueprints that became non-configurable, removed job types, a
+// minimum_version_for_upgrade that is inconsistent with the product
+// versions, and a change of stemcell OS. previousMigrations and migrations
+// are the sources of the migrations of previous and current keyed by file
+// name. Only migrations that previous does not have are run on upgrade, so
+// only they can handle a removed property blueprint.
+func CheckUpgrade(previous, current ProductTemplate, previousMigrations, migrations map[string]string) []UpgradeHazard {
+	var hazards []UpgradeHazard
+
+	var newMigrations []string
+	for name, source := range migrations {
+		if _, ok := previousMigrations[name]; !ok {
+			newMigrations = append(newMigrations, source)
+		}
+	}
+
+	hazards = append(hazards, checkPropertyBlueprints(previous, current, newMigrations)...)
+	hazards = append(hazards, checkJobTypes(previous.JobTypes, current.JobTypes)...)
+	hazards = append(hazards, checkMinimumVersionForUpgrade(previous, current)...)
+
+	if previous.StemcellCriteria.OS != current.StemcellCriteria.OS {
+		hazards = append(hazards, UpgradeHazard{
+			Kind:    HazardStemcellOS,
+			Message: fmt.Sprintf("stemcell_criteria os changed from %s to %s, every VM is recreated on the new OS", previous.StemcellCriteria.OS, current.StemcellCriteria.OS),
+		})
+	}
+
+	return hazards
+}
+
+// DetectPropertyRenames pairs property blueprints removed from previous with
+// property blueprints added to current that have the same type, options and
+// parent, for example .properties.old_name and .properties.new_name. Only
+// pairs where neither side has another candidate are reported.
+func DetectPropertyRenames(previous, current ProductTemplate) []PropertyRename {
+	removed, added := removedAndAddedBlueprints(previous.AllPropertyBlueprints(), current.AllPropertyBlueprints())
+
+	candidates := func(pb NormalizedPropertyBlueprint, others []NormalizedPropertyBlueprint) []NormalizedPropertyBlueprint {
+		var matches []NormalizedPropertyBlueprint
+		for _, other := range others {
+			if other.Type == pb.Type && parent(other.Property) == parent(pb.Property) && reflect.DeepEqual(other.Options, pb.Options) {
+				matches = append(matches, other)
+			}
+		}
+		return matches
+	}
+
+	var renames []PropertyRename
+	for _, pb := range removed {
+		to := candidates(pb, added)
+		if len(to) != 1 || len(candidates(to[0], removed)) != 1 {
+			continue
+		}
+
+		renames = append(renames, PropertyRename{From: pb.Property, To: to[0].Property, Type: pb.Type})
+	}
+
+	return renames
+}
+
+func checkPropertyBlueprints(previous, current ProductTemplate, migrations []string) []UpgradeHazard {
+	previousBlueprints := previous.AllPropertyBlueprints()
+	currentBlueprints := current.AllPropertyBlueprints()
+
+	renamedTo := map[string]string{}
+	for _, rename := range DetectPropertyRenames(previous, current) {
+		renamedTo[rename.From] = rename.To
+	}
+
+	var hazards []UpgradeHazard
+
+	removed, _ := removedAndAddedBlueprints(previousBlueprints, currentBlueprints)
+	for _, pb := range removed {
+		if referencedByMigration(pb.Property, migrations) {
+			continue
+		}
+
+		message := fmt.Sprintf("property blueprint %s was removed and no migration references it", pb.Property)
+		if to, ok := renamedTo[pb.Property]; ok {
+			message = fmt.Sprintf("property blueprint %s was removed, possibly renamed to %s, and no migration references it", pb.Property, to)
+		}
+
+		hazards = append(hazards, UpgradeHazard{Kind: HazardRemovedPropertyBlueprint, Name: pb.Property, Message: message})
+	}
+
+	previousByName := map[string]NormalizedPropertyBlueprint{}
+	for _, pb := range previousBlueprints {
+		previousByName[pb.Property] = pb
+	}
+
+	for _, c := range currentBlueprints {
+		p, ok := previousByName[c.Property]
+		if !ok {
+			continue
+		}
+
+		if p.Type != c.Type {
+			hazards = append(hazards, UpgradeHazard{
+				Kind:    HazardPropertyBlueprintType,
+				Name:    c.Property,
+				Message: fmt.Sprintf("property blueprint %s changed type from %s to %s", c.Property, p.Type, c.Type),
+			})
+		}
+
+		if p.Configurable && !c.Configurable {
+			hazards = append(hazards, UpgradeHazard{
+				Kind:    HazardPropertyBlueprintConfigurable,
+				Name:    c.Property,
+				Message: fmt.Sprintf("property blueprint %s is no longer configurable, values set by operators are lost", c.Property),
+			})
+		}
+	}
+
+	return hazards
+}
+
+func checkJobTypes(previous, current []JobType) []UpgradeHazard {
+	inCurrent := map[string]bool{}
+	for _, jt := range current {
+		inCurrent[jt.Name] = true
+	}
+
+	var hazards []UpgradeHazard
+	for _, jt := range previous {
+		if !inCurrent[jt.Name] {
+			hazards = append(hazards, UpgradeHazard{
+				Kind:    HazardRemovedJobType,
+				Name:    jt.Name,
+				Message: fmt.Sprintf("job type %s was removed, its VMs and persistent disks are deleted on upgrade", jt.Name),
+			})
+		}
+	}
+
+	return hazards
+}
+
+func checkMinimumVersionForUpgrade(previous, current ProductTemplate) []UpgradeHazard {
+	if current.MinimumVersionForUpgrade == "" {
+		return nil
+	}
+
+	hazard := func(format string, args ...interface{}) []UpgradeHazard {
+		return []UpgradeHazard{{Kind: HazardMinimumVersionForUpgrade, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	minimum, err := semver.NewVersion(current.MinimumVersionForUpgrade)
+	if err != nil {
+		return hazard("minimum_version_for_upgrade %q is not a semantic version", current.MinimumVersionForUpgrade)
+	}
+
+	if version, err := semver.NewVersion(current.ProductVersion); err == nil && minimum.GreaterThan(version) {
+		return hazard("minimum_version_for_upgrade %s is greater than product_version %s", current.MinimumVersionForUpgrade, current.ProductVersion)
+	}
+
+	if version, err := semver.NewVersion(previous.ProductVersion); err == nil && minimum.GreaterThan(version) {
+		return hazard("minimum_version_for_upgrade %s is greater than the previous product_version %s, which cannot be upgraded", current.MinimumVersionForUpgrade, previous.ProductVersion)
+	}
+
+	return nil
+}
+
+// removedAndAddedBlueprints returns the blueprints only in previous and the
+// blueprints only in current, each sorted by property name.
+func removedAndAddedBlueprints(previous, current []NormalizedPropertyBlueprint) ([]NormalizedPropertyBlueprint, []NormalizedPropertyBlueprint) {
+	inPrevious := map[string]bool{}
+	for _, pb := range previous {
+		inPrevious[pb.Property] = true
+	}
+
+	inCurrent := map[string]bool{}
+	for _, pb := range current {
+		inCurrent[pb.Property] = true
+	}
+
+	var removed, added []NormalizedPropertyBlueprint
+	for _, pb := range previous {
+		if !inCurrent[pb.Property] {
+			removed = append(removed, pb)
+		}
+	}
+	for _, pb := range current {
+		if !inPrevious[pb.Property] {
+			added = append(added, pb)
+		}
+	}
+
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Property < removed[j].Property })
+	sort.Slice(added, func(i, j int) bool { return added[i].Property < added[j].Property })
+
+	return removed, added
+}
+
+// referencedByMigration reports whether a migration uses the exact accessor of
+// property, not a longer accessor or the accessor of a parent property.
+func referencedByMigration(property string, migrations []string) bool {
+	accessor := regexp.MustCompile(`(^|[^\w.-])` + regexp.QuoteMeta(property) + `([^\w.-]|$)`)
+	for _, migration := range migrations {
+		if accessor.MatchString(migration) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parent(property string) string {
+	i := strings.LastIndex(property, ".")
+	if i < 0 {
+		return ""
+	}
+	return property[:i]
+}
@@ -0,0 +1,142 @@
+package proofing_test
+
+import (
+	"os"
+
+	. "github.com/pivotal-cf/kiln/proofing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckUpgrade", func() {
+	var previous, current ProductTemplate
+
+	parse := func(path string) ProductTemplate {
+		f, err := os.Open(path)
+		defer f.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		productTemplate, err := Parse(f)
+		Expect(err).NotTo(HaveOccurred())
+
+		return productTemplate
+	}
+
+	messages := func(hazards []UpgradeHazard) []string {
+		var messages []string
+		for _, hazard := range hazards {
+			messages = append(messages, hazard.String())
+		}
+		return messages
+	}
+
+	BeforeEach(func() {
+		previous = parse("fixtures/upgrade/previous.yml")
+		current = parse("fixtures/upgrade/current.yml")
+	})
+
+	It("returns the upgrade hazards between two product templates", func() {
+		migrations := map[string]string{
+			"201801010000_migrated.js": `exports.migrate = function(input) { delete input.properties[".properties.migrated-property"]; return input; };`,
+			"201801010001_option.js":   `exports.migrate = function(input) { delete input.properties[".properties.some-selector.some-option.removed-option-property"]; return input; };`,
+		}
+
+		Expect(messages(CheckUpgrade(previous, current, nil, migrations))).To(Equal([]string{
+			"property blueprint .properties.old-name was removed, possibly renamed to .properties.new-name, and no migration references it",
+			"property blueprint .properties.removed-property was removed and no migration references it",
+			"property blueprint .properties.some-property changed type from string to integer",
+			"property blueprint .some-job.job-property is no longer configurable, values set by operators are lost",
+			"job type removed-job was removed, its VMs and persistent disks are deleted on upgrade",
+			"stemcell_criteria os changed from ubuntu-xenial to ubuntu-jammy, every VM is recreated on the new OS",
+		}))
+	})
+
+	It("does not count a migration referencing a property with a longer name", func() {
+		migrations := map[string]string{"201801010000_other.js": `input.properties[".properties.removed-property-2"]`}
+
+		Expect(CheckUpgrade(previous, current, nil, migrations)).To(ContainElement(UpgradeHazard{
+			Kind:    HazardRemovedPropertyBlueprint,
+			Name:    ".properties.removed-property",
+			Message: "property blueprint .properties.removed-property was removed and no migration references it",
+		}))
+	})
+
+	It("does not count a migration referencing only a parent property", func() {
+		migrations := map[string]string{"201801010000_selector.js": `input.properties[".properties.some-selector"]`}
+
+		Expect(CheckUpgrade(previous, current, nil, migrations)).To(ContainElement(UpgradeHazard{
+			Kind:    HazardRemovedPropertyBlueprint,
+			Name:    ".properties.some-selector.some-option.removed-option-property",
+			Message: "property blueprint .properties.some-selector.some-option.removed-option-property was removed and no migration references it",
+		}))
+	})
+
+	It("does not count a migration the previous tile already has", func() {
+		migration := `delete input.properties[".properties.removed-property"];`
+		previousMigrations := map[string]string{"201801010000_remove.js": migration}
+		migrations := map[string]string{"201801010000_remove.js": migration}
+
+		Expect(CheckUpgrade(previous, current, previousMigrations, migrations)).To(ContainElement(UpgradeHazard{
+			Kind:    HazardRemovedPropertyBlueprint,
+			Name:    ".properties.removed-property",
+			Message: "property blueprint .properties.removed-property was removed and no migration references it",
+		}))
+
+		migrations["201901010000_remove_again.js"] = migration
+		Expect(messages(CheckUpgrade(previous, current, previousMigrations, migrations))).NotTo(ContainElement(
+			"property blueprint .properties.removed-property was removed and no migration references it",
+		))
+	})
+
+	Context("when minimum_version_for_upgrade is inconsistent with the versions", func() {
+		It("reports a minimum version greater than the product version", func() {
+			current.MinimumVersionForUpgrade = "1.3.0"
+
+			Expect(messages(CheckUpgrade(current, current, nil, nil))).To(Equal([]string{
+				"minimum_version_for_upgrade 1.3.0 is greater than product_version 1.2.0",
+			}))
+		})
+
+		It("reports a minimum version greater than the previous product version", func() {
+			current.MinimumVersionForUpgrade = "1.2.0"
+			previous = current
+			previous.ProductVersion = "1.1.5"
+
+			Expect(messages(CheckUpgrade(previous, current, nil, nil))).To(Equal([]string{
+				"minimum_version_for_upgrade 1.2.0 is greater than the previous product_version 1.1.5, which cannot be upgraded",
+			}))
+		})
+
+		It("reports a minimum version that is not a semantic version", func() {
+			current.MinimumVersionForUpgrade = "banana"
+
+			Expect(messages(CheckUpgrade(current, current, nil, nil))).To(Equal([]string{
+				`minimum_version_for_upgrade "banana" is not a semantic version`,
+			}))
+		})
+	})
+})
+
+var _ = Describe("DetectPropertyRenames", func() {
+	It("pairs removed and added property blueprints with the same type, options and parent", func() {
+		previous := ProductTemplate{PropertyBlueprints: PropertyBlueprints{
+			SimplePropertyBlueprint{Name: "old-name", Type: "string"},
+			SimplePropertyBlueprint{Name: "old-port", Type: "port"},
+			SimplePropertyBlueprint{Name: "first-ambiguous", Type: "boolean"},
+			SimplePropertyBlueprint{Name: "second-ambiguous", Type: "boolean"},
+			SimplePropertyBlueprint{Name: "old-dropdown", Type: "dropdown_select", Options: []PropertyBlueprintOption{{Name: "a"}}},
+		}}
+		current := ProductTemplate{PropertyBlueprints: PropertyBlueprints{
+			SimplePropertyBlueprint{Name: "new-name", Type: "string"},
+			SimplePropertyBlueprint{Name: "new-port", Type: "port"},
+			SimplePropertyBlueprint{Name: "new-ambiguous", Type: "boolean"},
+			SimplePropertyBlueprint{Name: "new-dropdown", Type: "dropdown_select", Options: []PropertyBlueprintOption{{Name: "b"}}},
+		}}
+
+		Expect(DetectPropertyRenames(previous, current)).To(Equal([]PropertyRename{
+			{From: ".properties.old-name", To: ".properties.new-name", Type: "string"},
+			{From: ".properties.old-port", To: ".properties.new-port", Type: "port"},
+		}))
+	})
+})
@@ -0,0 +1,238 @@
+package tiletest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf/kiln/internal/cargo"
+)
+
+// Deployment is the rendered BOSH deployment of a tile and the runtime
+// configs that come with it. Manifest values are maps keyed by strings,
+// slices and scalars.
+type Deployment struct {
+	Name           string
+	InstanceGroups []InstanceGroup
+	RuntimeConfigs []RuntimeConfig
+}
+
+type InstanceGroup struct {
+	Name           string
+	Lifecycle      string
+	Instances      int
+	AZs            []string
+	VMType         string
+	PersistentDisk int
+	Networks       []string
+	StaticIPs      []string
+	Jobs           []Job
+	Properties     map[string]interface{}
+}
+
+type Job struct {
+	Name       string
+	Release    string
+	Provides   map[string]Link
+	Consumes   map[string]Link
+	Properties map[string]interface{}
+}
+
+// Link is a link a job provides or consumes. Disabled is true when the link
+// is explicitly set to nil.
+type Link struct {
+	As          string
+	From        string
+	Deployment  string
+	Network     string
+	Shared      bool
+	IPAddresses bool
+	Disabled    bool
+}
+
+type RuntimeConfig struct {
+	Name     string
+	Manifest map[string]interface{}
+}
+
+// InstanceGroup returns the instance group of the job type with the name.
+func (d Deployment) InstanceGroup(name string) (InstanceGroup, bool) {
+	for _, instanceGroup := range d.InstanceGroups {
+		if instanceGroup.Name == name {
+			return instanceGroup, true
+		}
+	}
+	return InstanceGroup{}, false
+}
+
+// Job returns the job of the template with the name in the instance group.
+func (d Deployment) Job(instanceGroup, name string) (Job, bool) {
+	ig, ok := d.InstanceGroup(instanceGroup)
+	if !ok {
+		return Job{}, false
+	}
+	return ig.Job(name)
+}
+
+// RuntimeConfig returns the runtime config with the name.
+func (d Deployment) RuntimeConfig(name string) (RuntimeConfig, bool) {
+	for _, runtimeConfig := range d.RuntimeConfigs {
+		if runtimeConfig.Name == name {
+			return runtimeConfig, true
+		}
+	}
+	return RuntimeConfig{}, false
+}
+
+// Job returns the job of the template with the name.
+func (ig InstanceGroup) Job(name string) (Job, bool) {
+	for _, job := range ig.Jobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return Job{}, false
+}
+
+// Property returns the instance group property at the dotted path, e.g.
+// "tls.enabled".
+func (ig InstanceGroup) Property(path string) (interface{}, error) {
+	return lookup(ig.Properties, path)
+}
+
+// Property returns the job property at the dotted path, e.g. "tls.enabled".
+func (j Job) Property(path string) (interface{}, error) {
+	return lookup(j.Properties, path)
+}
+
+func lookup(properties map[string]interface{}, path string) (interface{}, error) {
+	var value interface{} = properties
+
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("property %q not found", path)
+		}
+
+		value, ok = m[key]
+		if !ok {
+			return nil, fmt.Errorf("property %q not found", path)
+		}
+	}
+
+	return value, nil
+}
+
+func newDeployment(manifest cargo.Manifest, runtimeConfigs []cargo.RuntimeConfig) (Deployment, error) {
+	deployment := Deployment{Name: manifest.Name}
+
+	for _, ig := range manifest.InstanceGroups {
+		instanceGroup := InstanceGroup{
+			Name:           ig.Name,
+			Lifecycle:      ig.Lifecycle,
+			Instances:      ig.Instances,
+			AZs:            ig.AZs,
+			VMType:         ig.VMType,
+			PersistentDisk: ig.PersistentDisk,
+			Properties:     stringKeys(ig.Properties),
+		}
+
+		for _, network := range ig.Networks {
+			instanceGroup.Networks = append(instanceGroup.Networks, network.Name)
+			instanceGroup.StaticIPs = append(instanceGroup.StaticIPs, network.StaticIPs...)
+		}
+
+		for _, j := range ig.Jobs {
+			provides, err := links(j.Provides)
+			if err != nil {
+				return Deployment{}, fmt.Errorf("invalid provides of job %q in instance group %q: %w", j.Name, ig.Name, err)
+			}
+
+			consumes, err := links(j.Consumes)
+			if err != nil {
+				return Deployment{}, fmt.Errorf("invalid consumes of job %q in instance group %q: %w", j.Name, ig.Name, err)
+			}
+
+			instanceGroup.Jobs = append(instanceGroup.Jobs, Job{
+				Name:       j.Name,
+				Release:    j.Release,
+				Provides:   provides,
+				Consumes:   consumes,
+				Properties: stringKeys(j.Properties),
+			})
+		}
+
+		deployment.InstanceGroups = append(deployment.InstanceGroups, instanceGroup)
+	}
+
+	for _, runtimeConfig := range runtimeConfigs {
+		deployment.RuntimeConfigs = append(deployment.RuntimeConfigs, RuntimeConfig{
+			Name:     runtimeConfig.Name,
+			Manifest: stringKeys(runtimeConfig.RuntimeConfig),
+		})
+	}
+
+	return deployment, nil
+}
+
+func links(node interface{}) (map[string]Link, error) {
+	result := map[string]Link{}
+
+	for name, value := range stringKeys(node) {
+		if value == nil || value == "nil" {
+			result[name] = Link{Disabled: true}
+			continue
+		}
+
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("link %q is not a map", name)
+		}
+
+		var link Link
+		for field, target := range map[string]*string{
+			"as":         &link.As,
+			"from":       &link.From,
+			"deployment": &link.Deployment,
+			"network":    &link.Network,
+		} {
+			if v, ok := fields[field]; ok {
+				*target = fmt.Sprint(v)
+			}
+		}
+		link.Shared, _ = fields["shared"].(bool)
+		link.IPAddresses, _ = fields["ip_addresses"].(bool)
+
+		result[name] = link
+	}
+
+	return result, nil
+}
+
+// stringKeys converts the maps of an unmarshalled manifest to maps keyed by
+// strings. It returns nil for anything but a map.
+func stringKeys(node interface{}) map[string]interface{} {
+	m, ok := convertKeys(node).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return m
+}
+
+func convertKeys(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for key, value := range n {
+			result[fmt.Sprint(key)] = convertKeys(value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(n))
+		for _, value := range n {
+			result = append(result, convertKeys(value))
+		}
+		return result
+	default:
+		return node
+	}
+}
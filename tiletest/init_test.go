@@ -0,0 +1,15 @@
+package tiletest_test
+
+import (
+	"testing"
+
+	"github.com/matt-royal/biloba"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTiletest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithCustomReporters(t, "tiletest", biloba.DefaultReporters())
+}
@@ -0,0 +1,184 @@
+// Package tiletest renders the manifests of a tile with a set of property
+// values, the way Ops Manager would, so that tile teams can test their
+// metadata with go test instead of a deployment:
+//
+//	tile, err := tiletest.Load("metadata.yml")
+//	...
+//	deployment, err := tile.Render(tiletest.Config{
+//		Properties: map[string]interface{}{
+//			".properties.tls": "enabled",
+//		},
+//	})
+//	...
+//	job, _ := deployment.Job("router", "gorouter")
+//	enabled, err := job.Property("tls.enabled")
+package tiletest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pivotal-cf/kiln/internal/cargo"
+	"github.com/pivotal-cf/kiln/internal/cargo/opsman"
+	"github.com/pivotal-cf/kiln/internal/tile"
+	"github.com/pivotal-cf/kiln/proofing"
+	"gopkg.in/src-d/go-billy.v4/osfs"
+)
+
+// Tile is the parsed metadata of a tile.
+type Tile struct {
+	template proofing.ProductTemplate
+}
+
+// Config holds what an operator would configure in Ops Manager.
+type Config struct {
+	// DeploymentName defaults to the product name.
+	DeploymentName    string
+	Network           string
+	AvailabilityZones []string
+
+	// Properties are the values of the property blueprints keyed by their
+	// accessor without the trailing ".value", e.g. ".properties.some-property"
+	// or ".some-job.some-property". Unset properties take the default of their
+	// blueprint.
+	Properties map[string]interface{}
+
+	// ResourceConfigs are keyed by job type name. Job types without a resource
+	// config get the default number of instances of their instance definition.
+	ResourceConfigs map[string]ResourceConfig
+
+	// Self and Director hold the values of the $self.* and $director.*
+	// accessors, keyed by the name after "$self." or "$director.", and
+	// OtherProducts the values of accessors into other products, keyed by the
+	// whole accessor, e.g. "..cf.properties.system_domain.value".
+	// $self.deployment_name and $self.service_network default to
+	// DeploymentName and Network.
+	Self          map[string]interface{}
+	Director      map[string]interface{}
+	OtherProducts map[string]interface{}
+}
+
+// ResourceConfig overrides the resources of a job type. A nil Instances keeps
+// the default of the instance definition, like "automatic" in Ops Manager.
+type ResourceConfig struct {
+	Instances      *int
+	VMType         string
+	PersistentDisk int
+	StaticIPs      []string
+}
+
+// Load reads the metadata from a baked metadata file or a .pivotal file.
+func Load(path string) (Tile, error) {
+	var (
+		metadata []byte
+		err      error
+	)
+
+	if filepath.Ext(path) == ".pivotal" {
+		metadata, err = tile.ReadMetadata(osfs.New(""), path)
+	} else {
+		metadata, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return Tile{}, fmt.Errorf("could not read metadata from %q: %w", path, err)
+	}
+
+	return Parse(metadata)
+}
+
+// Parse parses baked metadata.
+func Parse(metadata []byte) (Tile, error) {
+	template, err := proofing.Parse(bytes.NewReader(metadata))
+	if err != nil {
+		return Tile{}, fmt.Errorf("could not parse metadata: %w", err)
+	}
+
+	return Tile{template: template}, nil
+}
+
+// ProductTemplate returns the parsed metadata.
+func (t Tile) ProductTemplate() proofing.ProductTemplate {
+	return t.template
+}
+
+// Validate runs the proofing validations of the metadata: releases must have
+// a name, file and version, and every property accessor must resolve to a
+// property blueprint.
+func (t Tile) Validate() error {
+	var problems []string
+
+	for _, release := range t.template.Releases {
+		if err := release.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("release %q: %s", release.Name, err))
+		}
+	}
+
+	for _, reference := range t.template.CheckPropertyReferences().UndefinedReferences {
+		problems = append(problems, fmt.Sprintf("%s references undefined property %s", reference.Source, reference.Accessor))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("metadata is invalid:\n- %s", strings.Join(problems, "\n- "))
+	}
+
+	return nil
+}
+
+// Render evaluates the manifests of the job types and runtime configs with
+// the configuration.
+func (t Tile) Render(config Config) (Deployment, error) {
+	opsManagerConfig := cargo.OpsManagerConfig{
+		DeploymentName:    config.DeploymentName,
+		AvailabilityZones: config.AvailabilityZones,
+		Network:           config.Network,
+		Properties:        config.Properties,
+		Self:              config.Self,
+		Director:          config.Director,
+		OtherProducts:     config.OtherProducts,
+	}
+
+	if opsManagerConfig.DeploymentName == "" {
+		opsManagerConfig.DeploymentName = t.template.Name
+	}
+
+	jobTypes := map[string]bool{}
+	for _, jobType := range t.template.JobTypes {
+		jobTypes[jobType.Name] = true
+	}
+
+	for name, rc := range config.ResourceConfigs {
+		if !jobTypes[name] {
+			return Deployment{}, fmt.Errorf("resource config %q does not match a job type", name)
+		}
+
+		instances := opsman.ResourceConfigInstances{Value: -1}
+		if rc.Instances != nil {
+			instances.Value = *rc.Instances
+		}
+
+		opsManagerConfig.ResourceConfigs = append(opsManagerConfig.ResourceConfigs, opsman.ResourceConfig{
+			Name:           name,
+			Instances:      instances,
+			VMType:         rc.VMType,
+			PersistentDisk: rc.PersistentDisk,
+			StaticIPs:      rc.StaticIPs,
+		})
+	}
+
+	generator := cargo.NewGenerator()
+
+	manifest, err := generator.Execute(t.template, opsManagerConfig)
+	if err != nil {
+		return Deployment{}, err
+	}
+
+	runtimeConfigs, err := generator.RuntimeConfigs(t.template, opsManagerConfig)
+	if err != nil {
+		return Deployment{}, err
+	}
+
+	return newDeployment(manifest, runtimeConfigs)
+}
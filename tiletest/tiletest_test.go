@@ -0,0 +1,176 @@
+package tiletest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-cf/kiln/tiletest"
+)
+
+var _ = Describe("Tile", func() {
+	var tile Tile
+
+	BeforeEach(func() {
+		var err error
+		tile, err = Load("fixtures/metadata.yml")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Render", func() {
+		It("renders the instance groups, jobs, links and runtime configs", func() {
+			instances := 3
+			deployment, err := tile.Render(Config{
+				Network: "some-network",
+				Properties: map[string]interface{}{
+					".properties.hostname": "example.com",
+				},
+				ResourceConfigs: map[string]ResourceConfig{
+					"server": {Instances: &instances, VMType: "large"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deployment.Name).To(Equal("some-product"))
+
+			server, ok := deployment.InstanceGroup("server")
+			Expect(ok).To(BeTrue())
+			Expect(server.Instances).To(Equal(3))
+			Expect(server.VMType).To(Equal("large"))
+			Expect(server.Networks).To(Equal([]string{"some-network"}))
+			Expect(server.Property("bpm.enabled")).To(BeTrue())
+
+			web, ok := deployment.Job("server", "web")
+			Expect(ok).To(BeTrue())
+			Expect(web.Release).To(Equal("some-release"))
+			Expect(web.Property("url")).To(Equal("https://example.com"))
+			Expect(web.Property("tls.enabled")).To(BeFalse())
+			Expect(web.Provides).To(Equal(map[string]Link{"web": {As: "some-web", Shared: true}}))
+			Expect(web.Consumes).To(Equal(map[string]Link{"database": {Disabled: true}}))
+
+			runtimeConfig, ok := deployment.RuntimeConfig("some-runtime-config")
+			Expect(ok).To(BeTrue())
+			Expect(runtimeConfig.Manifest).To(Equal(map[string]interface{}{
+				"addons": []interface{}{
+					map[string]interface{}{
+						"name":       "some-addon",
+						"properties": map[string]interface{}{"hostname": "example.com"},
+					},
+				},
+			}))
+		})
+
+		It("renders the selected option of a selector", func() {
+			deployment, err := tile.Render(Config{
+				Properties: map[string]interface{}{
+					".properties.hostname": "example.com",
+					".properties.tls":      "Enabled",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			web, _ := deployment.Job("server", "web")
+			Expect(web.Property("tls.enabled")).To(BeTrue())
+
+			_, err = web.Property("tls.missing")
+			Expect(err).To(MatchError(`property "tls.missing" not found`))
+		})
+
+		It("evaluates the $self, $director and other product accessors with the configured values", func() {
+			tile, err := Parse([]byte(`---
+name: some-product
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "621.0"
+job_types:
+- name: server
+  templates:
+  - name: web
+    release: some-release
+    manifest: |
+      deployment: (( $self.deployment_name ))
+      client_secret: (( $self.uaa_client_secret ))
+      director: (( $director.hostname ))
+      system_domain: (( ..cf.properties.system_domain.value ))
+`))
+			Expect(err).NotTo(HaveOccurred())
+
+			deployment, err := tile.Render(Config{
+				Self:          map[string]interface{}{"uaa_client_secret": "some-secret"},
+				Director:      map[string]interface{}{"hostname": "director.example.com"},
+				OtherProducts: map[string]interface{}{"..cf.properties.system_domain.value": "sys.example.com"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			web, _ := deployment.Job("server", "web")
+			Expect(web.Property("deployment")).To(Equal("some-product"))
+			Expect(web.Property("client_secret")).To(Equal("some-secret"))
+			Expect(web.Property("director")).To(Equal("director.example.com"))
+			Expect(web.Property("system_domain")).To(Equal("sys.example.com"))
+		})
+
+		Context("when a resource config does not set the instances", func() {
+			It("keeps the default of the instance definition", func() {
+				deployment, err := tile.Render(Config{
+					Properties: map[string]interface{}{
+						".properties.hostname": "example.com",
+					},
+					ResourceConfigs: map[string]ResourceConfig{
+						"server": {VMType: "large"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				server, _ := deployment.InstanceGroup("server")
+				Expect(server.Instances).To(Equal(1))
+				Expect(server.VMType).To(Equal("large"))
+			})
+		})
+
+		Context("when a required property has no value", func() {
+			It("returns an error", func() {
+				_, err := tile.Render(Config{})
+				Expect(err).To(MatchError(ContainSubstring(`property ".properties.hostname" is required but has no value`)))
+			})
+		})
+
+		Context("when a resource config does not match a job type", func() {
+			It("returns an error", func() {
+				_, err := tile.Render(Config{
+					ResourceConfigs: map[string]ResourceConfig{"missing": {VMType: "large"}},
+				})
+				Expect(err).To(MatchError(`resource config "missing" does not match a job type`))
+			})
+		})
+	})
+
+	Describe("Validate", func() {
+		It("succeeds for valid metadata", func() {
+			Expect(tile.Validate()).To(Succeed())
+		})
+
+		Context("when the metadata is invalid", func() {
+			It("returns every problem", func() {
+				tile, err := Parse([]byte(`---
+releases:
+- name: some-release
+  version: 1.2.3
+job_types:
+- name: server
+  manifest: |
+    url: (( .properties.missing.value ))
+`))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tile.Validate()).To(MatchError("metadata is invalid:\n" +
+					"- release \"some-release\": release file must be present\n" +
+					"- job_types[server].manifest references undefined property .properties.missing.value"))
+			})
+		})
+	})
+
+	Context("when the metadata file does not exist", func() {
+		It("returns an error", func() {
+			_, err := Load("fixtures/missing.yml")
+			Expect(err).To(MatchError(ContainSubstring(`could not read metadata from "fixtures/missing.yml"`)))
+		})
+	})
+})